@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	// workspace time zones must load on hosts without a zoneinfo database
+	_ "time/tzdata"
 
 	"github.com/mirzakhany/pm/internal"
 	"github.com/mirzakhany/pm/pkg/kv"
@@ -14,6 +16,7 @@ import (
 	"github.com/mirzakhany/pm/pkg/db"
 	"github.com/mirzakhany/pm/pkg/grpcgw"
 	"github.com/mirzakhany/pm/pkg/log"
+	"github.com/mirzakhany/pm/pkg/mail"
 )
 
 var (
@@ -51,12 +54,14 @@ func main() {
 		panic(err)
 	}
 
+	mail.Init()
+
 	database, err := db.Init(ctx)
 	if err != nil {
 		panic(err)
 	}
 
-	err = internal.Setup(database)
+	err = internal.Setup(ctx, database)
 	if err != nil {
 		panic(err)
 	}
@@ -4,8 +4,10 @@ go 1.15
 
 require (
 	github.com/alicebob/miniredis/v2 v2.13.3
+	github.com/casbin/casbin/v2 v2.44.2
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/fsnotify/fsnotify v1.4.9
+	github.com/go-ldap/ldap/v3 v3.3.0
 	github.com/go-ozzo/ozzo-validation/v4 v4.2.2
 	github.com/go-pg/pg v8.0.7+incompatible
 	github.com/go-pg/pg/v10 v10.3.1
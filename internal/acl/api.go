@@ -0,0 +1,64 @@
+package acl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/grpcgw"
+	"github.com/mirzakhany/pm/protobuf/acl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type API interface {
+	grpcgw.Controller
+	acl.ACLServiceServer
+}
+
+type api struct {
+	service Service
+}
+
+func (a api) InitRest(ctx context.Context, conn *grpc.ClientConn, mux *runtime.ServeMux) {
+	cl := acl.NewACLServiceClient(conn)
+	_ = acl.RegisterACLServiceHandlerClient(ctx, mux, cl)
+}
+
+func (a api) InitGrpc(ctx context.Context, server *grpc.Server) {
+	acl.RegisterACLServiceServer(server, a)
+}
+
+func (a api) GetObjectACL(ctx context.Context, request *acl.GetObjectACLRequest) (*acl.ObjectACL, error) {
+	res, err := a.service.Get(ctx, request.ObjectType, request.ObjectUuid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) SetObjectACL(ctx context.Context, request *acl.ObjectACL) (*acl.ObjectACL, error) {
+	res, err := a.service.Set(ctx, request)
+	if err != nil {
+		if errors.Is(err, ErrReadOnly) || err == errLockout {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func New(srv Service) API {
+	s := api{service: srv}
+	grpcgw.RegisterController(s)
+	return s
+}
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/aclV1.ACLService/GetObjectACL": auth.Require("acls", "get"),
+		"/aclV1.ACLService/SetObjectACL": auth.Require("acls", "update"),
+	})
+}
@@ -0,0 +1,78 @@
+package acl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+)
+
+// Types of the objects with access control lists, named after their tables
+const (
+	ObjectIssues = "issues"
+	ObjectCycles = "cycles"
+)
+
+// ErrReadOnly is returned when the access control list of an object does not let the current user edit it
+var ErrReadOnly = errors.New("not allowed to edit the object")
+
+// The conditions below take the object type as ?0, the object ID as ?1,
+// the UUID of the current user as ?2 and the domain of the current workspace as ?3.
+const (
+	// subjectCondition matches the entries given to the user or to one of their roles in the workspace
+	subjectCondition = "(oa.subject_type = 'user' AND oa.subject_uuid = ?2) OR " +
+		"(oa.subject_type = 'role' AND EXISTS (SELECT 1 FROM casbin_rules cr " +
+		"WHERE cr.ptype = 'g' AND cr.v0 = ?2 AND cr.v1 = 'role:' || oa.subject_uuid AND cr.v2 = ?3))"
+
+	// visibleCondition is true if the object has no view entries or the user has any of its entries
+	visibleCondition = "(NOT EXISTS (SELECT 1 FROM object_acls oa " +
+		"WHERE oa.object_type = ?0 AND oa.object_id = ?1 AND oa.access = 'view') OR " +
+		"EXISTS (SELECT 1 FROM object_acls oa " +
+		"WHERE oa.object_type = ?0 AND oa.object_id = ?1 AND (" + subjectCondition + ")))"
+
+	// editableCondition is true if the object has no edit entries or the user has one of them
+	editableCondition = "(NOT EXISTS (SELECT 1 FROM object_acls oa " +
+		"WHERE oa.object_type = ?0 AND oa.object_id = ?1 AND oa.access = 'edit') OR " +
+		"EXISTS (SELECT 1 FROM object_acls oa " +
+		"WHERE oa.object_type = ?0 AND oa.object_id = ?1 AND oa.access = 'edit' AND (" + subjectCondition + ")))"
+)
+
+// principal returns the UUID of the current user and the domain of the current workspace.
+// Without a user only the objects without restrictions are accessible.
+func principal(ctx context.Context) (string, string) {
+	var userUUID, domain string
+	if user, err := auth.ExtractUser(ctx); err == nil {
+		userUUID = user.Uuid
+	}
+	if workspace, err := auth.ExtractWorkspace(ctx); err == nil {
+		domain = workspace.Domain
+	}
+	return userUUID, domain
+}
+
+// Visible filters the query to the objects of the type the current user can view,
+// idColumn being the column of the object IDs in the query.
+func Visible(ctx context.Context, q *orm.Query, objectType, idColumn string) *orm.Query {
+	userUUID, domain := principal(ctx)
+	return q.Where(visibleCondition, objectType, pg.Safe(idColumn), userUUID, domain)
+}
+
+// Editable returns true if the current user can edit the object of the type with the specified ID.
+func Editable(ctx context.Context, db orm.DB, objectType string, objectID uint64) (bool, error) {
+	userUUID, domain := principal(ctx)
+	var ok bool
+	_, err := db.QueryOne(pg.Scan(&ok), "SELECT "+editableCondition, objectType, objectID, userUUID, domain)
+	return ok, err
+}
+
+// Remove deletes the access control list of the object of the type with the specified ID.
+func Remove(db orm.DB, objectType string, objectID uint64) error {
+	_, err := db.Model((*entity.ObjectACL)(nil)).
+		Where("object_type = ?", objectType).
+		Where("object_id = ?", objectID).
+		Delete()
+	return err
+}
@@ -0,0 +1,33 @@
+package acl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisible(t *testing.T) {
+	db := pg.Connect(&pg.Options{})
+	defer db.Close()
+	ctx := auth.ContextWithWorkspace(auth.ContextWithUser(context.Background(), &usersProto.User{Uuid: "u1"}),
+		&workspacesProto.Workspace{Id: 1, Domain: "foo.bar"})
+
+	q := Visible(ctx, db.Model((*entity.Issue)(nil)).Where("i.workspace_id = ?", 1), ObjectIssues, "i.id")
+	b, err := orm.NewSelectQuery(q).AppendQuery(orm.NewFormatter(), nil)
+	assert.Nil(t, err)
+	query := string(b)
+
+	// the conditions apply to the issue of each row for the current user and workspace
+	assert.Contains(t, query, "WHERE (i.workspace_id = 1) AND ((NOT EXISTS")
+	assert.Equal(t, 2, strings.Count(query, "oa.object_type = 'issues' AND oa.object_id = i.id"))
+	assert.Contains(t, query, "oa.subject_uuid = 'u1'")
+	assert.Contains(t, query, "cr.v0 = 'u1' AND cr.v1 = 'role:' || oa.subject_uuid AND cr.v2 = 'foo.bar'")
+}
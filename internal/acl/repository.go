@@ -0,0 +1,92 @@
+package acl
+
+import (
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/db"
+)
+
+// Repository encapsulates the logic to access the access control lists from the data source.
+type Repository interface {
+	// ObjectID returns the ID of the object of the type with the specified UUID, if the current user can view it.
+	ObjectID(ctx context.Context, objectType, uuid string) (uint64, error)
+	// Get returns the entries of the access control list of the object.
+	Get(ctx context.Context, objectType string, objectID uint64) ([]entity.ObjectACL, error)
+	// Replace replaces the entries of the access control list of the object.
+	Replace(ctx context.Context, objectType string, objectID uint64, entries []entity.ObjectACL) error
+	// Editable returns true if the current user can edit the object.
+	Editable(ctx context.Context, objectType string, objectID uint64) (bool, error)
+	// Transactional runs f in a transaction.
+	Transactional(ctx context.Context, f func(ctx context.Context) error) error
+}
+
+// repository persists access control lists in database
+type repository struct {
+	db *db.DB
+}
+
+// NewRepository creates a new access control list repository
+func NewRepository(db *db.DB) Repository {
+	return repository{db}
+}
+
+// ObjectID reads the ID of the object with the specified UUID in the current workspace from the database.
+func (r repository) ObjectID(ctx context.Context, objectType, uuid string) (uint64, error) {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var id uint64
+	q := r.db.With(ctx).Model().
+		TableExpr("? AS i", pg.Ident(objectType)).
+		Column("i.id").
+		Where("i.uuid = ?", uuid).
+		Where("i.workspace_id = ?", workspace.Id)
+	err = Visible(ctx, q, objectType, "i.id").Select(pg.Scan(&id))
+	return id, err
+}
+
+// Get reads the entries of the access control list of the object from the database.
+func (r repository) Get(ctx context.Context, objectType string, objectID uint64) ([]entity.ObjectACL, error) {
+	var entries []entity.ObjectACL
+	err := r.db.With(ctx).Model(&entries).
+		Where("object_type = ?", objectType).
+		Where("object_id = ?", objectID).
+		Order("id ASC").
+		Select()
+	return entries, err
+}
+
+// Replace deletes the entries of the access control list of the object and saves the given ones in the current workspace.
+func (r repository) Replace(ctx context.Context, objectType string, objectID uint64, entries []entity.ObjectACL) error {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	if err := Remove(r.db.With(ctx), objectType, objectID); err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	for i := range entries {
+		entries[i].WorkspaceID = workspace.Id
+		entries[i].ObjectType = objectType
+		entries[i].ObjectID = objectID
+	}
+	_, err = r.db.With(ctx).Model(&entries).Insert()
+	return err
+}
+
+// Editable checks the access control list of the object for the current user.
+func (r repository) Editable(ctx context.Context, objectType string, objectID uint64) (bool, error) {
+	return Editable(ctx, r.db.With(ctx), objectType, objectID)
+}
+
+// Transactional runs f in a database transaction.
+func (r repository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	return r.db.Transactional(ctx, f)
+}
@@ -0,0 +1,135 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/mirzakhany/pm/internal/entity"
+	aclProto "github.com/mirzakhany/pm/protobuf/acl"
+)
+
+// Service encapsulates use case logic for the access control lists of issues and cycles.
+type Service interface {
+	// Get returns the access control list of an object
+	Get(ctx context.Context, objectType, objectUUID string) (*aclProto.ObjectACL, error)
+	// Set replaces the access control list of an object
+	Set(ctx context.Context, input *aclProto.ObjectACL) (*aclProto.ObjectACL, error)
+}
+
+var errLockout = errors.New("the access control list must let you edit the object")
+
+var objectTypeRule = validation.In(ObjectIssues, ObjectCycles)
+
+// entriesRule validates the entries of an access control list.
+var entriesRule = validation.By(func(value interface{}) error {
+	entries, _ := value.([]*aclProto.ACLEntry)
+	for _, e := range entries {
+		if e == nil {
+			return errors.New("entries cannot be blank")
+		}
+		err := validation.ValidateStruct(e,
+			validation.Field(&e.SubjectType, validation.Required, validation.In(entity.ACLSubjectUser, entity.ACLSubjectRole)),
+			validation.Field(&e.SubjectUuid, validation.Required, is.UUID),
+			validation.Field(&e.Access, validation.Required, validation.In(entity.ACLAccessView, entity.ACLAccessEdit)),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+})
+
+// ValidateObjectACL validates the ObjectACL fields.
+func ValidateObjectACL(o *aclProto.ObjectACL) error {
+	return validation.ValidateStruct(o,
+		validation.Field(&o.ObjectType, validation.Required, objectTypeRule),
+		validation.Field(&o.ObjectUuid, validation.Required, is.UUID),
+		validation.Field(&o.Entries, entriesRule),
+	)
+}
+
+// ValidateGetRequest validates the GetObjectACLRequest fields.
+func ValidateGetRequest(g *aclProto.GetObjectACLRequest) error {
+	return validation.ValidateStruct(g,
+		validation.Field(&g.ObjectType, validation.Required, objectTypeRule),
+		validation.Field(&g.ObjectUuid, validation.Required, is.UUID),
+	)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new access control list service.
+func NewService(repo Repository) Service {
+	return service{repo}
+}
+
+// Get returns the access control list of the object, which must be visible to the current user.
+func (s service) Get(ctx context.Context, objectType, objectUUID string) (*aclProto.ObjectACL, error) {
+	if err := ValidateGetRequest(&aclProto.GetObjectACLRequest{ObjectType: objectType, ObjectUuid: objectUUID}); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.ObjectID(ctx, objectType, objectUUID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.Get(ctx, objectType, id)
+	if err != nil {
+		return nil, err
+	}
+	return &aclProto.ObjectACL{
+		ObjectType: objectType,
+		ObjectUuid: objectUUID,
+		Entries:    entity.ObjectACLToProtoList(entries),
+	}, nil
+}
+
+// Set replaces the access control list of the object, which must be editable by the current user
+// before and after the change.
+func (s service) Set(ctx context.Context, req *aclProto.ObjectACL) (*aclProto.ObjectACL, error) {
+	if err := ValidateObjectACL(req); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.ObjectID(ctx, req.ObjectType, req.ObjectUuid)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := s.repo.Editable(ctx, req.ObjectType, id); err != nil || !ok {
+		if err == nil {
+			err = ErrReadOnly
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	var entries []entity.ObjectACL
+	for _, e := range req.Entries {
+		entries = append(entries, entity.ObjectACL{
+			SubjectType: e.SubjectType,
+			SubjectUUID: e.SubjectUuid,
+			Access:      e.Access,
+			CreatedAt:   now,
+		})
+	}
+	err = s.repo.Transactional(ctx, func(ctx context.Context) error {
+		if err := s.repo.Replace(ctx, req.ObjectType, id, entries); err != nil {
+			return err
+		}
+		ok, err := s.repo.Editable(ctx, req.ObjectType, id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errLockout
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, req.ObjectType, req.ObjectUuid)
+}
@@ -0,0 +1,161 @@
+package acl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	aclProto "github.com/mirzakhany/pm/protobuf/acl"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateObjectACL(t *testing.T) {
+	Uuid := uuid.New().String()
+	entry := func(subjectType, access string) []*aclProto.ACLEntry {
+		return []*aclProto.ACLEntry{{SubjectType: subjectType, SubjectUuid: Uuid, Access: access}}
+	}
+	tests := []struct {
+		name      string
+		model     *aclProto.ObjectACL
+		wantError bool
+	}{
+		{"success", &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: Uuid, Entries: entry("user", "view")}, false},
+		{"no entries", &aclProto.ObjectACL{ObjectType: ObjectCycles, ObjectUuid: Uuid}, false},
+		{"role", &aclProto.ObjectACL{ObjectType: ObjectCycles, ObjectUuid: Uuid, Entries: entry("role", "edit")}, false},
+		{"object type", &aclProto.ObjectACL{ObjectType: "users", ObjectUuid: Uuid}, true},
+		{"object uuid", &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: "none"}, true},
+		{"subject type", &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: Uuid, Entries: entry("group", "view")}, true},
+		{"access", &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: Uuid, Entries: entry("user", "delete")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateObjectACL(tt.model)
+			assert.Equal(t, tt.wantError, err != nil)
+		})
+	}
+}
+
+func Test_service_ACL(t *testing.T) {
+	issueUUID := uuid.New().String()
+	owner := &usersProto.User{Uuid: uuid.New().String()}
+	other := &usersProto.User{Uuid: uuid.New().String()}
+	repo := &mockRepository{objects: map[string]uint64{issueUUID: 1}}
+	s := NewService(repo)
+	asOwner := auth.ContextWithUser(context.Background(), owner)
+	asOther := auth.ContextWithUser(context.Background(), other)
+
+	res, err := s.Get(asOther, ObjectIssues, issueUUID)
+	assert.Nil(t, err)
+	assert.Empty(t, res.Entries)
+
+	// an access control list must let its author edit the object
+	_, err = s.Set(asOwner, &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: issueUUID, Entries: []*aclProto.ACLEntry{
+		{SubjectType: entity.ACLSubjectUser, SubjectUuid: other.Uuid, Access: entity.ACLAccessEdit},
+	}})
+	assert.Equal(t, errLockout, err)
+	assert.Empty(t, repo.entries)
+
+	// private issue editable by its owner only
+	res, err = s.Set(asOwner, &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: issueUUID, Entries: []*aclProto.ACLEntry{
+		{SubjectType: entity.ACLSubjectUser, SubjectUuid: owner.Uuid, Access: entity.ACLAccessEdit},
+		{SubjectType: entity.ACLSubjectUser, SubjectUuid: other.Uuid, Access: entity.ACLAccessView},
+	}})
+	assert.Nil(t, err)
+	assert.Len(t, res.Entries, 2)
+
+	res, err = s.Get(asOther, ObjectIssues, issueUUID)
+	assert.Nil(t, err)
+	assert.Len(t, res.Entries, 2)
+	_, err = s.Set(asOther, &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: issueUUID})
+	assert.Equal(t, ErrReadOnly, err)
+
+	// hidden from the users without entries
+	_, err = s.Get(context.Background(), ObjectIssues, issueUUID)
+	assert.Equal(t, pg.ErrNoRows, err)
+
+	// an empty list removes the restrictions
+	res, err = s.Set(asOwner, &aclProto.ObjectACL{ObjectType: ObjectIssues, ObjectUuid: issueUUID})
+	assert.Nil(t, err)
+	assert.Empty(t, res.Entries)
+	_, err = s.Get(context.Background(), ObjectIssues, issueUUID)
+	assert.Nil(t, err)
+}
+
+// mockRepository keeps the entries of the objects with the same rules as the database,
+// without the entries given to roles.
+type mockRepository struct {
+	objects map[string]uint64
+	entries []entity.ObjectACL
+}
+
+func (m *mockRepository) matches(ctx context.Context, objectID uint64, access string) (restricted, matched bool) {
+	userUUID, _ := principal(ctx)
+	for _, e := range m.entries {
+		if e.ObjectID != objectID {
+			continue
+		}
+		if e.Access == access {
+			restricted = true
+		}
+		if e.SubjectUUID == userUUID && (access == entity.ACLAccessView || e.Access == access) {
+			matched = true
+		}
+	}
+	return restricted, matched
+}
+
+func (m *mockRepository) ObjectID(ctx context.Context, objectType, uuid string) (uint64, error) {
+	id, ok := m.objects[uuid]
+	if !ok {
+		return 0, pg.ErrNoRows
+	}
+	if restricted, matched := m.matches(ctx, id, entity.ACLAccessView); restricted && !matched {
+		return 0, pg.ErrNoRows
+	}
+	return id, nil
+}
+
+func (m *mockRepository) Get(ctx context.Context, objectType string, objectID uint64) ([]entity.ObjectACL, error) {
+	var entries []entity.ObjectACL
+	for _, e := range m.entries {
+		if e.ObjectType == objectType && e.ObjectID == objectID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (m *mockRepository) Replace(ctx context.Context, objectType string, objectID uint64, entries []entity.ObjectACL) error {
+	var kept []entity.ObjectACL
+	for _, e := range m.entries {
+		if e.ObjectType != objectType || e.ObjectID != objectID {
+			kept = append(kept, e)
+		}
+	}
+	for _, e := range entries {
+		e.ObjectType = objectType
+		e.ObjectID = objectID
+		kept = append(kept, e)
+	}
+	m.entries = kept
+	return nil
+}
+
+func (m *mockRepository) Editable(ctx context.Context, objectType string, objectID uint64) (bool, error) {
+	restricted, matched := m.matches(ctx, objectID, entity.ACLAccessEdit)
+	return !restricted || matched, nil
+}
+
+// Transactional restores the entries if f fails.
+func (m *mockRepository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	entries := append([]entity.ObjectACL(nil), m.entries...)
+	err := f(ctx)
+	if err != nil {
+		m.entries = entries
+	}
+	return err
+}
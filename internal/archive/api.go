@@ -0,0 +1,70 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/grpcgw"
+	"github.com/mirzakhany/pm/protobuf/archive"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type API interface {
+	grpcgw.Controller
+	archive.ArchiveServiceServer
+}
+
+type api struct {
+	service Service
+}
+
+func (a api) InitRest(ctx context.Context, conn *grpc.ClientConn, mux *runtime.ServeMux) {
+	cl := archive.NewArchiveServiceClient(conn)
+	_ = archive.RegisterArchiveServiceHandlerClient(ctx, mux, cl)
+}
+
+func (a api) InitGrpc(ctx context.Context, server *grpc.Server) {
+	archive.RegisterArchiveServiceServer(server, a)
+}
+
+func (a api) ExportWorkspace(ctx context.Context, request *archive.ExportWorkspaceRequest) (*httpbody.HttpBody, error) {
+	// only the workspace the request is authorized in can be exported
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if request.WorkspaceUuid != "" && request.WorkspaceUuid != workspace.Uuid {
+		return nil, status.Error(codes.PermissionDenied, "the request is not authorized in the workspace")
+	}
+	data, err := a.service.Export(ctx, workspace.Uuid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &httpbody.HttpBody{ContentType: ContentType, Data: data}, nil
+}
+
+func (a api) ImportWorkspace(ctx context.Context, request *archive.ImportWorkspaceRequest) (*workspaces.Workspace, error) {
+	res, err := a.service.Import(ctx, request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func New(srv Service) API {
+	s := api{service: srv}
+	grpcgw.RegisterController(s)
+	return s
+}
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/archiveV1.ArchiveService/ExportWorkspace": auth.Require("archive", "export"),
+		"/archiveV1.ArchiveService/ImportWorkspace": auth.Authenticated,
+	})
+}
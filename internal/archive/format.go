@@ -0,0 +1,346 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version is the version of the archive format written by Encode.
+// Decode accepts archives up to this version.
+const Version = 1
+
+// maxLineSize is the longest record Decode accepts
+const maxLineSize = 4 << 20
+
+// Record types, an archive is a header line followed by one line per record
+const (
+	recordHeader     = "header"
+	recordUser       = "user"
+	recordRole       = "role"
+	recordMember     = "member"
+	recordSettings   = "settings"
+	recordStatus     = "status"
+	recordCycle      = "cycle"
+	recordCapacity   = "capacity"
+	recordIssue      = "issue"
+	recordTransition = "transition"
+)
+
+var (
+	errNoHeader    = errors.New("archive: the first record must be the header")
+	errVersion     = errors.New("archive: unsupported version")
+	errRecordType  = errors.New("unknown record type")
+	errEmptyRecord = errors.New("record without data")
+)
+
+// record is a line of the archive
+type record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Header is the first record of an archive
+type Header struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Workspace  WorkspaceRecord `json:"workspace"`
+}
+
+// WorkspaceRecord is the exported workspace
+type WorkspaceRecord struct {
+	UUID      string    `json:"uuid"`
+	Title     string    `json:"title"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserRecord is a user referenced by the workspace, passwords are never exported
+type UserRecord struct {
+	UUID      string    `json:"uuid"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Enable    bool      `json:"enable"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoleRecord is a role of the workspace
+type RoleRecord struct {
+	UUID  string `json:"uuid"`
+	Title string `json:"title"`
+	// Builtin roles are imported as the builtin role of the same title of the new workspace
+	Builtin   bool      `json:"builtin,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MemberRecord is a member of the workspace
+type MemberRecord struct {
+	UserUUID  string    `json:"user_uuid"`
+	RoleUUID  string    `json:"role_uuid,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SettingsRecord holds the settings of the workspace
+type SettingsRecord struct {
+	DefaultStatusUUID string    `json:"default_status_uuid,omitempty"`
+	EstimateScale     string    `json:"estimate_scale"`
+	TimeZone          string    `json:"time_zone"`
+	WeekStart         string    `json:"week_start"`
+	IssueKeyPrefix    string    `json:"issue_key_prefix"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// StatusRecord is an issue status of the workspace
+type StatusRecord struct {
+	UUID      string    `json:"uuid"`
+	Title     string    `json:"title"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CycleRecord is a cycle of the workspace
+type CycleRecord struct {
+	UUID        string    `json:"uuid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Goals       []string  `json:"goals,omitempty"`
+	StartAt     time.Time `json:"start_at"`
+	EndAt       time.Time `json:"end_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CapacityRecord is the capacity of a user in a cycle
+type CapacityRecord struct {
+	CycleUUID string    `json:"cycle_uuid"`
+	UserUUID  string    `json:"user_uuid"`
+	Capacity  uint64    `json:"capacity"`
+	Unit      string    `json:"unit"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IssueRecord is an issue of the workspace
+type IssueRecord struct {
+	UUID         string    `json:"uuid"`
+	Number       uint64    `json:"number"`
+	Key          string    `json:"key"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	StatusUUID   string    `json:"status_uuid,omitempty"`
+	CycleUUID    string    `json:"cycle_uuid,omitempty"`
+	Estimate     uint64    `json:"estimate"`
+	AssigneeUUID string    `json:"assignee_uuid,omitempty"`
+	CreatorUUID  string    `json:"creator_uuid,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TransitionRecord is a status or cycle change of an issue
+type TransitionRecord struct {
+	IssueUUID      string    `json:"issue_uuid"`
+	CycleUUID      string    `json:"cycle_uuid,omitempty"`
+	FromStatusUUID string    `json:"from_status_uuid,omitempty"`
+	ToStatusUUID   string    `json:"to_status_uuid,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Archive is the decoded content of a workspace archive. Records reference each other by UUID.
+type Archive struct {
+	Header      Header
+	Users       []UserRecord
+	Roles       []RoleRecord
+	Members     []MemberRecord
+	Settings    *SettingsRecord
+	Statuses    []StatusRecord
+	Cycles      []CycleRecord
+	Capacities  []CapacityRecord
+	Issues      []IssueRecord
+	Transitions []TransitionRecord
+}
+
+// Encode writes the archive as JSON lines, the header first and every record
+// after the records it references.
+func Encode(w io.Writer, a *Archive) error {
+	enc := &encoder{w: bufio.NewWriter(w)}
+	header := a.Header
+	header.Version = Version
+	enc.write(recordHeader, header)
+	for _, r := range a.Users {
+		enc.write(recordUser, r)
+	}
+	for _, r := range a.Roles {
+		enc.write(recordRole, r)
+	}
+	for _, r := range a.Members {
+		enc.write(recordMember, r)
+	}
+	for _, r := range a.Statuses {
+		enc.write(recordStatus, r)
+	}
+	if a.Settings != nil {
+		enc.write(recordSettings, a.Settings)
+	}
+	for _, r := range a.Cycles {
+		enc.write(recordCycle, r)
+	}
+	for _, r := range a.Capacities {
+		enc.write(recordCapacity, r)
+	}
+	for _, r := range a.Issues {
+		enc.write(recordIssue, r)
+	}
+	for _, r := range a.Transitions {
+		enc.write(recordTransition, r)
+	}
+	if enc.err != nil {
+		return enc.err
+	}
+	return enc.w.Flush()
+}
+
+// encoder writes records until the first error
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) write(recordType string, v interface{}) {
+	if e.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		e.err = err
+		return
+	}
+	line, err := json.Marshal(record{Type: recordType, Data: data})
+	if err != nil {
+		e.err = err
+		return
+	}
+	if _, err := e.w.Write(line); err != nil {
+		e.err = err
+		return
+	}
+	e.err = e.w.WriteByte('\n')
+}
+
+// Decode reads an archive written by Encode. Empty lines are skipped.
+func Decode(r io.Reader) (*Archive, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	a := &Archive{}
+	line := 0
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		line++
+
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("archive: line %d: %w", line, err)
+		}
+		if len(rec.Data) == 0 {
+			return nil, fmt.Errorf("archive: line %d: %w", line, errEmptyRecord)
+		}
+		if line == 1 {
+			if rec.Type != recordHeader {
+				return nil, errNoHeader
+			}
+			if err := json.Unmarshal(rec.Data, &a.Header); err != nil {
+				return nil, fmt.Errorf("archive: line %d: %w", line, err)
+			}
+			if a.Header.Version < 1 || a.Header.Version > Version {
+				return nil, fmt.Errorf("%w %d", errVersion, a.Header.Version)
+			}
+			continue
+		}
+		if err := a.decodeRecord(rec); err != nil {
+			return nil, fmt.Errorf("archive: line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if line == 0 {
+		return nil, errNoHeader
+	}
+	return a, nil
+}
+
+func (a *Archive) decodeRecord(rec record) error {
+	switch rec.Type {
+	case recordUser:
+		var r UserRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Users = append(a.Users, r)
+	case recordRole:
+		var r RoleRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Roles = append(a.Roles, r)
+	case recordMember:
+		var r MemberRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Members = append(a.Members, r)
+	case recordSettings:
+		var r SettingsRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Settings = &r
+	case recordStatus:
+		var r StatusRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Statuses = append(a.Statuses, r)
+	case recordCycle:
+		var r CycleRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Cycles = append(a.Cycles, r)
+	case recordCapacity:
+		var r CapacityRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Capacities = append(a.Capacities, r)
+	case recordIssue:
+		var r IssueRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Issues = append(a.Issues, r)
+	case recordTransition:
+		var r TransitionRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		a.Transitions = append(a.Transitions, r)
+	case recordHeader:
+		return errors.New("duplicate header")
+	default:
+		return fmt.Errorf("%w %q", errRecordType, rec.Type)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	a := &Archive{
+		Header:   Header{ExportedAt: now, Workspace: WorkspaceRecord{UUID: "w1", Title: "test", Domain: "test"}},
+		Users:    []UserRecord{{UUID: "u1", Username: "test", Email: "test@example.com", Enable: true}},
+		Statuses: []StatusRecord{{UUID: "s1", Title: "todo", Category: "unstarted"}},
+		Settings: &SettingsRecord{DefaultStatusUUID: "s1", TimeZone: "UTC"},
+		Issues:   []IssueRecord{{UUID: "i1", Number: 1, Key: "ISS-1", StatusUUID: "s1", CreatorUUID: "u1", CreatedAt: now}},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, Encode(&buf, a))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.True(t, strings.HasPrefix(lines[0], `{"type":"header","data":{"version":1,`))
+	assert.True(t, strings.HasPrefix(lines[3], `{"type":"settings"`))
+
+	decoded, err := Decode(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, Version, decoded.Header.Version)
+	assert.Equal(t, a.Header.Workspace, decoded.Header.Workspace)
+	assert.Equal(t, a.Users, decoded.Users)
+	assert.Equal(t, a.Statuses, decoded.Statuses)
+	assert.Equal(t, a.Settings, decoded.Settings)
+	assert.Equal(t, a.Issues, decoded.Issues)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	header := `{"type":"header","data":{"version":1}}` + "\n"
+	tests := []struct {
+		name    string
+		archive string
+		wantErr error
+	}{
+		{"empty", "", errNoHeader},
+		{"no header", `{"type":"user","data":{}}`, errNoHeader},
+		{"newer version", `{"type":"header","data":{"version":2}}`, errVersion},
+		{"unknown type", header + `{"type":"comment","data":{}}`, errRecordType},
+		{"no data", header + `{"type":"user"}`, errEmptyRecord},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.archive))
+			assert.True(t, errors.Is(err, tt.wantErr), err)
+		})
+	}
+
+	_, err := Decode(strings.NewReader(header + "not json"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,156 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/db"
+)
+
+// Snapshot holds all the rows belonging to a workspace
+type Snapshot struct {
+	Workspace   entity.Workspace
+	Settings    *entity.WorkspaceSettings
+	Users       []entity.User
+	Roles       []entity.Role
+	Members     []entity.WorkspaceMember
+	Statuses    []entity.IssueStatus
+	Cycles      []entity.Cycle
+	Capacities  []entity.CycleCapacity
+	Issues      []entity.Issue
+	Transitions []entity.IssueTransition
+}
+
+// Repository encapsulates the logic to read and write whole workspaces from the data source.
+type Repository interface {
+	// GetWorkspace returns the workspace with the specified UUID.
+	GetWorkspace(ctx context.Context, uuid string) (entity.Workspace, error)
+	// Snapshot returns the workspace with all the rows belonging to it.
+	Snapshot(ctx context.Context, workspace entity.Workspace) (Snapshot, error)
+	// SourceMembers returns the users who are members of the workspace with the specified UUID, none if it does not exist.
+	SourceMembers(ctx context.Context, workspaceUUID string) ([]entity.User, error)
+	// BuiltinRoles returns the builtin roles of the workspace with the specified ID.
+	BuiltinRoles(ctx context.Context, workspaceID uint64) ([]entity.Role, error)
+	// Insert saves a new row in the storage and sets its ID.
+	Insert(ctx context.Context, model interface{}) error
+	// Transactional runs f in a transaction.
+	Transactional(ctx context.Context, f func(ctx context.Context) error) error
+}
+
+// repository persists workspace archives in database
+type repository struct {
+	db *db.DB
+}
+
+// NewRepository creates a new archive repository
+func NewRepository(db *db.DB) Repository {
+	return repository{db}
+}
+
+// GetWorkspace reads the workspace with the specified UUID from the database.
+func (r repository) GetWorkspace(ctx context.Context, uuid string) (entity.Workspace, error) {
+	var workspace entity.Workspace
+	err := r.db.With(ctx).Model(&workspace).Where("uuid = ?", uuid).First()
+	return workspace, err
+}
+
+// Snapshot reads all the rows of the workspace from the database.
+func (r repository) Snapshot(ctx context.Context, workspace entity.Workspace) (Snapshot, error) {
+	s := Snapshot{Workspace: workspace}
+	tx := r.db.With(ctx)
+
+	var settings entity.WorkspaceSettings
+	err := tx.Model(&settings).Where("workspace_id = ?", workspace.ID).First()
+	switch {
+	case err == nil:
+		s.Settings = &settings
+	case err != pg.ErrNoRows:
+		return s, err
+	}
+
+	if err := tx.Model(&s.Roles).Where("workspace_id = ?", workspace.ID).Order("id").Select(); err != nil {
+		return s, err
+	}
+	if err := tx.Model(&s.Members).Where("workspace_id = ?", workspace.ID).Order("id").Select(); err != nil {
+		return s, err
+	}
+	if err := tx.Model(&s.Statuses).Where("workspace_id = ?", workspace.ID).Order("id").Select(); err != nil {
+		return s, err
+	}
+	if err := tx.Model(&s.Cycles).Where("workspace_id = ?", workspace.ID).Order("id").Select(); err != nil {
+		return s, err
+	}
+	err = tx.Model(&s.Capacities).
+		Join("JOIN cycles AS c ON c.id = cc.cycle_id").
+		Where("c.workspace_id = ?", workspace.ID).
+		Order("cc.id").Select()
+	if err != nil {
+		return s, err
+	}
+	if err := tx.Model(&s.Issues).Where("workspace_id = ?", workspace.ID).Order("id").Select(); err != nil {
+		return s, err
+	}
+	if err := tx.Model(&s.Transitions).Where("workspace_id = ?", workspace.ID).Order("id").Select(); err != nil {
+		return s, err
+	}
+
+	userIDs := referencedUsers(s)
+	if len(userIDs) > 0 {
+		if err := tx.Model(&s.Users).WhereIn("id IN (?)", userIDs).Order("id").Select(); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// SourceMembers reads the members of the workspace with the specified UUID from the database.
+func (r repository) SourceMembers(ctx context.Context, workspaceUUID string) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.With(ctx).Model(&users).
+		Where("id IN (SELECT wm.user_id FROM workspace_members AS wm JOIN workspaces AS w ON w.id = wm.workspace_id "+
+			"WHERE w.uuid = ? AND w.deleted_at IS NULL)", workspaceUUID).
+		Order("id").Select()
+	return users, err
+}
+
+// BuiltinRoles reads the builtin roles of the workspace from the database.
+func (r repository) BuiltinRoles(ctx context.Context, workspaceID uint64) ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.With(ctx).Model(&roles).Where("workspace_id = ?", workspaceID).Where("builtin").Order("id").Select()
+	return roles, err
+}
+
+// Insert saves a new row in the database.
+func (r repository) Insert(ctx context.Context, model interface{}) error {
+	_, err := r.db.With(ctx).Model(model).Insert()
+	return err
+}
+
+// Transactional runs f in a database transaction.
+func (r repository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	return r.db.Transactional(ctx, f)
+}
+
+// referencedUsers returns the IDs of the users the rows of the snapshot reference
+func referencedUsers(s Snapshot) []uint64 {
+	seen := make(map[uint64]bool)
+	var ids []uint64
+	add := func(id uint64) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, m := range s.Members {
+		add(m.UserID)
+	}
+	for _, c := range s.Capacities {
+		add(c.UserID)
+	}
+	for _, i := range s.Issues {
+		add(i.CreatorID)
+		add(i.AssigneeID)
+	}
+	return ids
+}
@@ -0,0 +1,523 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	workspacesSrv "github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	archiveProto "github.com/mirzakhany/pm/protobuf/archive"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+// ContentType is the content type of exported archives
+const ContentType = "application/x-ndjson"
+
+// Service exports workspaces to archives and imports them back as new workspaces.
+// Issue comments are not part of the archive as this version has no comments.
+type Service interface {
+	// Export returns the archive of the workspace with the specified UUID
+	Export(ctx context.Context, workspaceUUID string) ([]byte, error)
+	// Import creates a new workspace from an archive
+	Import(ctx context.Context, input *archiveProto.ImportWorkspaceRequest) (*workspacesProto.Workspace, error)
+}
+
+var errEmptyArchive = errors.New("archive is required")
+
+type service struct {
+	repo          Repository
+	workspacesSrv workspacesSrv.Service
+	hooks         []workspacesSrv.MembershipHook
+}
+
+// NewService creates a new archive service creating the imported workspaces with the workspaces service
+// and notifying the hooks of the members joining them.
+func NewService(repo Repository, workspaces workspacesSrv.Service, hooks ...workspacesSrv.MembershipHook) Service {
+	return service{repo, workspaces, hooks}
+}
+
+// Export returns the archive of the workspace with the specified UUID.
+func (s service) Export(ctx context.Context, workspaceUUID string) ([]byte, error) {
+	workspace, err := s.repo.GetWorkspace(ctx, workspaceUUID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.repo.Snapshot(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, buildArchive(snapshot, time.Now())); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// buildArchive converts the rows of a workspace to archive records, replacing IDs with UUIDs.
+// Transitions of issues that no longer exist are left out.
+func buildArchive(s Snapshot, now time.Time) *Archive {
+	a := &Archive{Header: Header{
+		Version:    Version,
+		ExportedAt: now,
+		Workspace: WorkspaceRecord{
+			UUID:      s.Workspace.UUID,
+			Title:     s.Workspace.Title,
+			Domain:    s.Workspace.Domain,
+			CreatedAt: s.Workspace.CreatedAt,
+			UpdatedAt: s.Workspace.UpdatedAt,
+		},
+	}}
+
+	users := make(map[uint64]string)
+	for _, u := range s.Users {
+		users[u.ID] = u.UUID
+		a.Users = append(a.Users, UserRecord{
+			UUID:      u.UUID,
+			Username:  u.Username,
+			Email:     u.Email,
+			Enable:    u.Enable,
+			CreatedAt: u.CreatedAt,
+			UpdatedAt: u.UpdatedAt,
+		})
+	}
+	roles := make(map[uint64]string)
+	for _, r := range s.Roles {
+		roles[r.ID] = r.UUID
+		a.Roles = append(a.Roles, RoleRecord{
+			UUID:      r.UUID,
+			Title:     r.Title,
+			Builtin:   r.Builtin,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		})
+	}
+	for _, m := range s.Members {
+		a.Members = append(a.Members, MemberRecord{
+			UserUUID:  users[m.UserID],
+			RoleUUID:  roles[m.RoleID],
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+		})
+	}
+	statuses := make(map[uint64]string)
+	for _, st := range s.Statuses {
+		statuses[st.ID] = st.UUID
+		a.Statuses = append(a.Statuses, StatusRecord{
+			UUID:      st.UUID,
+			Title:     st.Title,
+			Category:  st.Category,
+			CreatedAt: st.CreatedAt,
+			UpdatedAt: st.UpdatedAt,
+		})
+	}
+	if s.Settings != nil {
+		a.Settings = &SettingsRecord{
+			DefaultStatusUUID: statuses[s.Settings.DefaultStatusID],
+			EstimateScale:     s.Settings.EstimateScale,
+			TimeZone:          s.Settings.TimeZone,
+			WeekStart:         s.Settings.WeekStart,
+			IssueKeyPrefix:    s.Settings.IssueKeyPrefix,
+			CreatedAt:         s.Settings.CreatedAt,
+			UpdatedAt:         s.Settings.UpdatedAt,
+		}
+	}
+	cycles := make(map[uint64]string)
+	for _, c := range s.Cycles {
+		cycles[c.ID] = c.UUID
+		a.Cycles = append(a.Cycles, CycleRecord{
+			UUID:        c.UUID,
+			Title:       c.Title,
+			Description: c.Description,
+			Active:      c.Active,
+			Goals:       c.Goals,
+			StartAt:     c.StartAt,
+			EndAt:       c.EndAt,
+			CreatedAt:   c.CreatedAt,
+			UpdatedAt:   c.UpdatedAt,
+		})
+	}
+	for _, c := range s.Capacities {
+		a.Capacities = append(a.Capacities, CapacityRecord{
+			CycleUUID: cycles[c.CycleID],
+			UserUUID:  users[c.UserID],
+			Capacity:  c.Capacity,
+			Unit:      c.Unit,
+			CreatedAt: c.CreatedAt,
+			UpdatedAt: c.UpdatedAt,
+		})
+	}
+	issues := make(map[uint64]string)
+	for _, i := range s.Issues {
+		issues[i.ID] = i.UUID
+		a.Issues = append(a.Issues, IssueRecord{
+			UUID:         i.UUID,
+			Number:       i.Number,
+			Key:          i.Key,
+			Title:        i.Title,
+			Description:  i.Description,
+			StatusUUID:   statuses[i.StatusID],
+			CycleUUID:    cycles[i.CycleID],
+			Estimate:     i.Estimate,
+			AssigneeUUID: users[i.AssigneeID],
+			CreatorUUID:  users[i.CreatorID],
+			CreatedAt:    i.CreatedAt,
+			UpdatedAt:    i.UpdatedAt,
+		})
+	}
+	for _, t := range s.Transitions {
+		issue, ok := issues[t.IssueID]
+		if !ok {
+			continue
+		}
+		a.Transitions = append(a.Transitions, TransitionRecord{
+			IssueUUID:      issue,
+			CycleUUID:      cycles[t.CycleID],
+			FromStatusUUID: statuses[t.FromStatusID],
+			ToStatusUUID:   statuses[t.ToStatusID],
+			CreatedAt:      t.CreatedAt,
+		})
+	}
+	return a
+}
+
+// Import creates a new workspace from the archive. The workspace is created like any other one,
+// with the builtin roles and the importer as its owner. All the rows get new IDs and UUIDs, references
+// between them are remapped. The archive is not trusted to name accounts: its users are linked to the
+// importer by email and, if the importer is a member of the source workspace, to the members of that
+// workspace with the same verified email. The other members are invited to the new workspace,
+// the issues they created are credited to the importer, those assigned to them are left unassigned
+// and their capacities are left out.
+// Nothing is saved if any record cannot be imported.
+func (s service) Import(ctx context.Context, req *archiveProto.ImportWorkspaceRequest) (*workspacesProto.Workspace, error) {
+	if len(req.Archive) == 0 {
+		return nil, errEmptyArchive
+	}
+	a, err := Decode(bytes.NewReader(req.Archive))
+	if err != nil {
+		return nil, err
+	}
+
+	create := &workspacesProto.CreateWorkspaceRequest{Title: req.Title, Domain: req.Domain}
+	if create.Title == "" {
+		create.Title = a.Header.Workspace.Title
+	}
+	if create.Domain == "" {
+		create.Domain = a.Header.Workspace.Domain
+	}
+	if err := workspacesSrv.ValidateCreateRequest(create); err != nil {
+		return nil, err
+	}
+	importer, err := auth.ExtractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var workspace *workspacesProto.Workspace
+	var members []membership
+	err = s.repo.Transactional(ctx, func(ctx context.Context) error {
+		var err error
+		if workspace, err = s.workspacesSrv.Create(ctx, create); err != nil {
+			return err
+		}
+		members, err = s.restore(ctx, workspace, importer, a)
+		return err
+	})
+	if err != nil {
+		if workspace != nil {
+			// the policy is not part of the transaction, the importer is not left the owner of a workspace which does not exist
+			for _, hook := range s.hooks {
+				_ = hook.MemberRemoved(ctx, workspace, importer.Uuid)
+			}
+		}
+		return nil, err
+	}
+	for _, m := range members {
+		for _, hook := range s.hooks {
+			if err := hook.MemberJoined(ctx, workspace, m.user, m.roleUUID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return workspace, nil
+}
+
+// membership is a member of an imported workspace, who gets their role once the import is saved
+type membership struct {
+	user     *usersProto.User
+	roleUUID string
+}
+
+// idMap maps the UUIDs of an archive to the IDs of the imported rows
+type idMap struct {
+	kind string
+	ids  map[string]uint64
+}
+
+func newIDMap(kind string) idMap {
+	return idMap{kind: kind, ids: make(map[string]uint64)}
+}
+
+// get returns the ID the UUID was imported as, 0 for an empty UUID
+func (m idMap) get(uuid string) (uint64, error) {
+	if uuid == "" {
+		return 0, nil
+	}
+	id, ok := m.ids[uuid]
+	if !ok {
+		return 0, fmt.Errorf("archive: unknown %s %s", m.kind, uuid)
+	}
+	return id, nil
+}
+
+// restore saves the records of the archive in the workspace, invites its members who are not linked to an account
+// and returns the members it added. The importer is already the owner of the workspace.
+func (s service) restore(ctx context.Context, workspace *workspacesProto.Workspace, importer *usersProto.User, a *Archive) ([]membership, error) {
+	workspaceID := workspace.Id
+	linked, err := s.linkableUsers(ctx, importer, a.Header.Workspace.UUID)
+	if err != nil {
+		return nil, err
+	}
+	users := newIDMap("user")
+	accounts := make(map[string]entity.User)
+	// pending are the users of the archive without an account, by UUID
+	pending := make(map[string]bool)
+	for _, r := range a.Users {
+		if u, ok := linked[strings.ToLower(r.Email)]; ok {
+			users.ids[r.UUID] = u.ID
+			accounts[r.UUID] = u
+		} else {
+			pending[r.UUID] = true
+		}
+	}
+	// userOr returns the ID of the account of the user, fallback if they have none
+	userOr := func(uuid string, fallback uint64) (uint64, error) {
+		if pending[uuid] {
+			return fallback, nil
+		}
+		return users.get(uuid)
+	}
+
+	builtin, err := s.repo.BuiltinRoles(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	builtinRoles := make(map[string]entity.Role)
+	for _, r := range builtin {
+		builtinRoles[r.Title] = r
+	}
+	roles := newIDMap("role")
+	roleUUIDs := make(map[string]string)
+	for _, r := range a.Roles {
+		if role, ok := builtinRoles[r.Title]; ok && r.Builtin {
+			roles.ids[r.UUID] = role.ID
+			roleUUIDs[r.UUID] = role.UUID
+			continue
+		}
+		role := entity.Role{
+			UUID:        uuid.New().String(),
+			Title:       r.Title,
+			WorkspaceID: workspaceID,
+			CreatedAt:   r.CreatedAt,
+			UpdatedAt:   r.UpdatedAt,
+		}
+		if err := s.repo.Insert(ctx, &role); err != nil {
+			return nil, err
+		}
+		roles.ids[r.UUID] = role.ID
+		roleUUIDs[r.UUID] = role.UUID
+	}
+
+	var invitations []*MemberRecord
+	var members []membership
+	emails := make(map[string]string)
+	for _, r := range a.Users {
+		emails[r.UUID] = strings.ToLower(r.Email)
+	}
+	for i, r := range a.Members {
+		member := entity.WorkspaceMember{WorkspaceID: workspaceID, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt}
+		var err error
+		if member.RoleID, err = roles.get(r.RoleUUID); err != nil {
+			return nil, err
+		}
+		if pending[r.UserUUID] {
+			invitations = append(invitations, &a.Members[i])
+			continue
+		}
+		if member.UserID, err = users.get(r.UserUUID); err != nil {
+			return nil, err
+		}
+		if member.UserID == importer.Id {
+			continue
+		}
+		if err := s.repo.Insert(ctx, &member); err != nil {
+			return nil, err
+		}
+		account := accounts[r.UserUUID]
+		members = append(members, membership{user: account.ToProto(false /*secure*/), roleUUID: roleUUIDs[r.RoleUUID]})
+	}
+
+	statuses := newIDMap("status")
+	for _, r := range a.Statuses {
+		st := entity.IssueStatus{
+			UUID:        uuid.New().String(),
+			Title:       r.Title,
+			Category:    r.Category,
+			WorkspaceID: workspaceID,
+			CreatedAt:   r.CreatedAt,
+			UpdatedAt:   r.UpdatedAt,
+		}
+		if err := s.repo.Insert(ctx, &st); err != nil {
+			return nil, err
+		}
+		statuses.ids[r.UUID] = st.ID
+	}
+
+	if r := a.Settings; r != nil {
+		settings := entity.WorkspaceSettings{
+			WorkspaceID:    workspaceID,
+			EstimateScale:  r.EstimateScale,
+			TimeZone:       r.TimeZone,
+			WeekStart:      r.WeekStart,
+			IssueKeyPrefix: r.IssueKeyPrefix,
+			CreatedAt:      r.CreatedAt,
+			UpdatedAt:      r.UpdatedAt,
+		}
+		var err error
+		if settings.DefaultStatusID, err = statuses.get(r.DefaultStatusUUID); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Insert(ctx, &settings); err != nil {
+			return nil, err
+		}
+	}
+
+	cycles := newIDMap("cycle")
+	for _, r := range a.Cycles {
+		cycle := entity.Cycle{
+			UUID:        uuid.New().String(),
+			Title:       r.Title,
+			Description: r.Description,
+			Active:      r.Active,
+			Goals:       r.Goals,
+			WorkspaceID: workspaceID,
+			StartAt:     r.StartAt,
+			EndAt:       r.EndAt,
+			CreatedAt:   r.CreatedAt,
+			UpdatedAt:   r.UpdatedAt,
+		}
+		if err := s.repo.Insert(ctx, &cycle); err != nil {
+			return nil, err
+		}
+		cycles.ids[r.UUID] = cycle.ID
+	}
+
+	for _, r := range a.Capacities {
+		if pending[r.UserUUID] {
+			continue
+		}
+		capacity := entity.CycleCapacity{Capacity: r.Capacity, Unit: r.Unit, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt}
+		var err error
+		if capacity.CycleID, err = cycles.get(r.CycleUUID); err != nil {
+			return nil, err
+		}
+		if capacity.UserID, err = users.get(r.UserUUID); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Insert(ctx, &capacity); err != nil {
+			return nil, err
+		}
+	}
+
+	issues := newIDMap("issue")
+	for _, r := range a.Issues {
+		issue := entity.Issue{
+			UUID:        uuid.New().String(),
+			Number:      r.Number,
+			Key:         r.Key,
+			Title:       r.Title,
+			Description: r.Description,
+			Estimate:    r.Estimate,
+			WorkspaceID: workspaceID,
+			CreatedAt:   r.CreatedAt,
+			UpdatedAt:   r.UpdatedAt,
+		}
+		var err error
+		if issue.StatusID, err = statuses.get(r.StatusUUID); err != nil {
+			return nil, err
+		}
+		if issue.CycleID, err = cycles.get(r.CycleUUID); err != nil {
+			return nil, err
+		}
+		if issue.AssigneeID, err = userOr(r.AssigneeUUID, 0); err != nil {
+			return nil, err
+		}
+		if issue.CreatorID, err = userOr(r.CreatorUUID, importer.Id); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Insert(ctx, &issue); err != nil {
+			return nil, err
+		}
+		issues.ids[r.UUID] = issue.ID
+	}
+
+	for _, r := range a.Transitions {
+		transition := entity.IssueTransition{WorkspaceID: workspaceID, CreatedAt: r.CreatedAt}
+		var err error
+		if transition.IssueID, err = issues.get(r.IssueUUID); err != nil {
+			return nil, err
+		}
+		if transition.CycleID, err = cycles.get(r.CycleUUID); err != nil {
+			return nil, err
+		}
+		if transition.FromStatusID, err = statuses.get(r.FromStatusUUID); err != nil {
+			return nil, err
+		}
+		if transition.ToStatusID, err = statuses.get(r.ToStatusUUID); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Insert(ctx, &transition); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range invitations {
+		// the importer owns the workspace and can grant its roles
+		inv := workspacesSrv.NewInvitation(workspaceID, importer.Id, emails[r.UserUUID], roleUUIDs[r.RoleUUID], time.Now())
+		if err := s.repo.Insert(ctx, &inv); err != nil {
+			return nil, err
+		}
+		if err := workspacesSrv.SendInvitation(ctx, workspace, inv); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+// linkableUsers returns the accounts the users of an archive of the source workspace can be linked to, by email.
+// These are the importer and, if the importer is a member of the source workspace, its members with a verified email.
+func (s service) linkableUsers(ctx context.Context, importer *usersProto.User, sourceUUID string) (map[string]entity.User, error) {
+	linked := map[string]entity.User{strings.ToLower(importer.Email): entity.UserFromProto(importer)}
+	members, err := s.repo.SourceMembers(ctx, sourceUUID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if m.UUID != importer.Uuid {
+			continue
+		}
+		for _, u := range members {
+			if u.EmailVerified {
+				linked[strings.ToLower(u.Email)] = u
+			}
+		}
+		break
+	}
+	return linked, nil
+}
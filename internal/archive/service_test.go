@@ -0,0 +1,290 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	workspacesSrv "github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/mail"
+	archiveProto "github.com/mirzakhany/pm/protobuf/archive"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+	"github.com/stretchr/testify/assert"
+)
+
+var errCRUD = errors.New("error crud")
+
+type mockRepository struct {
+	snapshot Snapshot
+	// members are the members of the workspaces by UUID
+	members  map[string][]entity.User
+	builtin  []entity.Role
+	inserted []interface{}
+	lastID   uint64
+}
+
+func (m *mockRepository) GetWorkspace(ctx context.Context, uuid string) (entity.Workspace, error) {
+	if m.snapshot.Workspace.UUID != uuid {
+		return entity.Workspace{}, pg.ErrNoRows
+	}
+	return m.snapshot.Workspace, nil
+}
+
+func (m *mockRepository) Snapshot(ctx context.Context, workspace entity.Workspace) (Snapshot, error) {
+	return m.snapshot, nil
+}
+
+func (m *mockRepository) SourceMembers(ctx context.Context, workspaceUUID string) ([]entity.User, error) {
+	return m.members[workspaceUUID], nil
+}
+
+func (m *mockRepository) BuiltinRoles(ctx context.Context, workspaceID uint64) ([]entity.Role, error) {
+	var res []entity.Role
+	for _, r := range m.builtin {
+		if r.WorkspaceID == workspaceID {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockRepository) Insert(ctx context.Context, model interface{}) error {
+	m.lastID++
+	switch v := model.(type) {
+	case *entity.Workspace:
+		v.ID = m.lastID
+	case *entity.Role:
+		v.ID = m.lastID
+	case *entity.IssueStatus:
+		v.ID = m.lastID
+	case *entity.Cycle:
+		v.ID = m.lastID
+	case *entity.Issue:
+		if v.Title == "error" {
+			return errCRUD
+		}
+		v.ID = m.lastID
+	}
+	m.inserted = append(m.inserted, model)
+	return nil
+}
+
+func (m *mockRepository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	inserted := len(m.inserted)
+	if err := f(ctx); err != nil {
+		m.inserted = m.inserted[:inserted]
+		return err
+	}
+	return nil
+}
+
+// mockHook creates the builtin member role of the workspaces like the roles service, and records the members joining and leaving them
+type mockHook struct {
+	repo    *mockRepository
+	joined  map[string]string
+	removed []string
+}
+
+func newMockHook(repo *mockRepository) *mockHook {
+	return &mockHook{repo: repo, joined: make(map[string]string)}
+}
+
+func (h *mockHook) WorkspaceCreated(ctx context.Context, workspace *workspacesProto.Workspace, owner *usersProto.User) error {
+	h.repo.lastID++
+	h.repo.builtin = append(h.repo.builtin, entity.Role{
+		ID: h.repo.lastID, UUID: fmt.Sprintf("member-%d", workspace.Id), Title: "member", Builtin: true, WorkspaceID: workspace.Id,
+	})
+	return nil
+}
+
+func (h *mockHook) MemberInvited(ctx context.Context, workspace *workspacesProto.Workspace, inviter *usersProto.User, roleUUID string) error {
+	return nil
+}
+
+func (h *mockHook) MemberJoined(ctx context.Context, workspace *workspacesProto.Workspace, user *usersProto.User, roleUUID string) error {
+	h.joined[user.Uuid] = roleUUID
+	return nil
+}
+
+func (h *mockHook) MemberRemoved(ctx context.Context, workspace *workspacesProto.Workspace, userUUID string) error {
+	h.removed = append(h.removed, userUUID)
+	return nil
+}
+
+func testSnapshot() Snapshot {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	return Snapshot{
+		Workspace: entity.Workspace{ID: 10, UUID: "w-uuid", Title: "source", Domain: "source"},
+		Settings:  &entity.WorkspaceSettings{WorkspaceID: 10, DefaultStatusID: 31, IssueKeyPrefix: "SRC", TimeZone: "UTC"},
+		Users: []entity.User{
+			{ID: 11, UUID: "u1-uuid", Username: "existing", Email: "existing@example.com", Password: "hash"},
+			{ID: 12, UUID: "u2-uuid", Username: "new", Email: "new@example.com", Password: "hash"},
+		},
+		Roles: []entity.Role{
+			{ID: 21, UUID: "r-uuid", Title: "member", Builtin: true, WorkspaceID: 10},
+			{ID: 22, UUID: "r2-uuid", Title: "reviewer", WorkspaceID: 10},
+		},
+		Members:  []entity.WorkspaceMember{{WorkspaceID: 10, UserID: 11, RoleID: 22}, {WorkspaceID: 10, UserID: 12, RoleID: 21}},
+		Statuses: []entity.IssueStatus{{ID: 31, UUID: "s1-uuid", Title: "todo"}, {ID: 32, UUID: "s2-uuid", Title: "done"}},
+		Cycles:   []entity.Cycle{{ID: 41, UUID: "c-uuid", Title: "cycle", Goals: []string{"ship"}}},
+		Capacities: []entity.CycleCapacity{
+			{CycleID: 41, UserID: 12, Capacity: 5, Unit: "points"},
+		},
+		Issues: []entity.Issue{
+			{ID: 51, UUID: "i-uuid", Number: 7, Key: "SRC-7", Title: "issue", StatusID: 32, CycleID: 41, AssigneeID: 12, CreatorID: 11, CreatedAt: now},
+		},
+		Transitions: []entity.IssueTransition{
+			{IssueID: 51, CycleID: 41, FromStatusID: 31, ToStatusID: 32, CreatedAt: now},
+			// transition of a deleted issue
+			{IssueID: 52, FromStatusID: 31},
+		},
+	}
+}
+
+func Test_service_ExportImport(t *testing.T) {
+	defer workspacesSrv.MockInvitationsForTest()()
+	mails := mail.InitMock()
+	importer := entity.User{ID: 100, UUID: "importer-uuid", Email: "Existing@example.com", EmailVerified: true}
+	repo := &mockRepository{snapshot: testSnapshot(), lastID: 1000}
+	hook := newMockHook(repo)
+	s := NewService(repo, workspacesSrv.NewServiceForTest(hook), hook)
+	ctx := auth.ContextWithUser(context.Background(), importer.ToProto(false))
+
+	_, err := s.Export(ctx, "none")
+	assert.NotNil(t, err)
+
+	data, err := s.Export(ctx, "w-uuid")
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "hash")
+
+	a, err := Decode(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Len(t, a.Transitions, 1)
+	assert.Equal(t, "s1-uuid", a.Settings.DefaultStatusUUID)
+
+	// validation error
+	_, err = s.Import(ctx, &archiveProto.ImportWorkspaceRequest{})
+	assert.NotNil(t, err)
+	_, err = s.Import(ctx, &archiveProto.ImportWorkspaceRequest{Archive: data, Domain: "not a domain"})
+	assert.NotNil(t, err)
+
+	workspace, err := s.Import(ctx, &archiveProto.ImportWorkspaceRequest{Archive: data, Domain: "copy"})
+	assert.Nil(t, err)
+	assert.Equal(t, "source", workspace.Title)
+	assert.Equal(t, "copy", workspace.Domain)
+	assert.NotEqual(t, "w-uuid", workspace.Uuid)
+
+	ids := make(map[string]uint64)
+	var members []*entity.WorkspaceMember
+	var issue *entity.Issue
+	var transition *entity.IssueTransition
+	var settings *entity.WorkspaceSettings
+	var capacities []*entity.CycleCapacity
+	for _, model := range repo.inserted {
+		switch v := model.(type) {
+		case *entity.User:
+			t.Errorf("user %s created by the import", v.Email)
+		case *entity.Role:
+			ids[v.Title] = v.ID
+			assert.Equal(t, workspace.Id, v.WorkspaceID)
+		case *entity.IssueStatus:
+			ids[v.Title] = v.ID
+			assert.NotEqual(t, "s1-uuid", v.UUID)
+		case *entity.Cycle:
+			ids["cycle"] = v.ID
+			assert.Equal(t, []string{"ship"}, v.Goals)
+		case *entity.WorkspaceMember:
+			members = append(members, v)
+		case *entity.Issue:
+			issue = v
+		case *entity.IssueTransition:
+			transition = v
+		case *entity.WorkspaceSettings:
+			settings = v
+		case *entity.CycleCapacity:
+			capacities = append(capacities, v)
+		}
+	}
+
+	// the builtin roles are those of the new workspace, the others are created
+	assert.NotContains(t, ids, "member")
+	assert.Contains(t, ids, "reviewer")
+
+	// the user with the email of the importer is linked to the importer, who is already the owner,
+	// the other one is invited with the builtin role of the new workspace
+	assert.Empty(t, members)
+	assert.Empty(t, hook.joined)
+	msg, ok := mails.Last("new@example.com")
+	if assert.True(t, ok) {
+		assert.Contains(t, msg.Subject, "source")
+	}
+
+	assert.Equal(t, ids["todo"], settings.DefaultStatusID)
+	assert.Equal(t, "SRC", settings.IssueKeyPrefix)
+	assert.Empty(t, capacities)
+
+	assert.Equal(t, "SRC-7", issue.Key)
+	assert.Equal(t, uint64(7), issue.Number)
+	assert.Equal(t, workspace.Id, issue.WorkspaceID)
+	assert.Equal(t, ids["done"], issue.StatusID)
+	assert.Equal(t, ids["cycle"], issue.CycleID)
+	assert.Equal(t, uint64(0), issue.AssigneeID)
+	assert.Equal(t, importer.ID, issue.CreatorID)
+
+	assert.Equal(t, issue.ID, transition.IssueID)
+	assert.Equal(t, ids["todo"], transition.FromStatusID)
+	assert.Equal(t, ids["done"], transition.ToStatusID)
+
+	// nothing is kept when a record fails
+	inserted := len(repo.inserted)
+	repo.snapshot.Issues[0].Title = "error"
+	data, _ = s.Export(ctx, "w-uuid")
+	_, err = s.Import(ctx, &archiveProto.ImportWorkspaceRequest{Archive: data, Domain: "copy2"})
+	assert.Equal(t, errCRUD, err)
+	assert.Len(t, repo.inserted, inserted)
+	// and the importer does not keep the roles of the workspace
+	assert.Equal(t, []string{importer.UUID}, hook.removed)
+}
+
+func Test_service_ImportLinks(t *testing.T) {
+	defer workspacesSrv.MockInvitationsForTest()()
+	mail.InitMock()
+	importer := entity.User{ID: 100, UUID: "importer-uuid", Email: "importer@example.com", EmailVerified: true}
+	verified := entity.User{ID: 101, UUID: "verified-uuid", Email: "new@example.com", EmailVerified: true}
+	unverified := entity.User{ID: 102, UUID: "unverified-uuid", Email: "existing@example.com"}
+	repo := &mockRepository{snapshot: testSnapshot(), lastID: 1000}
+	hook := newMockHook(repo)
+	s := NewService(repo, workspacesSrv.NewServiceForTest(hook), hook)
+	ctx := auth.ContextWithUser(context.Background(), importer.ToProto(false))
+	data, err := s.Export(ctx, "w-uuid")
+	assert.Nil(t, err)
+
+	linked := func(domain string) []uint64 {
+		inserted := len(repo.inserted)
+		_, err := s.Import(ctx, &archiveProto.ImportWorkspaceRequest{Archive: data, Domain: domain})
+		assert.Nil(t, err)
+		var ids []uint64
+		for _, model := range repo.inserted[inserted:] {
+			if m, ok := model.(*entity.WorkspaceMember); ok {
+				ids = append(ids, m.UserID)
+			}
+		}
+		return ids
+	}
+
+	// the members of the source are not linked when the importer is not one of them
+	repo.members = map[string][]entity.User{"w-uuid": {verified, unverified}}
+	assert.Empty(t, linked("copy1"))
+
+	// and only those with a verified email when they are, with their role in the new workspace
+	repo.members = map[string][]entity.User{"w-uuid": {importer, verified, unverified}}
+	assert.Equal(t, []uint64{verified.ID}, linked("copy2"))
+	assert.Equal(t, map[string]string{verified.UUID: repo.builtin[len(repo.builtin)-1].UUID}, hook.joined)
+}
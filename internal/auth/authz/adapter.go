@@ -0,0 +1,76 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/mirzakhany/pm/internal/entity"
+)
+
+// adapter loads and saves the policy of the enforcer through the repository.
+// Casbin adapters have no context, so the queries run with the background one.
+type adapter struct {
+	repo Repository
+}
+
+var _ persist.BatchAdapter = adapter{}
+
+// LoadPolicy loads all the rules from the repository into the model.
+func (a adapter) LoadPolicy(m model.Model) error {
+	rules, err := a.repo.Query(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		persist.LoadPolicyArray(append([]string{rule.PType}, rule.Values()...), m)
+	}
+	return nil
+}
+
+// SavePolicy replaces the rules in the repository with the rules of the model.
+func (a adapter) SavePolicy(m model.Model) error {
+	var rules []entity.CasbinRule
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range m[sec] {
+			for _, values := range ast.Policy {
+				rules = append(rules, entity.CasbinRuleFromValues(ptype, values))
+			}
+		}
+	}
+	return a.repo.Replace(context.Background(), rules)
+}
+
+// AddPolicy saves the rule in the repository.
+func (a adapter) AddPolicy(sec string, ptype string, rule []string) error {
+	return a.repo.Create(context.Background(), entity.CasbinRuleFromValues(ptype, rule))
+}
+
+// RemovePolicy removes the rule from the repository.
+func (a adapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	return a.repo.Delete(context.Background(), entity.CasbinRuleFromValues(ptype, rule))
+}
+
+// RemoveFilteredPolicy removes the rules matching the filter from the repository.
+func (a adapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	return a.repo.DeleteFiltered(context.Background(), ptype, fieldIndex, fieldValues...)
+}
+
+// AddPolicies saves the rules in the repository.
+func (a adapter) AddPolicies(sec string, ptype string, rules [][]string) error {
+	var r []entity.CasbinRule
+	for _, rule := range rules {
+		r = append(r, entity.CasbinRuleFromValues(ptype, rule))
+	}
+	return a.repo.Create(context.Background(), r...)
+}
+
+// RemovePolicies removes the rules from the repository.
+func (a adapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	for _, rule := range rules {
+		if err := a.RemovePolicy(sec, ptype, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
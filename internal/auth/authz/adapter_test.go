@@ -0,0 +1,78 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	casbin "github.com/casbin/casbin/v2"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdapter(t *testing.T) {
+	modelPath = config.RegisterStringMock("rbac.model", "../../../configs/rbac.conf")
+	reloadInterval = config.RegisterIntMock("rbac.reloadInterval", 0)
+
+	// nothing is seeded without a policy file
+	repo := &mockRepository{}
+	_, err := NewEnforcer(repo, nil)
+	assert.Nil(t, err)
+	assert.Len(t, repo.items, 0)
+
+	policyPath = config.RegisterStringMock("rbac.policy", "../../../configs/sample_policy.csv")
+	e, err := NewEnforcer(repo, nil)
+	assert.Nil(t, err)
+	seeded := len(repo.items)
+	assert.Equal(t, 7, seeded)
+	assert.True(t, e.HasNamedGroupingPolicy("g", ownerUUID, "role:owner", "foo.bar"))
+
+	// the policy is seeded only once
+	_, err = NewEnforcer(repo, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, seeded, len(repo.items))
+
+	ok, err := e.Enforce(adminUUID, "", "cycles", "delete", "c1")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// changes of the enforcer are saved
+	_, err = e.AddNamedGroupingPolicy("g", "u1", "role:owner", "foo.bar")
+	assert.Nil(t, err)
+	assert.Equal(t, seeded+1, len(repo.items))
+	_, err = e.RemoveNamedGroupingPolicy("g2", adminUUID, "role:admin")
+	assert.Nil(t, err)
+	assert.Equal(t, seeded, len(repo.items))
+	_, err = e.RemoveFilteredNamedPolicy("p", 0, "role:admin")
+	assert.Nil(t, err)
+	assert.Equal(t, seeded-4, len(repo.items))
+
+	// changes of the storage are applied on reload
+	ok, _ = e.Enforce("u2", "foo.bar", "users", "get", "u1")
+	assert.False(t, ok)
+	assert.Nil(t, repo.Create(context.Background(), entity.CasbinRuleFromValues("g", []string{"u2", "role:owner", "foo.bar"})))
+	assert.Nil(t, e.LoadPolicy())
+	ok, _ = e.Enforce("u2", "foo.bar", "users", "get", "u1")
+	assert.True(t, ok)
+
+	// the rules of a domain apply in it only, even to the users they name
+	_, err = e.AddPolicy("u3", "foo.bar", "issues", "get", "*", "allow")
+	assert.Nil(t, err)
+	ok, _ = e.Enforce("u3", "foo.bar", "issues", "get", "i1")
+	assert.True(t, ok)
+	ok, _ = e.Enforce("u3", "other", "issues", "get", "i1")
+	assert.False(t, ok)
+
+	// a new enforcer loads the saved policy
+	e2, err := casbin.NewSyncedEnforcer(modelPath.String(), adapter{repo})
+	assert.Nil(t, err)
+	assert.Equal(t, e.GetNamedGroupingPolicy("g"), e2.GetNamedGroupingPolicy("g"))
+	assert.Empty(t, e2.GetNamedGroupingPolicy("g2"))
+}
+
+func TestCasbinRule_Values(t *testing.T) {
+	rule := entity.CasbinRuleFromValues("g2", []string{"u1", "role:admin"})
+	assert.Equal(t, entity.CasbinRule{PType: "g2", V0: "u1", V1: "role:admin"}, rule)
+	assert.Equal(t, []string{"u1", "role:admin"}, rule.Values())
+	assert.Empty(t, entity.CasbinRule{PType: "p"}.Values())
+}
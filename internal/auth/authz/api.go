@@ -0,0 +1,94 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/grpcgw"
+	"github.com/mirzakhany/pm/protobuf/authz"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type API interface {
+	grpcgw.Controller
+	authz.PolicyServiceServer
+}
+
+type api struct {
+	service Service
+}
+
+func (a api) InitRest(ctx context.Context, conn *grpc.ClientConn, mux *runtime.ServeMux) {
+	cl := authz.NewPolicyServiceClient(conn)
+	_ = authz.RegisterPolicyServiceHandlerClient(ctx, mux, cl)
+}
+
+func (a api) InitGrpc(ctx context.Context, server *grpc.Server) {
+	authz.RegisterPolicyServiceServer(server, a)
+}
+
+func (a api) ListPolicies(ctx context.Context, request *authz.ListPoliciesRequest) (*authz.ListPoliciesResponse, error) {
+	res, err := a.service.ListPolicies(ctx, request.Domain)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return res, err
+}
+
+func (a api) AddPolicy(ctx context.Context, request *authz.PolicyRule) (*authz.PolicyRule, error) {
+	res, err := a.service.AddPolicy(ctx, request)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return res, err
+}
+
+func (a api) RemovePolicy(ctx context.Context, request *authz.PolicyRule) (*empty.Empty, error) {
+	err := a.service.RemovePolicy(ctx, request)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) CheckPermission(ctx context.Context, request *authz.CheckPermissionRequest) (*authz.CheckPermissionResponse, error) {
+	res, err := a.service.CheckPermission(ctx, request)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return res, err
+}
+
+// errorStatus maps the errors of the service to grpc statuses.
+func errorStatus(err error) error {
+	switch err {
+	case errRuleExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errRuleNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case errOtherDomain, errCheckNotAllowed, errCheckOtherWorkspace:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errNoWorkspace:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func New(srv Service) API {
+	s := api{service: srv}
+	grpcgw.RegisterController(s)
+	return s
+}
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/authzV1.PolicyService/ListPolicies":    auth.Require("policies", "list"),
+		"/authzV1.PolicyService/AddPolicy":       auth.Require("policies", "create"),
+		"/authzV1.PolicyService/RemovePolicy":    auth.Require("policies", "delete"),
+		"/authzV1.PolicyService/CheckPermission": auth.AuthenticatedInWorkspace,
+	})
+}
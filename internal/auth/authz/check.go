@@ -0,0 +1,75 @@
+package authz
+
+import (
+	"context"
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	authzProto "github.com/mirzakhany/pm/protobuf/authz"
+)
+
+var (
+	errCheckNotAllowed     = errors.New("not allowed to check the permissions of other users")
+	errCheckOtherWorkspace = errors.New("permissions are checked in the workspace of the request")
+)
+
+// ValidateCheckPermissionRequest validates the CheckPermissionRequest fields.
+func ValidateCheckPermissionRequest(c *authzProto.CheckPermissionRequest) error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.UserUuid, is.UUID),
+		validation.Field(&c.WorkspaceUuid, is.UUID),
+		validation.Field(&c.Resource, validation.Required, validation.Length(0, 64)),
+		validation.Field(&c.Action, validation.Required, validation.Length(0, 64)),
+	)
+}
+
+// CheckPermission enforces the request for the user and explains the decision with the matched rule.
+// Users can check their own permissions, checking the ones of other users needs the check permission on policies.
+// A workspace given in the request must be the workspace of the request, whose members the resolver checks.
+func (s service) CheckPermission(ctx context.Context, req *authzProto.CheckPermissionRequest) (*authzProto.CheckPermissionResponse, error) {
+	if err := ValidateCheckPermissionRequest(req); err != nil {
+		return nil, err
+	}
+	current, err := auth.ExtractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	domain := ""
+	if req.WorkspaceUuid != "" {
+		workspace, err := auth.ExtractWorkspace(ctx)
+		if err != nil {
+			return nil, errNoWorkspace
+		}
+		if workspace.Uuid != req.WorkspaceUuid {
+			return nil, errCheckOtherWorkspace
+		}
+		domain = workspace.Domain
+	}
+
+	userUUID := req.UserUuid
+	if userUUID == "" {
+		userUUID = current.Uuid
+	}
+	if userUUID != current.Uuid {
+		allowed, err := s.enforcer.Enforce(current.Uuid, domain, "policies", "check", userUUID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, errCheckNotAllowed
+		}
+	}
+
+	allowed, explain, err := s.enforcer.EnforceEx(userUUID, domain, req.Resource, req.Action, req.Object)
+	if err != nil {
+		return nil, err
+	}
+	res := &authzProto.CheckPermissionResponse{Allowed: allowed}
+	if len(explain) > 0 {
+		res.MatchedRule = entity.CasbinRuleFromValues("p", explain).ToProto()
+	}
+	return res, nil
+}
@@ -0,0 +1,58 @@
+package authz
+
+import (
+	"context"
+	"time"
+
+	casbin "github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/mirzakhany/pm/pkg/config"
+)
+
+var (
+	modelPath = config.RegisterString("rbac.model", "configs/rbac.conf")
+	// policyPath is the policy file the rules are seeded from when the database has none, nothing is seeded without it.
+	// configs/sample_policy.csv grants the sample users everything, it is meant for local development and tests only.
+	policyPath = config.RegisterString("rbac.policy", "")
+	// reloadInterval is the number of seconds between two reloads of the whole policy, 0 disables them.
+	// Reloads pick up the rules changed without the enforcer, like the ones of a purged workspace.
+	reloadInterval = config.RegisterInt("rbac.reloadInterval", 300)
+)
+
+// NewEnforcer creates a casbin enforcer from the configured model and the rules of the repository.
+// Requests are enforced as (user UUID, workspace domain, resource, action, object UUID).
+// The enforcer saves the policy changes in the repository and announces them with the watcher.
+func NewEnforcer(repo Repository, watcher persist.Watcher) (*casbin.SyncedEnforcer, error) {
+	if err := seedPolicy(repo); err != nil {
+		return nil, err
+	}
+	enforcer, err := casbin.NewSyncedEnforcer(modelPath.String(), adapter{repo})
+	if err != nil {
+		return nil, err
+	}
+	if watcher != nil {
+		if err := enforcer.SetWatcher(watcher); err != nil {
+			return nil, err
+		}
+	}
+	if reloadInterval.Int() > 0 {
+		enforcer.StartAutoLoadPolicy(time.Second * time.Duration(reloadInterval.Int()))
+	}
+	return enforcer, nil
+}
+
+// seedPolicy saves the rules of the configured policy file in the repository if it has none.
+func seedPolicy(repo Repository) error {
+	if policyPath.String() == "" {
+		return nil
+	}
+	n, err := repo.Count(context.Background())
+	if err != nil || n > 0 {
+		return err
+	}
+	seed, err := casbin.NewEnforcer(modelPath.String(), policyPath.String())
+	if err != nil {
+		return err
+	}
+	return adapter{repo}.SavePolicy(seed.GetModel())
+}
@@ -0,0 +1,76 @@
+package authz
+
+import (
+	"context"
+
+	casbin "github.com/casbin/casbin/v2"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/grpcgw"
+	"github.com/mirzakhany/pm/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authorizer checks the current user is allowed to call the method in the current workspace,
+// or in every workspace for the global methods which are checked outside of any workspace.
+// It runs after the user is authenticated and the workspace is resolved.
+type authorizer struct {
+	enforcer *casbin.SyncedEnforcer
+}
+
+func (a authorizer) authorize(ctx context.Context, method string, req interface{}) error {
+	policy, ok := auth.MethodPolicyOf(method)
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "method %s is not allowed", method)
+	}
+	if policy.Access != auth.AccessAuthorized && policy.Access != auth.AccessGlobal {
+		return nil
+	}
+	user, err := auth.ExtractUser(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	domain := ""
+	if policy.Access == auth.AccessAuthorized {
+		workspace, err := auth.ExtractWorkspace(ctx)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "method %s requires a workspace", method)
+		}
+		domain = workspace.Domain
+	}
+
+	allowed, err := a.enforcer.Enforce(user.Uuid, domain, policy.Resource, policy.Action, objectOf(req))
+	if err != nil {
+		log.Error("authorization failed", log.String("method", method), log.Err(err))
+		return status.Error(codes.Internal, "authorization failed")
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "not allowed to %s %s", policy.Action, policy.Resource)
+	}
+	return nil
+}
+
+func (a authorizer) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a authorizer) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod, nil); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// RegisterAuthorizer registers the interceptors enforcing the policies of the enforcer on every request.
+// It must be registered after the workspace resolver.
+func RegisterAuthorizer(enforcer *casbin.SyncedEnforcer) {
+	a := authorizer{enforcer: enforcer}
+	grpcgw.RegisterInterceptors(grpcgw.Interceptor{
+		Unary:  a.unary,
+		Stream: a.stream,
+	})
+}
@@ -0,0 +1,101 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	casbin "github.com/casbin/casbin/v2"
+	"github.com/mirzakhany/pm/pkg/auth"
+	aclProto "github.com/mirzakhany/pm/protobuf/acl"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	adminUUID = "8f14e45f-ceea-467f-a0e6-1f6c3b5a1d20"
+	ownerUUID = "c9f0f895-fb98-4ab9-92b2-8d5e2f4f7c31"
+)
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/usersV1.UserService/Login":       auth.Public,
+		"/usersV1.UserService/Logout":      auth.Authenticated,
+		"/usersV1.UserService/ListUsers":   auth.Require("users", "list"),
+		"/usersV1.UserService/GetUser":     auth.Require("users", "get"),
+		"/usersV1.UserService/DeleteUser":  auth.Require("users", "delete"),
+		"/test.TestService/RevokeSessions": auth.RequireGlobal("sessions", "revoke"),
+	})
+}
+
+func newTestEnforcer(t *testing.T) *casbin.SyncedEnforcer {
+	e, err := NewEnforcerForTest("../../../configs/rbac.conf", "../../../configs/sample_policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestAuthorizer(t *testing.T) {
+	e := newTestEnforcer(t)
+	_, err := e.AddPolicy("role:owner", "*", "users", "delete", "protected", "deny")
+	assert.Nil(t, err)
+	a := authorizer{enforcer: e}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	call := func(ctx context.Context, method string, req interface{}) codes.Code {
+		_, err := a.unary(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return status.Code(err)
+	}
+
+	ctx := context.Background()
+	admin := auth.ContextWithUser(ctx, &usersProto.User{Uuid: adminUUID})
+	adminInWorkspace := auth.ContextWithWorkspace(admin, &workspacesProto.Workspace{Id: 1, Domain: "foo.bar"})
+	owner := auth.ContextWithWorkspace(auth.ContextWithUser(ctx, &usersProto.User{Uuid: ownerUUID}),
+		&workspacesProto.Workspace{Id: 1, Domain: "foo.bar"})
+	ownerElsewhere := auth.ContextWithWorkspace(auth.ContextWithUser(ctx, &usersProto.User{Uuid: ownerUUID}),
+		&workspacesProto.Workspace{Id: 2, Domain: "other"})
+	stranger := auth.ContextWithUser(ctx, &usersProto.User{Uuid: "someone"})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		req    interface{}
+		want   codes.Code
+	}{
+		{"open method", ctx, "/usersV1.UserService/Login", &usersProto.LoginRequest{}, codes.OK},
+		{"no user", ctx, "/usersV1.UserService/DeleteUser", &usersProto.DeleteUserRequest{Uuid: "u1"}, codes.Unauthenticated},
+		{"no workspace", admin, "/usersV1.UserService/GetUser", &usersProto.GetUserRequest{Uuid: "u1"}, codes.FailedPrecondition},
+		{"global role", adminInWorkspace, "/usersV1.UserService/GetUser", &usersProto.GetUserRequest{Uuid: "u1"}, codes.OK},
+		{"global role without the action", adminInWorkspace, "/usersV1.UserService/DeleteUser", &usersProto.DeleteUserRequest{Uuid: "u1"}, codes.PermissionDenied},
+		{"global method", admin, "/test.TestService/RevokeSessions", nil, codes.OK},
+		{"global method in a workspace", adminInWorkspace, "/test.TestService/RevokeSessions", nil, codes.OK},
+		{"global method with a workspace role", owner, "/test.TestService/RevokeSessions", nil, codes.PermissionDenied},
+		{"workspace role", owner, "/usersV1.UserService/DeleteUser", &usersProto.DeleteUserRequest{Uuid: "u1"}, codes.OK},
+		{"workspace role in another workspace", ownerElsewhere, "/usersV1.UserService/DeleteUser", &usersProto.DeleteUserRequest{Uuid: "u1"}, codes.PermissionDenied},
+		{"explicit deny", owner, "/usersV1.UserService/DeleteUser", &usersProto.DeleteUserRequest{Uuid: "protected"}, codes.PermissionDenied},
+		{"authenticated method", stranger, "/usersV1.UserService/Logout", &usersProto.LogoutRequest{}, codes.OK},
+		{"unknown method", admin, "/usersV1.UserService/Unknown", &usersProto.GetUserRequest{Uuid: "u1"}, codes.PermissionDenied},
+		{"no role", auth.ContextWithWorkspace(stranger, &workspacesProto.Workspace{Id: 1, Domain: "foo.bar"}),
+			"/usersV1.UserService/ListUsers", &usersProto.ListUsersRequest{}, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, call(tt.ctx, tt.method, tt.req))
+		})
+	}
+}
+
+func TestObjectOf(t *testing.T) {
+	assert.Equal(t, "u1", objectOf(&usersProto.GetUserRequest{Uuid: "u1"}))
+	assert.Equal(t, "u2", objectOf(&workspacesProto.RemoveWorkspaceMemberRequest{WorkspaceUuid: "w1", UserUuid: "u2"}))
+	assert.Equal(t, "w1", objectOf(&workspacesProto.GetWorkspaceSettingsRequest{WorkspaceUuid: "w1"}))
+	assert.Equal(t, "o1", objectOf(&aclProto.ObjectACL{ObjectType: "issues", ObjectUuid: "o1"}))
+	assert.Equal(t, "", objectOf(&usersProto.ListUsersRequest{}))
+}
@@ -0,0 +1,81 @@
+package authz
+
+import (
+	"context"
+
+	casbin "github.com/casbin/casbin/v2"
+	"github.com/mirzakhany/pm/internal/entity"
+)
+
+// NewEnforcerForTest creates an enforcer with the given model file and the rules of the policy file,
+// saving its changes in memory.
+func NewEnforcerForTest(model, policy string) (*casbin.SyncedEnforcer, error) {
+	repo := &mockRepository{}
+	seed, err := casbin.NewEnforcer(model, policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := (adapter{repo}).SavePolicy(seed.GetModel()); err != nil {
+		return nil, err
+	}
+	return casbin.NewSyncedEnforcer(model, adapter{repo})
+}
+
+type mockRepository struct {
+	items  []entity.CasbinRule
+	lastID uint64
+}
+
+func (m mockRepository) Query(ctx context.Context) ([]entity.CasbinRule, error) {
+	return m.items, nil
+}
+
+func (m mockRepository) Count(ctx context.Context) (int, error) {
+	return len(m.items), nil
+}
+
+func (m *mockRepository) Create(ctx context.Context, rules ...entity.CasbinRule) error {
+	for _, rule := range rules {
+		m.lastID++
+		rule.ID = m.lastID
+		m.items = append(m.items, rule)
+	}
+	return nil
+}
+
+func (m *mockRepository) Delete(ctx context.Context, rule entity.CasbinRule) error {
+	for i, item := range m.items {
+		rule.ID = item.ID
+		if item == rule {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteFiltered(ctx context.Context, ptype string, fieldIndex int, values ...string) error {
+	var kept []entity.CasbinRule
+	for _, item := range m.items {
+		if item.PType != ptype || !matchValues(item, fieldIndex, values) {
+			kept = append(kept, item)
+		}
+	}
+	m.items = kept
+	return nil
+}
+
+func matchValues(rule entity.CasbinRule, fieldIndex int, values []string) bool {
+	ruleValues := []string{rule.V0, rule.V1, rule.V2, rule.V3, rule.V4, rule.V5}
+	for i, v := range values {
+		if v != "" && ruleValues[fieldIndex+i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mockRepository) Replace(ctx context.Context, rules []entity.CasbinRule) error {
+	m.items = nil
+	return m.Create(ctx, rules...)
+}
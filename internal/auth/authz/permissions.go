@@ -0,0 +1,20 @@
+package authz
+
+// objectOf returns the UUID of the object the request is about, empty if it is not about a single object
+func objectOf(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetUuid() string }:
+		return r.GetUuid()
+	case interface{ GetIssueUuid() string }:
+		return r.GetIssueUuid()
+	case interface{ GetCycleUuid() string }:
+		return r.GetCycleUuid()
+	case interface{ GetObjectUuid() string }:
+		return r.GetObjectUuid()
+	case interface{ GetUserUuid() string }:
+		return r.GetUserUuid()
+	case interface{ GetWorkspaceUuid() string }:
+		return r.GetWorkspaceUuid()
+	}
+	return ""
+}
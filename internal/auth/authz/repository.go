@@ -0,0 +1,95 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/db"
+)
+
+// Repository encapsulates the logic to access the policy rules from the data source.
+type Repository interface {
+	// Query returns all the policy rules
+	Query(ctx context.Context) ([]entity.CasbinRule, error)
+	// Count returns the number of policy rules
+	Count(ctx context.Context) (int, error)
+	// Create saves the rules in the storage
+	Create(ctx context.Context, rules ...entity.CasbinRule) error
+	// Delete removes the rule from the storage
+	Delete(ctx context.Context, rule entity.CasbinRule) error
+	// DeleteFiltered removes the rules of the ptype whose values starting at fieldIndex match the given ones,
+	// empty values match any value
+	DeleteFiltered(ctx context.Context, ptype string, fieldIndex int, values ...string) error
+	// Replace replaces all the rules in the storage with the given ones
+	Replace(ctx context.Context, rules []entity.CasbinRule) error
+}
+
+// repository persists policy rules in database
+type repository struct {
+	db *db.DB
+}
+
+// NewRepository creates a new policy rules repository
+func NewRepository(db *db.DB) Repository {
+	return repository{db}
+}
+
+// Query reads all the policy rules from the database.
+func (r repository) Query(ctx context.Context) ([]entity.CasbinRule, error) {
+	var rules []entity.CasbinRule
+	err := r.db.With(ctx).Model(&rules).Order("id ASC").Select()
+	return rules, err
+}
+
+// Count returns the number of the policy rules in the database.
+func (r repository) Count(ctx context.Context) (int, error) {
+	return r.db.With(ctx).Model((*entity.CasbinRule)(nil)).Count()
+}
+
+// Create saves the rules in the database.
+func (r repository) Create(ctx context.Context, rules ...entity.CasbinRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	_, err := r.db.With(ctx).Model(&rules).Insert()
+	return err
+}
+
+// Delete deletes the rule from the database.
+func (r repository) Delete(ctx context.Context, rule entity.CasbinRule) error {
+	_, err := r.db.With(ctx).Model((*entity.CasbinRule)(nil)).
+		Where("ptype = ?", rule.PType).
+		Where("v0 = ?", rule.V0).
+		Where("v1 = ?", rule.V1).
+		Where("v2 = ?", rule.V2).
+		Where("v3 = ?", rule.V3).
+		Where("v4 = ?", rule.V4).
+		Where("v5 = ?", rule.V5).
+		Delete()
+	return err
+}
+
+// DeleteFiltered deletes the rules of the ptype matching the values starting at fieldIndex from the database.
+func (r repository) DeleteFiltered(ctx context.Context, ptype string, fieldIndex int, values ...string) error {
+	q := r.db.With(ctx).Model((*entity.CasbinRule)(nil)).Where("ptype = ?", ptype)
+	for i, v := range values {
+		if v == "" {
+			continue
+		}
+		q = q.Where("? = ?", pg.Ident(fmt.Sprintf("v%d", fieldIndex+i)), v)
+	}
+	_, err := q.Delete()
+	return err
+}
+
+// Replace deletes all the rules from the database and saves the given ones in a transaction.
+func (r repository) Replace(ctx context.Context, rules []entity.CasbinRule) error {
+	return r.db.Transactional(ctx, func(ctx context.Context) error {
+		if _, err := r.db.With(ctx).Model((*entity.CasbinRule)(nil)).Where("TRUE").Delete(); err != nil {
+			return err
+		}
+		return r.Create(ctx, rules...)
+	})
+}
@@ -0,0 +1,36 @@
+package authz
+
+import (
+	casbin "github.com/casbin/casbin/v2"
+)
+
+// RoleSync grants users the global roles an identity provider decides, such as the roles of their directory groups.
+type RoleSync struct {
+	enforcer *casbin.SyncedEnforcer
+}
+
+// NewRoleSync creates a role sync saving the grants with the enforcer.
+func NewRoleSync(enforcer *casbin.SyncedEnforcer) RoleSync {
+	return RoleSync{enforcer}
+}
+
+// SyncRoles gives the user the roles with g2 rules, and takes back the managed roles which are not among them.
+// Roles outside of the managed ones are left as they are.
+func (r RoleSync) SyncRoles(userUUID string, roles, managed []string) error {
+	want := map[string]bool{}
+	for _, role := range roles {
+		want[role] = true
+	}
+	for _, role := range managed {
+		var err error
+		if want[role] {
+			_, err = r.enforcer.AddNamedGroupingPolicy("g2", userUUID, role)
+		} else {
+			_, err = r.enforcer.RemoveNamedGroupingPolicy("g2", userUUID, role)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	casbin "github.com/casbin/casbin/v2"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	authzProto "github.com/mirzakhany/pm/protobuf/authz"
+)
+
+// Service encapsulates use case logic for the policy rules.
+type Service interface {
+	// ListPolicies returns the rules of the domain of the workspace of the request
+	ListPolicies(ctx context.Context, domain string) (*authzProto.ListPoliciesResponse, error)
+	// AddPolicy adds a rule to the policy
+	AddPolicy(ctx context.Context, rule *authzProto.PolicyRule) (*authzProto.PolicyRule, error)
+	// RemovePolicy removes a rule from the policy
+	RemovePolicy(ctx context.Context, rule *authzProto.PolicyRule) error
+	// CheckPermission returns whether a user is allowed an action and the rule deciding it
+	CheckPermission(ctx context.Context, req *authzProto.CheckPermissionRequest) (*authzProto.CheckPermissionResponse, error)
+}
+
+var (
+	errRuleExists   = errors.New("policy rule already exists")
+	errRuleNotFound = errors.New("policy rule not found")
+	errOtherDomain  = errors.New("policy rules of other workspaces cannot be managed")
+	errNoWorkspace  = errors.New("policy rules are managed in a workspace")
+	errOtherSubject = errors.New("policy rules can only name the roles and the members of the workspace")
+)
+
+// ruleSizes is the number of values of the rules of each ptype
+var ruleSizes = map[string]int{"p": 6, "g": 3, "g2": 2}
+
+// domainField is the index of the domain in the values of the rules of each ptype
+var domainField = map[string]int{"p": 1, "g": 2}
+
+// ValidatePolicyRule validates the PolicyRule fields.
+func ValidatePolicyRule(r *authzProto.PolicyRule) error {
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Ptype, validation.Required, validation.In("p", "g", "g2")),
+		validation.Field(&r.Values, validation.By(func(interface{}) error {
+			size, ok := ruleSizes[r.Ptype]
+			if !ok {
+				return nil
+			}
+			if len(r.Values) != size {
+				return fmt.Errorf("%s rules have %d values", r.Ptype, size)
+			}
+			for _, v := range r.Values {
+				if v == "" {
+					return errors.New("values cannot be blank")
+				}
+			}
+			if r.Ptype == "p" && r.Values[5] != "allow" && r.Values[5] != "deny" {
+				return errors.New("the effect must be allow or deny")
+			}
+			return nil
+		})),
+	)
+}
+
+type service struct {
+	enforcer      *casbin.SyncedEnforcer
+	workspacesSrv workspaces.Service
+}
+
+// NewService creates a new policy rules service.
+func NewService(enforcer *casbin.SyncedEnforcer, workspacesSrv workspaces.Service) Service {
+	return service{enforcer, workspacesSrv}
+}
+
+// ruleDomain returns the domain of the rule, empty for the global g2 rules.
+func ruleDomain(ptype string, values []string) string {
+	if i, ok := domainField[ptype]; ok && i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+// checkDomain returns errOtherDomain if the request is made in a workspace other than the domain.
+// The global rules are not managed through the service, requests made outside of a workspace are refused.
+func checkDomain(ctx context.Context, domain string) error {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return errNoWorkspace
+	}
+	if workspace.Domain != domain {
+		return errOtherDomain
+	}
+	return nil
+}
+
+// ListPolicies returns the p and g rules of the domain of the workspace of the request.
+func (s service) ListPolicies(ctx context.Context, domain string) (*authzProto.ListPoliciesResponse, error) {
+	if workspace, err := auth.ExtractWorkspace(ctx); err == nil && domain == "" {
+		domain = workspace.Domain
+	}
+	if err := checkDomain(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	var rules []entity.CasbinRule
+	for _, ptype := range []string{"p", "g"} {
+		for _, v := range s.filteredNamedRules(ptype, domainField[ptype], domain) {
+			rules = append(rules, entity.CasbinRuleFromValues(ptype, v))
+		}
+	}
+	return &authzProto.ListPoliciesResponse{Rules: entity.CasbinRuleToProtoList(rules)}, nil
+}
+
+func (s service) filteredNamedRules(ptype string, fieldIndex int, value string) [][]string {
+	if ptype == "p" {
+		return s.enforcer.GetFilteredNamedPolicy(ptype, fieldIndex, value)
+	}
+	return s.enforcer.GetFilteredNamedGroupingPolicy(ptype, fieldIndex, value)
+}
+
+// checkSubjects returns errOtherSubject unless the subject of a p rule is a role or a member of the workspace of the request,
+// and a g rule gives one of its roles to one of its members.
+// Otherwise a rule could grant the permissions of the workspace to anyone, or a role of another workspace.
+func (s service) checkSubjects(ctx context.Context, rule *authzProto.PolicyRule) error {
+	var ok bool
+	var err error
+	switch rule.Ptype {
+	case "p":
+		if ok, err = s.isRole(ctx, rule.Values[0]); err == nil && !ok {
+			ok, err = s.workspacesSrv.IsMember(ctx, rule.Values[0])
+		}
+	case "g":
+		if ok, err = s.workspacesSrv.IsMember(ctx, rule.Values[0]); err == nil && ok {
+			ok, err = s.isRole(ctx, rule.Values[1])
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errOtherSubject
+	}
+	return nil
+}
+
+// isRole returns whether the subject is a role of the workspace of the request.
+func (s service) isRole(ctx context.Context, subject string) (bool, error) {
+	roleUUID := strings.TrimPrefix(subject, "role:")
+	if roleUUID == subject {
+		return false, nil
+	}
+	return s.workspacesSrv.HasRole(ctx, roleUUID)
+}
+
+// AddPolicy adds the rule to the policy, the change is saved and applied right away.
+func (s service) AddPolicy(ctx context.Context, rule *authzProto.PolicyRule) (*authzProto.PolicyRule, error) {
+	if err := ValidatePolicyRule(rule); err != nil {
+		return nil, err
+	}
+	if err := checkDomain(ctx, ruleDomain(rule.Ptype, rule.Values)); err != nil {
+		return nil, err
+	}
+	if err := s.checkSubjects(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	var added bool
+	var err error
+	if rule.Ptype == "p" {
+		added, err = s.enforcer.AddNamedPolicy(rule.Ptype, rule.Values)
+	} else {
+		added, err = s.enforcer.AddNamedGroupingPolicy(rule.Ptype, rule.Values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, errRuleExists
+	}
+	return rule, nil
+}
+
+// RemovePolicy removes the rule from the policy, the change is saved and applied right away.
+// The subjects are not checked so the rules of removed members and roles can be cleaned up.
+func (s service) RemovePolicy(ctx context.Context, rule *authzProto.PolicyRule) error {
+	if err := ValidatePolicyRule(rule); err != nil {
+		return err
+	}
+	if err := checkDomain(ctx, ruleDomain(rule.Ptype, rule.Values)); err != nil {
+		return err
+	}
+
+	var removed bool
+	var err error
+	if rule.Ptype == "p" {
+		removed, err = s.enforcer.RemoveNamedPolicy(rule.Ptype, rule.Values)
+	} else {
+		removed, err = s.enforcer.RemoveNamedGroupingPolicy(rule.Ptype, rule.Values)
+	}
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errRuleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/pkg/auth"
+	authzProto "github.com/mirzakhany/pm/protobuf/authz"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePolicyRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    *authzProto.PolicyRule
+		wantErr bool
+	}{
+		{"p", &authzProto.PolicyRule{Ptype: "p", Values: []string{"role:a", "d", "issues", "get", "*", "allow"}}, false},
+		{"p deny", &authzProto.PolicyRule{Ptype: "p", Values: []string{"role:a", "d", "issues", "get", "*", "deny"}}, false},
+		{"p effect", &authzProto.PolicyRule{Ptype: "p", Values: []string{"role:a", "d", "issues", "get", "*", "maybe"}}, true},
+		{"p size", &authzProto.PolicyRule{Ptype: "p", Values: []string{"role:a", "d", "issues", "get", "*"}}, true},
+		{"g", &authzProto.PolicyRule{Ptype: "g", Values: []string{"u1", "role:a", "d"}}, false},
+		{"g blank", &authzProto.PolicyRule{Ptype: "g", Values: []string{"u1", "", "d"}}, true},
+		{"g2", &authzProto.PolicyRule{Ptype: "g2", Values: []string{"u1", "role:a"}}, false},
+		{"g2 size", &authzProto.PolicyRule{Ptype: "g2", Values: []string{"u1", "role:a", "d"}}, true},
+		{"ptype", &authzProto.PolicyRule{Ptype: "g3", Values: []string{"u1", "role:a"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePolicyRule(tt.rule)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func Test_service_Policies(t *testing.T) {
+	workspacesSrv := workspaces.NewServiceForTest()
+	s := NewService(newTestEnforcer(t), workspacesSrv)
+	ctx := context.Background()
+	inFoo := auth.ContextWithWorkspace(ctx, &workspacesProto.Workspace{Id: 1, Domain: "foo.bar"})
+	inOther := auth.ContextWithWorkspace(ctx, &workspacesProto.Workspace{Id: 2, Domain: "other"})
+	const roleUUID = "3c6e0b8a-9c15-4a1b-8f6e-2b1f3c4d5e6f"
+	workspaces.AddMemberForTest(workspacesSrv, 1, &usersProto.User{Id: 1, Uuid: "u1"})
+	workspaces.AddMemberForTest(workspacesSrv, 2, &usersProto.User{Id: 2, Uuid: "u2"})
+	workspaces.AddRoleForTest(workspacesSrv, 1, roleUUID)
+
+	// the rules are managed in a workspace, which lists its own rules only
+	_, err := s.ListPolicies(ctx, "")
+	assert.Equal(t, errNoWorkspace, err)
+	foo, err := s.ListPolicies(inFoo, "")
+	assert.Nil(t, err)
+	assert.Len(t, foo.Rules, 2)
+	_, err = s.ListPolicies(inFoo, "other")
+	assert.Equal(t, errOtherDomain, err)
+
+	member := &authzProto.PolicyRule{Ptype: "g", Values: []string{"u1", "role:" + roleUUID, "foo.bar"}}
+	_, err = s.AddPolicy(ctx, member)
+	assert.Equal(t, errNoWorkspace, err)
+	_, err = s.AddPolicy(inOther, member)
+	assert.Equal(t, errOtherDomain, err)
+	_, err = s.AddPolicy(inFoo, &authzProto.PolicyRule{Ptype: "g2", Values: []string{"u1", "role:admin"}})
+	assert.Equal(t, errOtherDomain, err)
+	_, err = s.AddPolicy(inFoo, &authzProto.PolicyRule{Ptype: "p", Values: []string{"role:*", "*", "*", "*", "*", "allow"}})
+	assert.Equal(t, errOtherDomain, err)
+
+	// the subjects are the roles and the members of the workspace
+	_, err = s.AddPolicy(inFoo, &authzProto.PolicyRule{Ptype: "g", Values: []string{"u2", "role:" + roleUUID, "foo.bar"}})
+	assert.Equal(t, errOtherSubject, err)
+	_, err = s.AddPolicy(inFoo, &authzProto.PolicyRule{Ptype: "g", Values: []string{"u1", "role:admin", "foo.bar"}})
+	assert.Equal(t, errOtherSubject, err)
+	_, err = s.AddPolicy(inFoo, &authzProto.PolicyRule{Ptype: "p", Values: []string{"u2", "foo.bar", "issues", "*", "*", "allow"}})
+	assert.Equal(t, errOtherSubject, err)
+	_, err = s.AddPolicy(inFoo, &authzProto.PolicyRule{Ptype: "p", Values: []string{"u1", "foo.bar", "issues", "*", "*", "allow"}})
+	assert.Nil(t, err)
+
+	res, err := s.AddPolicy(inFoo, member)
+	assert.Nil(t, err)
+	assert.Equal(t, member, res)
+	_, err = s.AddPolicy(inFoo, member)
+	assert.Equal(t, errRuleExists, err)
+	foo, _ = s.ListPolicies(inFoo, "")
+	assert.Len(t, foo.Rules, 4)
+
+	assert.Nil(t, s.RemovePolicy(inFoo, member))
+	assert.Equal(t, errRuleNotFound, s.RemovePolicy(inFoo, member))
+	// the rules of the former members can be removed
+	assert.Nil(t, s.RemovePolicy(inFoo, &authzProto.PolicyRule{Ptype: "p", Values: []string{"role:owner", "foo.bar", "users", "*", "*", "allow"}}))
+}
+
+func Test_service_CheckPermission(t *testing.T) {
+	e := newTestEnforcer(t)
+	_, err := e.AddPolicy("role:owner", "foo.bar", "users", "delete", adminUUID, "deny")
+	assert.Nil(t, err)
+	workspacesSrv := workspaces.NewServiceForTest()
+	s := NewService(e, workspacesSrv)
+	ctx := context.Background()
+	foo, err := workspacesSrv.Create(ctx, &workspacesProto.CreateWorkspaceRequest{Title: "foo", Domain: "foo.bar"})
+	assert.Nil(t, err)
+	bar, err := workspacesSrv.Create(ctx, &workspacesProto.CreateWorkspaceRequest{Title: "bar", Domain: "bar.baz"})
+	assert.Nil(t, err)
+	inFoo := auth.ContextWithWorkspace(ctx, foo)
+	asOwner := auth.ContextWithUser(inFoo, &usersProto.User{Uuid: ownerUUID})
+	asAdmin := auth.ContextWithUser(inFoo, &usersProto.User{Uuid: adminUUID})
+	outside := auth.ContextWithUser(ctx, &usersProto.User{Uuid: ownerUUID})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		req     *authzProto.CheckPermissionRequest
+		allowed bool
+		rule    []string
+		wantErr error
+	}{
+		{"allowed", asOwner, &authzProto.CheckPermissionRequest{WorkspaceUuid: foo.Uuid, Resource: "users", Action: "update", Object: "u1"},
+			true, []string{"role:owner", "foo.bar", "users", "*", "*", "allow"}, nil},
+		{"denied by rule", asOwner, &authzProto.CheckPermissionRequest{WorkspaceUuid: foo.Uuid, Resource: "users", Action: "delete", Object: adminUUID},
+			false, []string{"role:owner", "foo.bar", "users", "delete", adminUUID, "deny"}, nil},
+		{"no rule", asOwner, &authzProto.CheckPermissionRequest{Resource: "users", Action: "update", Object: "u1"},
+			false, nil, nil},
+		{"other user", asAdmin, &authzProto.CheckPermissionRequest{UserUuid: ownerUUID, WorkspaceUuid: foo.Uuid, Resource: "users", Action: "get"},
+			true, []string{"role:owner", "foo.bar", "users", "*", "*", "allow"}, nil},
+		{"other user not allowed", asOwner, &authzProto.CheckPermissionRequest{UserUuid: adminUUID, Resource: "users", Action: "get"},
+			false, nil, errCheckNotAllowed},
+		{"other workspace", asOwner, &authzProto.CheckPermissionRequest{WorkspaceUuid: bar.Uuid, Resource: "users", Action: "get"},
+			false, nil, errCheckOtherWorkspace},
+		{"no workspace", outside, &authzProto.CheckPermissionRequest{WorkspaceUuid: foo.Uuid, Resource: "users", Action: "get"},
+			false, nil, errNoWorkspace},
+		{"no workspace global check", outside, &authzProto.CheckPermissionRequest{Resource: "users", Action: "update", Object: "u1"},
+			false, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.CheckPermission(tt.ctx, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.allowed, res.Allowed)
+			if tt.rule == nil {
+				assert.Nil(t, res.MatchedRule)
+			} else {
+				assert.Equal(t, tt.rule, res.MatchedRule.Values)
+			}
+		})
+	}
+
+	_, err = s.CheckPermission(asOwner, &authzProto.CheckPermissionRequest{Resource: "users"})
+	assert.NotNil(t, err)
+	_, err = s.CheckPermission(ctx, &authzProto.CheckPermissionRequest{Resource: "users", Action: "get"})
+	assert.NotNil(t, err)
+}
+
+func TestRoleSync(t *testing.T) {
+	e := newTestEnforcer(t)
+	r := NewRoleSync(e)
+	_, err := e.AddNamedGroupingPolicy("g2", "u1", "role:other")
+	assert.Nil(t, err)
+
+	managed := []string{"role:admin", "role:auditor"}
+	assert.Nil(t, r.SyncRoles("u1", []string{"role:admin"}, managed))
+	assert.True(t, e.HasNamedGroupingPolicy("g2", "u1", "role:admin"))
+	assert.False(t, e.HasNamedGroupingPolicy("g2", "u1", "role:auditor"))
+	// syncing again changes nothing
+	assert.Nil(t, r.SyncRoles("u1", []string{"role:admin"}, managed))
+
+	// the roles granted outside of the sync are kept
+	assert.Nil(t, r.SyncRoles("u1", nil, managed))
+	assert.False(t, e.HasNamedGroupingPolicy("g2", "u1", "role:admin"))
+	assert.True(t, e.HasNamedGroupingPolicy("g2", "u1", "role:other"))
+}
@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"context"
+	"sync"
+
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/pkg/db"
+	"github.com/mirzakhany/pm/pkg/log"
+)
+
+// policyChannel is the postgres notification channel the policy changes are announced on
+const policyChannel = "casbin_rules"
+
+// watcher reloads the policy of the enforcer when another server instance changes it.
+// Changes are announced with postgres NOTIFY, the payload being the id of the announcing instance
+// so an instance does not reload the changes it made itself.
+type watcher struct {
+	db       *db.DB
+	id       string
+	mu       sync.Mutex
+	callback func(string)
+	cancel   context.CancelFunc
+}
+
+var _ persist.Watcher = (*watcher)(nil)
+
+// NewWatcher creates a watcher listening for policy changes until the context is done.
+func NewWatcher(ctx context.Context, db *db.DB) persist.Watcher {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &watcher{db: db, id: uuid.New().String(), cancel: cancel}
+	go w.listen(ctx)
+	return w
+}
+
+func (w *watcher) listen(ctx context.Context) {
+	ln := w.db.DB().Listen(ctx, policyChannel)
+	defer func() { _ = ln.Close() }()
+
+	ch := ln.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-ch:
+			if !ok {
+				return
+			}
+			if n.Payload == w.id {
+				continue
+			}
+			w.mu.Lock()
+			callback := w.callback
+			w.mu.Unlock()
+			if callback != nil {
+				callback(n.Payload)
+			}
+		}
+	}
+}
+
+// SetUpdateCallback sets the function called when the policy is changed by another instance.
+func (w *watcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Update announces a policy change to the other instances.
+func (w *watcher) Update() error {
+	_, err := w.db.DB().Exec("SELECT pg_notify(?, ?)", policyChannel, w.id)
+	if err != nil {
+		log.Error("announce policy change failed", log.Err(err))
+	}
+	return err
+}
+
+// Close stops listening for policy changes.
+func (w *watcher) Close() {
+	w.cancel()
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/grpcgw"
 	"github.com/mirzakhany/pm/protobuf/roles"
 	"google.golang.org/grpc"
@@ -59,7 +60,7 @@ func (a api) CreateRole(ctx context.Context, request *roles.CreateRoleRequest) (
 func (a api) UpdateRole(ctx context.Context, request *roles.UpdateRoleRequest) (*roles.Role, error) {
 	res, err := a.service.Update(ctx, request)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, errorStatus(err)
 	}
 	return res, err
 }
@@ -67,13 +68,56 @@ func (a api) UpdateRole(ctx context.Context, request *roles.UpdateRoleRequest) (
 func (a api) DeleteRole(ctx context.Context, request *roles.DeleteRoleRequest) (*empty.Empty, error) {
 	_, err := a.service.Delete(ctx, request.Uuid)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, errorStatus(err)
 	}
 	return nil, err
 }
 
+func (a api) AssignRole(ctx context.Context, request *roles.AssignRoleRequest) (*empty.Empty, error) {
+	err := a.service.Assign(ctx, request.RoleUuid, request.UserUuid)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) UnassignRole(ctx context.Context, request *roles.UnassignRoleRequest) (*empty.Empty, error) {
+	err := a.service.Unassign(ctx, request.RoleUuid, request.UserUuid)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return &empty.Empty{}, nil
+}
+
+// errorStatus maps the errors of the service to grpc statuses.
+func errorStatus(err error) error {
+	switch err {
+	case errBuiltinRole:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errNotMember:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errAlreadyAssigned:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errNotAssigned:
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
 func New(srv Service) API {
 	s := api{service: srv}
 	grpcgw.RegisterController(s)
 	return s
 }
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/rolesV1.RoleService/ListRoles":    auth.Require("roles", "list"),
+		"/rolesV1.RoleService/GetRole":      auth.Require("roles", "get"),
+		"/rolesV1.RoleService/CreateRole":   auth.Require("roles", "create"),
+		"/rolesV1.RoleService/UpdateRole":   auth.Require("roles", "update"),
+		"/rolesV1.RoleService/DeleteRole":   auth.Require("roles", "delete"),
+		"/rolesV1.RoleService/AssignRole":   auth.Require("roles", "assign"),
+		"/rolesV1.RoleService/UnassignRole": auth.Require("roles", "unassign"),
+	})
+}
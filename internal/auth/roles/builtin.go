@@ -0,0 +1,160 @@
+package roles
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	workspacesSrv "github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	rolesProto "github.com/mirzakhany/pm/protobuf/roles"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+// Titles of the builtin roles every workspace has
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// readPermissions allow reading the work of the workspace.
+// The users and their sessions are not part of a workspace, they are managed by the global roles.
+var readPermissions = []*rolesProto.Permission{
+	{Resource: "workspaces", Action: "get"},
+	{Resource: "settings", Action: "get"},
+	{Resource: "members", Action: "list"},
+	{Resource: "roles", Action: "get"},
+	{Resource: "roles", Action: "list"},
+	{Resource: "cycles", Action: "get"},
+	{Resource: "cycles", Action: "list"},
+	{Resource: "capacities", Action: "list"},
+	{Resource: "issues", Action: "get"},
+	{Resource: "issues", Action: "list"},
+	{Resource: "statuses", Action: "get"},
+	{Resource: "statuses", Action: "list"},
+	{Resource: "reports", Action: "get"},
+	{Resource: "acls", Action: "get"},
+}
+
+// builtinRoles are created with every workspace, in the order of decreasing permissions
+var builtinRoles = []struct {
+	title       string
+	permissions []*rolesProto.Permission
+}{
+	{RoleOwner, []*rolesProto.Permission{
+		// every permission in the workspace, the global methods are not checked in its domain
+		{Resource: "*", Action: "*"},
+	}},
+	{RoleAdmin, append([]*rolesProto.Permission{
+		{Resource: "workspaces", Action: "update"},
+		{Resource: "settings", Action: "update"},
+		{Resource: "quotas", Action: "get"},
+		{Resource: "members", Action: "*"},
+		{Resource: "roles", Action: "*"},
+		{Resource: "policies", Action: "*"},
+		{Resource: "archive", Action: "export"},
+		{Resource: "acls", Action: "*"},
+		{Resource: "cycles", Action: "*"},
+		{Resource: "capacities", Action: "*"},
+		{Resource: "issues", Action: "*"},
+		{Resource: "statuses", Action: "*"},
+		{Resource: "reports", Action: "*"},
+	}, readPermissions...)},
+	{RoleMember, append([]*rolesProto.Permission{
+		{Resource: "capacities", Action: "update"},
+		{Resource: "issues", Action: "*"},
+		{Resource: "reports", Action: "export"},
+		{Resource: "acls", Action: "update"},
+	}, readPermissions...)},
+	{RoleViewer, readPermissions},
+}
+
+// seedBuiltinRoles creates the builtin roles in the workspace and returns them by title.
+func (s service) seedBuiltinRoles(ctx context.Context, workspace *workspacesProto.Workspace) (map[string]string, error) {
+	roleUUIDs := make(map[string]string)
+	now := time.Now()
+	for _, r := range builtinRoles {
+		id := uuid.New().String()
+		err := s.repo.Create(ctx, entity.Role{
+			UUID:      id,
+			Title:     r.title,
+			Builtin:   true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.setPermissions(workspace.Domain, id, r.permissions); err != nil {
+			return nil, err
+		}
+		roleUUIDs[r.title] = id
+	}
+	return roleUUIDs, nil
+}
+
+// WorkspaceCreated creates the builtin roles of the workspace and gives the owner role to the user creating it.
+func (s service) WorkspaceCreated(ctx context.Context, workspace *workspacesProto.Workspace, owner *usersProto.User) error {
+	ctx = auth.ContextWithWorkspace(ctx, workspace)
+	roleUUIDs, err := s.seedBuiltinRoles(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if owner == nil {
+		return nil
+	}
+	_, err = s.enforcer.AddNamedGroupingPolicy("g", owner.Uuid, subject(roleUUIDs[RoleOwner]), workspace.Domain)
+	return err
+}
+
+// memberRole returns the role with the specified UUID in the workspace of the request,
+// or the builtin member role if the UUID is empty.
+func (s service) memberRole(ctx context.Context, roleUUID string) (entity.Role, error) {
+	if roleUUID != "" {
+		return s.repo.Get(ctx, roleUUID)
+	}
+	return s.repo.GetBuiltin(ctx, RoleMember)
+}
+
+// MemberInvited returns workspaces.ErrRoleNotGrantable if the inviter does not have every permission of the role
+// with the specified UUID, or of the builtin member role if the UUID is empty, in the workspace.
+func (s service) MemberInvited(ctx context.Context, workspace *workspacesProto.Workspace, inviter *usersProto.User, roleUUID string) error {
+	role, err := s.memberRole(auth.ContextWithWorkspace(ctx, workspace), roleUUID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range s.enforcer.GetFilteredNamedPolicy("p", 0, subject(role.UUID)) {
+		if len(rule) < 6 || rule[5] != "allow" {
+			continue
+		}
+		ok, err := s.enforcer.Enforce(inviter.Uuid, workspace.Domain, rule[2], rule[3], rule[4])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return workspacesSrv.ErrRoleNotGrantable
+		}
+	}
+	return nil
+}
+
+// MemberJoined gives the role with the specified UUID to the new member of the workspace,
+// or the builtin member role if the UUID is empty.
+func (s service) MemberJoined(ctx context.Context, workspace *workspacesProto.Workspace, user *usersProto.User, roleUUID string) error {
+	role, err := s.memberRole(auth.ContextWithWorkspace(ctx, workspace), roleUUID)
+	if err != nil {
+		return err
+	}
+	_, err = s.enforcer.AddNamedGroupingPolicy("g", user.Uuid, subject(role.UUID), workspace.Domain)
+	return err
+}
+
+// MemberRemoved takes all the roles of the workspace from the member.
+func (s service) MemberRemoved(ctx context.Context, workspace *workspacesProto.Workspace, userUUID string) error {
+	_, err := s.enforcer.RemoveFilteredNamedGroupingPolicy("g", 0, userUUID, "", workspace.Domain)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/mirzakhany/pm/internal/entity"
 
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/db"
 )
 
@@ -22,6 +23,10 @@ type Repository interface {
 	Update(ctx context.Context, role entity.Role) error
 	// Delete removes the role with given UUID from the storage.
 	Delete(ctx context.Context, uuid string) error
+	// GetBuiltin returns the builtin role with the specified title.
+	GetBuiltin(ctx context.Context, title string) (entity.Role, error)
+	// IsMember returns true if the user with the specified UUID is a member of the current workspace.
+	IsMember(ctx context.Context, userUUID string) (bool, error)
 }
 
 // repository persists roles in database
@@ -34,23 +39,39 @@ func NewRepository(db *db.DB) Repository {
 	return repository{db}
 }
 
-// Get reads the role with the specified ID from the database.
+// Get reads the role with the specified UUID in the current workspace from the database.
 func (r repository) Get(ctx context.Context, uuid string) (entity.Role, error) {
 	var role entity.Role
-	err := r.db.With(ctx).Model(&role).Where("uuid = ?", uuid).First()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return role, err
+	}
+	err = r.db.With(ctx).Model(&role).
+		Where("uuid = ?", uuid).
+		Where("workspace_id = ?", workspace.Id).
+		First()
 	return role, err
 }
 
-// Create saves a new role record in the database.
-// It returns the ID of the newly inserted role record.
+// Create saves a new role record in the current workspace.
 func (r repository) Create(ctx context.Context, role entity.Role) error {
-	_, err := r.db.With(ctx).Model(&role).Insert()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	role.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&role).Insert()
 	return err
 }
 
 // Update saves the changes to an role in the database.
 func (r repository) Update(ctx context.Context, role entity.Role) error {
-	_, err := r.db.With(ctx).Model(&role).WherePK().Update()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	role.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&role).WherePK().Where("workspace_id = ?", workspace.Id).Update()
 	return err
 }
 
@@ -64,18 +85,53 @@ func (r repository) Delete(ctx context.Context, uuid string) error {
 	return err
 }
 
-// Count returns the number of the role records in the database.
+// Count returns the number of the role records in the current workspace.
 func (r repository) Count(ctx context.Context) (int64, error) {
-	var count int
-	count, err := r.db.With(ctx).Model((*entity.Role)(nil)).Count()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count, err := r.db.With(ctx).Model((*entity.Role)(nil)).Where("workspace_id = ?", workspace.Id).Count()
 	return int64(count), err
 }
 
-// Query retrieves the role records with the specified offset and limit from the database.
+// Query retrieves the role records of the current workspace with the specified offset and limit from the database.
 func (r repository) Query(ctx context.Context, offset, limit int64) ([]entity.Role, int, error) {
 	var _roles []entity.Role
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
 	count, err := r.db.With(ctx).Model(&_roles).
+		Where("workspace_id = ?", workspace.Id).
 		Order("id ASC").Limit(int(limit)).
 		Offset(int(offset)).SelectAndCount()
 	return _roles, count, err
 }
+
+// GetBuiltin reads the builtin role with the specified title in the current workspace from the database.
+func (r repository) GetBuiltin(ctx context.Context, title string) (entity.Role, error) {
+	var role entity.Role
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return role, err
+	}
+	err = r.db.With(ctx).Model(&role).
+		Where("title = ?", title).
+		Where("builtin = TRUE").
+		Where("workspace_id = ?", workspace.Id).
+		First()
+	return role, err
+}
+
+// IsMember checks the membership of the user with the specified UUID in the current workspace.
+func (r repository) IsMember(ctx context.Context, userUUID string) (bool, error) {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return false, err
+	}
+	return r.db.With(ctx).Model((*entity.WorkspaceMember)(nil)).
+		Where("workspace_id = ?", workspace.Id).
+		Where("user_id = (SELECT id FROM users WHERE uuid = ?)", userUUID).
+		Exists()
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/go-pg/pg"
 	"github.com/google/uuid"
 	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/db"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +19,8 @@ func TestRepository(t *testing.T) {
 	db.ResetTables(t, database, "roles")
 	repo := NewRepository(database)
 
-	ctx := context.Background()
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 1})
+	otherCtx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 2})
 	// initial count
 	count, err := repo.Count(ctx)
 	assert.Nil(t, err)
@@ -41,6 +44,8 @@ func TestRepository(t *testing.T) {
 	assert.Equal(t, "admin", role.Title)
 	_, err = repo.Get(ctx, "test0")
 	assert.EqualError(t, pg.ErrNoRows, err.Error())
+	_, err = repo.Get(otherCtx, testUuid)
+	assert.EqualError(t, pg.ErrNoRows, err.Error())
 
 	// update
 	err = repo.Update(ctx, entity.Role{
@@ -2,12 +2,19 @@ package roles
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	casbin "github.com/casbin/casbin/v2"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/google/uuid"
 	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
 	rolesProto "github.com/mirzakhany/pm/protobuf/roles"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
 )
 
 // Service encapsulates use case logic for roles.
@@ -18,12 +25,52 @@ type Service interface {
 	Create(ctx context.Context, input *rolesProto.CreateRoleRequest) (*rolesProto.Role, error)
 	Update(ctx context.Context, input *rolesProto.UpdateRoleRequest) (*rolesProto.Role, error)
 	Delete(ctx context.Context, uuid string) (*rolesProto.Role, error)
+
+	// Assign gives the role to a member of the current workspace
+	Assign(ctx context.Context, roleUUID, userUUID string) error
+	// Unassign takes the role from a member of the current workspace
+	Unassign(ctx context.Context, roleUUID, userUUID string) error
+
+	// WorkspaceCreated creates the builtin roles of a new workspace and makes the user creating it its owner
+	WorkspaceCreated(ctx context.Context, workspace *workspacesProto.Workspace, owner *usersProto.User) error
+	// MemberInvited rejects the invitations with a role the inviter does not have every permission of
+	MemberInvited(ctx context.Context, workspace *workspacesProto.Workspace, inviter *usersProto.User, roleUUID string) error
+	// MemberJoined gives the invited role, or the builtin member role, to a new member of the workspace
+	MemberJoined(ctx context.Context, workspace *workspacesProto.Workspace, user *usersProto.User, roleUUID string) error
+	// MemberRemoved takes all the roles of the workspace from the removed member
+	MemberRemoved(ctx context.Context, workspace *workspacesProto.Workspace, userUUID string) error
 }
 
+var (
+	errBuiltinRole     = errors.New("builtin roles cannot be changed")
+	errNotMember       = errors.New("user is not a member of the workspace")
+	errAlreadyAssigned = errors.New("role is already assigned to the user")
+	errNotAssigned     = errors.New("role is not assigned to the user")
+)
+
+// permissionsRule validates the permissions of a role.
+var permissionsRule = validation.By(func(value interface{}) error {
+	permissions, _ := value.([]*rolesProto.Permission)
+	for i, p := range permissions {
+		if p == nil {
+			return fmt.Errorf("%d: permission cannot be blank", i)
+		}
+		err := validation.ValidateStruct(p,
+			validation.Field(&p.Resource, validation.Required, validation.Length(0, 64)),
+			validation.Field(&p.Action, validation.Required, validation.Length(0, 64)),
+		)
+		if err != nil {
+			return fmt.Errorf("%d: %w", i, err)
+		}
+	}
+	return nil
+})
+
 // ValidateCreateRequest validates the CreateRoleRequest fields.
 func ValidateCreateRequest(c *rolesProto.CreateRoleRequest) error {
 	return validation.ValidateStruct(c,
 		validation.Field(&c.Title, validation.Required, validation.Length(0, 128)),
+		validation.Field(&c.Permissions, permissionsRule),
 	)
 }
 
@@ -31,16 +78,71 @@ func ValidateCreateRequest(c *rolesProto.CreateRoleRequest) error {
 func ValidateUpdateRequest(u *rolesProto.UpdateRoleRequest) error {
 	return validation.ValidateStruct(u,
 		validation.Field(&u.Title, validation.Required, validation.Length(0, 128)),
+		validation.Field(&u.Permissions, permissionsRule),
 	)
 }
 
+// ValidateAssignment validates the UUIDs of a role assignment.
+func ValidateAssignment(roleUUID, userUUID string) error {
+	if err := validation.Validate(roleUUID, validation.Required, is.UUID); err != nil {
+		return errors.New("role_uuid: " + err.Error())
+	}
+	if err := validation.Validate(userUUID, validation.Required, is.UUID); err != nil {
+		return errors.New("user_uuid: " + err.Error())
+	}
+	return nil
+}
+
 type service struct {
-	repo Repository
+	repo     Repository
+	enforcer *casbin.SyncedEnforcer
 }
 
 // NewService creates a new role service.
-func NewService(repo Repository) Service {
-	return service{repo}
+// The permissions and the assignments of the roles are rules of the enforcer policy.
+func NewService(repo Repository, enforcer *casbin.SyncedEnforcer) Service {
+	return service{repo, enforcer}
+}
+
+// subject returns the policy subject of the role with the specified UUID.
+func subject(roleUUID string) string {
+	return "role:" + roleUUID
+}
+
+// permissions returns the permissions of the role with the specified UUID.
+func (s service) permissions(roleUUID string) []*rolesProto.Permission {
+	var res []*rolesProto.Permission
+	for _, rule := range s.enforcer.GetFilteredNamedPolicy("p", 0, subject(roleUUID)) {
+		res = append(res, &rolesProto.Permission{Resource: rule[2], Action: rule[3]})
+	}
+	return res
+}
+
+// setPermissions replaces the permissions of the role with the specified UUID in the domain.
+func (s service) setPermissions(domain, roleUUID string, permissions []*rolesProto.Permission) error {
+	if _, err := s.enforcer.RemoveFilteredNamedPolicy("p", 0, subject(roleUUID)); err != nil {
+		return err
+	}
+	var rules [][]string
+	seen := make(map[[2]string]bool)
+	for _, p := range permissions {
+		if seen[[2]string{p.Resource, p.Action}] {
+			continue
+		}
+		seen[[2]string{p.Resource, p.Action}] = true
+		rules = append(rules, []string{subject(roleUUID), domain, p.Resource, p.Action, "*", "allow"})
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	_, err := s.enforcer.AddNamedPolicies("p", rules)
+	return err
+}
+
+func (s service) toProto(role entity.Role) *rolesProto.Role {
+	res := role.ToProto()
+	res.Permissions = s.permissions(role.UUID)
+	return res
 }
 
 // Get returns the role with the specified the role UUID.
@@ -49,7 +151,7 @@ func (s service) Get(ctx context.Context, UUID string) (*rolesProto.Role, error)
 	if err != nil {
 		return nil, err
 	}
-	return role.ToProto(), nil
+	return s.toProto(role), nil
 }
 
 // Create creates a new role.
@@ -57,9 +159,13 @@ func (s service) Create(ctx context.Context, req *rolesProto.CreateRoleRequest)
 	if err := ValidateCreateRequest(req); err != nil {
 		return nil, err
 	}
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	id := uuid.New().String()
-	err := s.repo.Create(ctx, entity.Role{
+	err = s.repo.Create(ctx, entity.Role{
 		UUID:      id,
 		Title:     req.Title,
 		CreatedAt: now,
@@ -68,6 +174,9 @@ func (s service) Create(ctx context.Context, req *rolesProto.CreateRoleRequest)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.setPermissions(workspace.Domain, id, req.Permissions); err != nil {
+		return nil, err
+	}
 	return s.Get(ctx, id)
 }
 
@@ -76,11 +185,18 @@ func (s service) Update(ctx context.Context, req *rolesProto.UpdateRoleRequest)
 	if err := ValidateUpdateRequest(req); err != nil {
 		return nil, err
 	}
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	role, err := s.repo.Get(ctx, req.Uuid)
 	if err != nil {
 		return nil, err
 	}
+	if role.Builtin {
+		return nil, errBuiltinRole
+	}
 	now := time.Now()
 	role.Title = req.Title
 	role.UpdatedAt = now
@@ -96,18 +212,30 @@ func (s service) Update(ctx context.Context, req *rolesProto.UpdateRoleRequest)
 	if err := s.repo.Update(ctx, roleModel); err != nil {
 		return nil, err
 	}
-	return role.ToProto(), nil
+	if err := s.setPermissions(workspace.Domain, role.UUID, req.Permissions); err != nil {
+		return nil, err
+	}
+	return s.toProto(role), nil
 }
 
-// Delete deletes the role with the specified UUID.
+// Delete deletes the role with the specified UUID, taking it from the users it is assigned to.
 func (s service) Delete(ctx context.Context, UUID string) (*rolesProto.Role, error) {
 	role, err := s.Get(ctx, UUID)
 	if err != nil {
 		return nil, err
 	}
+	if role.Builtin {
+		return nil, errBuiltinRole
+	}
 	if err = s.repo.Delete(ctx, UUID); err != nil {
 		return nil, err
 	}
+	if _, err := s.enforcer.RemoveFilteredNamedPolicy("p", 0, subject(UUID)); err != nil {
+		return nil, err
+	}
+	if _, err := s.enforcer.RemoveFilteredNamedGroupingPolicy("g", 1, subject(UUID)); err != nil {
+		return nil, err
+	}
 	return role, nil
 }
 
@@ -122,10 +250,68 @@ func (s service) Query(ctx context.Context, offset, limit int64) (*rolesProto.Li
 	if err != nil {
 		return nil, err
 	}
-	return &rolesProto.ListRolesResponse{
-		Roles:      entity.RoleToProtoList(items),
+	res := &rolesProto.ListRolesResponse{
 		TotalCount: int64(count),
 		Offset:     offset,
 		Limit:      limit,
-	}, nil
+	}
+	for _, item := range items {
+		res.Roles = append(res.Roles, s.toProto(item))
+	}
+	return res, nil
+}
+
+// Assign gives the role with the specified UUID to the member of the current workspace.
+func (s service) Assign(ctx context.Context, roleUUID, userUUID string) error {
+	if err := ValidateAssignment(roleUUID, userUUID); err != nil {
+		return err
+	}
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	role, err := s.repo.Get(ctx, roleUUID)
+	if err != nil {
+		return err
+	}
+	member, err := s.repo.IsMember(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return errNotMember
+	}
+
+	added, err := s.enforcer.AddNamedGroupingPolicy("g", userUUID, subject(role.UUID), workspace.Domain)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return errAlreadyAssigned
+	}
+	return nil
+}
+
+// Unassign takes the role with the specified UUID from the member of the current workspace.
+func (s service) Unassign(ctx context.Context, roleUUID, userUUID string) error {
+	if err := ValidateAssignment(roleUUID, userUUID); err != nil {
+		return err
+	}
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	role, err := s.repo.Get(ctx, roleUUID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.enforcer.RemoveNamedGroupingPolicy("g", userUUID, subject(role.UUID), workspace.Domain)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errNotAssigned
+	}
+	return nil
 }
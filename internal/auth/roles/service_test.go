@@ -5,9 +5,15 @@ import (
 	"errors"
 	"testing"
 
+	casbin "github.com/casbin/casbin/v2"
 	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/auth/authz"
+	workspacesSrv "github.com/mirzakhany/pm/internal/auth/workspaces"
 	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/protobuf/roles"
+	"github.com/mirzakhany/pm/protobuf/users"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,6 +27,7 @@ func TestCreateRoleRequest_Validate(t *testing.T) {
 	}{
 		{"success", roles.CreateRoleRequest{Title: "test"}, false},
 		{"required", roles.CreateRoleRequest{Title: ""}, true},
+		{"permission", roles.CreateRoleRequest{Title: "test", Permissions: []*roles.Permission{{Resource: "issues"}}}, true},
 		{"too long", roles.CreateRoleRequest{Title: "1234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890"}, true},
 	}
 	for _, tt := range tests {
@@ -49,9 +56,17 @@ func TestUpdateRoleRequest_Validate(t *testing.T) {
 	}
 }
 
+func newTestEnforcer(t *testing.T) *casbin.SyncedEnforcer {
+	e, err := authz.NewEnforcerForTest("../../../configs/rbac.conf", "../../../configs/sample_policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
 func Test_service_CRUD(t *testing.T) {
-	s := NewService(&mockRepository{})
-	ctx := context.Background()
+	s := NewService(&mockRepository{}, newTestEnforcer(t))
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 1, Domain: "foo.bar"})
 
 	// initial count
 	count, _ := s.Count(ctx)
@@ -123,8 +138,95 @@ func Test_service_CRUD(t *testing.T) {
 	assert.Equal(t, int64(1), count)
 }
 
+func Test_service_Permissions(t *testing.T) {
+	e := newTestEnforcer(t)
+	repo := &mockRepository{members: map[string]bool{"6ba7b810-9dad-11d1-80b4-00c04fd430c8": true}}
+	s := NewService(repo, e)
+	workspace := &workspaces.Workspace{Id: 1, Domain: "foo.bar"}
+	ctx := auth.ContextWithWorkspace(context.Background(), workspace)
+	owner := &users.User{Uuid: "1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed"}
+	member := &users.User{Uuid: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}
+
+	// builtin roles are created with the workspace
+	assert.Nil(t, s.WorkspaceCreated(ctx, workspace, owner))
+	count, _ := s.Count(ctx)
+	assert.Equal(t, int64(len(builtinRoles)), count)
+	ok, _ := e.Enforce(owner.Uuid, "foo.bar", "workspaces", "delete", "w1")
+	assert.True(t, ok)
+	ok, _ = e.Enforce(owner.Uuid, "other", "workspaces", "delete", "w1")
+	assert.False(t, ok)
+
+	builtin, _ := repo.GetBuiltin(ctx, RoleViewer)
+	_, err := s.Update(ctx, &roles.UpdateRoleRequest{Uuid: builtin.UUID, Title: "test"})
+	assert.Equal(t, errBuiltinRole, err)
+	_, err = s.Delete(ctx, builtin.UUID)
+	assert.Equal(t, errBuiltinRole, err)
+
+	// new members get the member role
+	assert.Nil(t, s.MemberJoined(ctx, workspace, member, ""))
+	ok, _ = e.Enforce(member.Uuid, "foo.bar", "issues", "create", "")
+	assert.True(t, ok)
+	ok, _ = e.Enforce(member.Uuid, "foo.bar", "cycles", "create", "")
+	assert.False(t, ok)
+
+	// members invite with the roles they have every permission of only
+	owned, _ := repo.GetBuiltin(ctx, RoleOwner)
+	admin, _ := repo.GetBuiltin(ctx, RoleAdmin)
+	assert.Nil(t, s.MemberInvited(ctx, workspace, owner, owned.UUID))
+	assert.Nil(t, s.MemberInvited(ctx, workspace, member, ""))
+	assert.Nil(t, s.MemberInvited(ctx, workspace, member, builtin.UUID))
+	assert.Equal(t, workspacesSrv.ErrRoleNotGrantable, s.MemberInvited(ctx, workspace, member, admin.UUID))
+	assert.Equal(t, workspacesSrv.ErrRoleNotGrantable, s.MemberInvited(ctx, workspace, member, owned.UUID))
+
+	// custom role
+	_, err = s.Create(ctx, &roles.CreateRoleRequest{Title: "planner", Permissions: []*roles.Permission{{Resource: "", Action: "create"}}})
+	assert.NotNil(t, err)
+	role, err := s.Create(ctx, &roles.CreateRoleRequest{Title: "planner", Permissions: []*roles.Permission{
+		{Resource: "cycles", Action: "create"},
+		{Resource: "cycles", Action: "create"},
+	}})
+	assert.Nil(t, err)
+	assert.Len(t, role.Permissions, 1)
+	assert.False(t, role.Builtin)
+
+	assert.NotNil(t, s.Assign(ctx, role.Uuid, "none"))
+	assert.Equal(t, errNotMember, s.Assign(ctx, role.Uuid, owner.Uuid))
+	assert.Nil(t, s.Assign(ctx, role.Uuid, member.Uuid))
+	assert.Equal(t, errAlreadyAssigned, s.Assign(ctx, role.Uuid, member.Uuid))
+	ok, _ = e.Enforce(member.Uuid, "foo.bar", "cycles", "create", "")
+	assert.True(t, ok)
+
+	role, err = s.Update(ctx, &roles.UpdateRoleRequest{Uuid: role.Uuid, Title: "planner", Permissions: []*roles.Permission{
+		{Resource: "cycles", Action: "update"},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, []*roles.Permission{{Resource: "cycles", Action: "update"}}, role.Permissions)
+	ok, _ = e.Enforce(member.Uuid, "foo.bar", "cycles", "create", "")
+	assert.False(t, ok)
+	ok, _ = e.Enforce(member.Uuid, "foo.bar", "cycles", "update", "")
+	assert.True(t, ok)
+
+	assert.Nil(t, s.Unassign(ctx, role.Uuid, member.Uuid))
+	assert.Equal(t, errNotAssigned, s.Unassign(ctx, role.Uuid, member.Uuid))
+	ok, _ = e.Enforce(member.Uuid, "foo.bar", "cycles", "update", "")
+	assert.False(t, ok)
+
+	// deleting a role removes its rules
+	assert.Nil(t, s.Assign(ctx, role.Uuid, member.Uuid))
+	_, err = s.Delete(ctx, role.Uuid)
+	assert.Nil(t, err)
+	assert.Empty(t, e.GetFilteredNamedPolicy("p", 0, subject(role.Uuid)))
+	assert.Empty(t, e.GetFilteredNamedGroupingPolicy("g", 1, subject(role.Uuid)))
+
+	// removed members lose their roles
+	assert.Nil(t, s.MemberRemoved(ctx, workspace, member.Uuid))
+	ok, _ = e.Enforce(member.Uuid, "foo.bar", "issues", "get", "")
+	assert.False(t, ok)
+}
+
 type mockRepository struct {
-	items []entity.Role
+	items   []entity.Role
+	members map[string]bool
 }
 
 func (m mockRepository) Get(ctx context.Context, id string) (entity.Role, error) {
@@ -175,3 +277,16 @@ func (m *mockRepository) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+func (m mockRepository) GetBuiltin(ctx context.Context, title string) (entity.Role, error) {
+	for _, item := range m.items {
+		if item.Builtin && item.Title == title {
+			return item, nil
+		}
+	}
+	return entity.Role{}, pg.ErrNoRows
+}
+
+func (m mockRepository) IsMember(ctx context.Context, userUUID string) (bool, error) {
+	return m.members[userUUID], nil
+}
@@ -10,8 +10,8 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	pkgAuth "github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/grpcgw"
-	"github.com/mirzakhany/pm/pkg/kv"
 	"github.com/mirzakhany/pm/protobuf/users"
 	"google.golang.org/grpc"
 )
@@ -36,94 +36,295 @@ func (a api) InitGrpc(ctx context.Context, server *grpc.Server) {
 }
 
 func (a api) Logout(ctx context.Context, req *users.LogoutRequest) (*users.LogoutResponse, error) {
-	token, err := auth.ExtractToken(ctx)
-	if err != nil {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
+	if err != nil || principal.SessionID == "" {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
-	err = auth.DeleteToken(token, true /* isAccessToken */)
+	err = auth.Logout(principal.SessionID)
 	if err != nil {
-		log.Error("failed to remove token", log.Err(err))
+		log.Error("failed to remove session", log.Err(err))
 		return nil, status.Errorf(codes.Internal, "logout failed")
 	}
-	return nil, nil
+	return &users.LogoutResponse{}, nil
 }
 
 func (a api) VerifyToken(ctx context.Context, request *users.VerifyTokenRequest) (*users.VerifyTokenResponse, error) {
-	vTokens, err := auth.VerifyToken(request.AccessToken)
+	_, err := auth.Authenticate(request.AccessToken)
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
-	return &users.VerifyTokenResponse{AccessToken: vTokens}, nil
+	return &users.VerifyTokenResponse{AccessToken: request.AccessToken}, nil
 }
 
 func (a api) RefreshToken(ctx context.Context, request *users.RefreshTokenRequest) (*users.RefreshTokenResponse, error) {
+	tokens, err := auth.Refresh(ctx, request.RefreshToken, a.service.GetByUUID)
+	if err != nil {
+		switch err {
+		case auth.ErrInvalidToken, auth.ErrSessionExpired, auth.ErrTokenReused, auth.ErrUserDisabled:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		log.Error("error on refresh user token", log.Err(err))
+		return nil, status.Errorf(codes.Internal, "internal server error, refresh token")
+	}
+	return &users.RefreshTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func (a api) Login(ctx context.Context, request *users.LoginRequest) (*users.LoginResponse, error) {
+	if !passwordLogin.Bool() {
+		return nil, status.Error(codes.FailedPrecondition, "password login is disabled, login with single sign-on")
+	}
+
+	user, err := a.service.Authenticate(ctx, request.Username, request.Password)
+	switch err {
+	case nil:
+		return a.login(ctx, user, request.Device)
+	case ErrUnknownUser:
+		return nil, status.Errorf(codes.NotFound, "username %s not found", request.Username)
+	case ErrInvalidCredentials:
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	log.Error("failed to authenticate", log.String("user", request.Username), log.Err(err))
+	return nil, status.Errorf(codes.Unavailable, "authentication is unavailable")
+}
 
-	vToken, err := auth.VerifyToken(request.RefreshToken)
+// login signs in the user whose identity was checked, asking for their code first if they enabled two-factor authentication
+func (a api) login(ctx context.Context, user *users.User, device string) (*users.LoginResponse, error) {
+	if err := canLogin(user); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if user.TwoFactorEnabled {
+		challenge, err := a.service.LoginChallenge(ctx, user.Uuid, device)
+		if err != nil {
+			log.Error("error on create login challenge", log.String("user", user.Username), log.Err(err))
+			return nil, status.Errorf(codes.Internal, "internal server error, session")
+		}
+		return &users.LoginResponse{ChallengeToken: challenge}, nil
+	}
+	return startSession(ctx, user, device)
+}
+
+func startSession(ctx context.Context, user *users.User, device string) (*users.LoginResponse, error) {
+	tokens, err := auth.Login(user, auth.ClientFromContext(ctx, device))
+	if err == auth.ErrUserDisabled {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+		log.Error("error on create user session", log.String("user", user.Username), log.Err(err))
+		return nil, status.Errorf(codes.Internal, "internal server error, session")
 	}
+	return tokens, nil
+}
 
-	user, err := auth.ExtractSessionUser(request.RefreshToken)
+func (a api) LoginTOTP(ctx context.Context, request *users.LoginTOTPRequest) (*users.LoginResponse, error) {
+	user, device, err := a.service.LoginTOTP(ctx, request.ChallengeToken, request.Code)
+	if err == errTOTPLocked {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "session expired")
+		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
+	return startSession(ctx, user, device)
+}
 
-	dbUser, err := a.service.GetByUsername(ctx, user.Username)
+func (a api) SSOLogin(ctx context.Context, request *users.SSOLoginRequest) (*users.SSOLoginResponse, error) {
+	u, err := a.service.StartSSO(ctx, request.Device)
+	if err == errSSODisabled {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid user")
+		log.Error("failed to start single sign-on", log.Err(err))
+		return nil, status.Errorf(codes.Unavailable, "single sign-on is unavailable")
 	}
+	return &users.SSOLoginResponse{AuthorizationUrl: u}, nil
+}
 
-	if !dbUser.Enable {
-		return nil, status.Errorf(codes.Unauthenticated, "user is not active")
+func (a api) SSOCallback(ctx context.Context, request *users.SSOCallbackRequest) (*users.LoginResponse, error) {
+	if request.Error != "" {
+		return nil, status.Errorf(codes.Unauthenticated, "login at the identity provider failed: %s", request.Error)
+	}
+	user, device, err := a.service.CompleteSSO(ctx, request.State, request.Code)
+	switch err {
+	case nil:
+		return a.login(ctx, user, device)
+	case errSSODisabled:
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errInvalidSSOState:
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errProviderEmailUnverified, errLinkUnverified:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
 	}
-	err = auth.DeleteToken(vToken, false /* isAccessToken */)
+	log.Warn("single sign-on failed", log.Err(err))
+	return nil, status.Errorf(codes.Unauthenticated, "single sign-on failed")
+}
+
+func (a api) EnrollTOTP(ctx context.Context, request *users.EnrollTOTPRequest) (*users.EnrollTOTPResponse, error) {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
 	if err != nil {
-		log.Error("failed to remove token", log.Err(err))
-		return nil, status.Errorf(codes.Internal, "logout failed")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
+	res, err := a.service.EnrollTOTP(ctx, principal.User.Uuid)
+	if err != nil {
+		return nil, twoFactorError(err)
+	}
+	return res, nil
+}
 
-	tokens, err := auth.CreateToken(dbUser)
+func (a api) ConfirmTOTP(ctx context.Context, request *users.ConfirmTOTPRequest) (*users.RecoveryCodesResponse, error) {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	recoveryCodes, err := a.service.ConfirmTOTP(ctx, principal.User.Uuid, request.Code)
 	if err != nil {
-		log.Error("error on create user token", log.String("user", dbUser.Username), log.Err(err))
-		return nil, status.Errorf(codes.Internal, "internal server error, create token")
+		return nil, twoFactorError(err)
 	}
+	return &users.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
 
-	return &users.RefreshTokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-	}, nil
+func (a api) DisableTOTP(ctx context.Context, request *users.DisableTOTPRequest) (*empty.Empty, error) {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	if err := a.service.DisableTOTP(ctx, principal.User.Uuid, request.Code); err != nil {
+		return nil, twoFactorError(err)
+	}
+	return &empty.Empty{}, nil
 }
 
-func (a api) Login(ctx context.Context, request *users.LoginRequest) (*users.LoginResponse, error) {
+func (a api) RegenerateRecoveryCodes(ctx context.Context, request *users.RegenerateRecoveryCodesRequest) (*users.RecoveryCodesResponse, error) {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	recoveryCodes, err := a.service.RegenerateRecoveryCodes(ctx, principal.User.Uuid, request.Code)
+	if err != nil {
+		return nil, twoFactorError(err)
+	}
+	return &users.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
 
-	user, err := a.service.GetByUsername(ctx, request.Username)
+// twoFactorError returns the status of an error of the two-factor authentication settings
+func twoFactorError(err error) error {
+	switch err {
+	case errTOTPEnabled, errTOTPNotEnabled, errTOTPNotEnrolled:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errTOTPLocked:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func (a api) ListSessions(ctx context.Context, request *users.ListSessionsRequest) (*users.ListSessionsResponse, error) {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "username %s not found", request.Username)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	sessions, err := auth.Sessions(principal.User.Uuid)
+	if err != nil {
+		log.Error("failed to list sessions", log.Err(err))
+		return nil, status.Errorf(codes.Internal, "list sessions failed")
 	}
+	res := &users.ListSessionsResponse{Sessions: make([]*users.Session, 0, len(sessions))}
+	for _, s := range sessions {
+		res.Sessions = append(res.Sessions, s.ToProto(principal.SessionID))
+	}
+	return res, nil
+}
 
-	if !auth.CheckPasswordHash(request.Password, user.Password) {
-		return nil, status.Error(codes.Unauthenticated, "username or password is not valid")
+func (a api) RevokeSession(ctx context.Context, request *users.RevokeSessionRequest) (*empty.Empty, error) {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	err = auth.RevokeSession(principal.User.Uuid, request.SessionUuid)
+	if err == auth.ErrSessionNotFound {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		log.Error("failed to revoke session", log.Err(err))
+		return nil, status.Errorf(codes.Internal, "revoke session failed")
 	}
+	return &empty.Empty{}, nil
+}
 
-	tokens, err := auth.CreateToken(user)
+func (a api) RevokeSessions(ctx context.Context, request *users.RevokeSessionsRequest) (*empty.Empty, error) {
+	principal, err := pkgAuth.ExtractPrincipal(ctx)
 	if err != nil {
-		log.Error("error on create user token", log.String("user", user.Username), log.Err(err))
-		return nil, status.Errorf(codes.Internal, "internal server error, create token")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
+	except := ""
+	if request.KeepCurrent {
+		except = principal.SessionID
+	}
+	if _, err := auth.RevokeSessions(principal.User.Uuid, except); err != nil {
+		log.Error("failed to revoke sessions", log.Err(err))
+		return nil, status.Errorf(codes.Internal, "revoke sessions failed")
+	}
+	return &empty.Empty{}, nil
+}
 
-	err = auth.SaveTokens(user, tokens)
+func (a api) RevokeUserSessions(ctx context.Context, request *users.RevokeUserSessionsRequest) (*empty.Empty, error) {
+	user, err := a.service.Get(ctx, request.UserUuid)
 	if err != nil {
-		log.Error("error on set user session", log.String("user", user.Username), log.Err(err))
-		return nil, status.Errorf(codes.Internal, "internal server error, session")
+		return nil, status.Error(codes.NotFound, err.Error())
 	}
-	return tokens, nil
+	n, err := auth.RevokeSessions(user.Uuid, "")
+	if err != nil {
+		log.Error("failed to revoke sessions", log.String("user", user.Uuid), log.Err(err))
+		return nil, status.Errorf(codes.Internal, "revoke sessions failed")
+	}
+	log.Info("sessions of the user revoked", log.String("user", user.Uuid), log.Any("sessions", n))
+	return &empty.Empty{}, nil
 }
 
-func (a api) Register(ctx context.Context, request *users.RegisterRequest) (*users.RegisterResponse, error) {
+func (a api) RequestPasswordReset(ctx context.Context, request *users.RequestPasswordResetRequest) (*empty.Empty, error) {
+	err := a.service.RequestPasswordReset(ctx, request.Email)
+	if err == errTooManyResetRequests {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
 
-	user, err := a.CreateUser(ctx, &users.CreateUserRequest{
+func (a api) ResetPassword(ctx context.Context, request *users.ResetPasswordRequest) (*empty.Empty, error) {
+	if err := a.service.ResetPassword(ctx, request); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) VerifyEmail(ctx context.Context, request *users.VerifyEmailRequest) (*empty.Empty, error) {
+	if err := a.service.VerifyEmail(ctx, request.Token); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) ResendVerificationEmail(ctx context.Context, request *users.ResendVerificationEmailRequest) (*empty.Empty, error) {
+	err := a.service.ResendVerificationEmail(ctx, request.Email)
+	if err == errTooManyVerificationMails {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) Register(ctx context.Context, request *users.RegisterRequest) (*users.RegisterResponse, error) {
+	password, err := auth.HashPassword(request.Password)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "registration failed")
+	}
+	user, err := a.service.Register(ctx, &users.CreateUserRequest{
 		Username: request.Username,
-		Password: request.Password,
+		Password: password,
 		Email:    request.Email,
 		Enable:   true,
 	})
@@ -188,8 +389,31 @@ func New(srv Service) API {
 }
 
 func init() {
-	kv.Memory().SetString("/usersV1.UserService/Login", "open")
-	kv.Memory().SetString("/usersV1.UserService/Register", "open")
-	kv.Memory().SetString("/usersV1.UserService/VerifyToken", "open")
-	kv.Memory().SetString("/usersV1.UserService/RefreshToken", "open")
+	pkgAuth.RegisterMethods(map[string]pkgAuth.MethodPolicy{
+		"/usersV1.UserService/ListUsers":               pkgAuth.RequireGlobal("users", "list"),
+		"/usersV1.UserService/GetUser":                 pkgAuth.RequireGlobal("users", "get"),
+		"/usersV1.UserService/CreateUser":              pkgAuth.RequireGlobal("users", "create"),
+		"/usersV1.UserService/UpdateUser":              pkgAuth.RequireGlobal("users", "update"),
+		"/usersV1.UserService/DeleteUser":              pkgAuth.RequireGlobal("users", "delete"),
+		"/usersV1.UserService/Login":                   pkgAuth.Public,
+		"/usersV1.UserService/Register":                pkgAuth.Public,
+		"/usersV1.UserService/VerifyToken":             pkgAuth.Public,
+		"/usersV1.UserService/RefreshToken":            pkgAuth.Public,
+		"/usersV1.UserService/Logout":                  pkgAuth.Authenticated,
+		"/usersV1.UserService/ListSessions":            pkgAuth.Authenticated,
+		"/usersV1.UserService/RevokeSession":           pkgAuth.Authenticated,
+		"/usersV1.UserService/RevokeSessions":          pkgAuth.Authenticated,
+		"/usersV1.UserService/RevokeUserSessions":      pkgAuth.RequireGlobal("sessions", "revoke"),
+		"/usersV1.UserService/RequestPasswordReset":    pkgAuth.Public,
+		"/usersV1.UserService/ResetPassword":           pkgAuth.Public,
+		"/usersV1.UserService/VerifyEmail":             pkgAuth.Public,
+		"/usersV1.UserService/ResendVerificationEmail": pkgAuth.Public,
+		"/usersV1.UserService/LoginTOTP":               pkgAuth.Public,
+		"/usersV1.UserService/SSOLogin":                pkgAuth.Public,
+		"/usersV1.UserService/SSOCallback":             pkgAuth.Public,
+		"/usersV1.UserService/EnrollTOTP":              pkgAuth.Authenticated,
+		"/usersV1.UserService/ConfirmTOTP":             pkgAuth.Authenticated,
+		"/usersV1.UserService/DisableTOTP":             pkgAuth.Authenticated,
+		"/usersV1.UserService/RegenerateRecoveryCodes": pkgAuth.Authenticated,
+	})
 }
@@ -0,0 +1,112 @@
+package users
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	pkgAuth "github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/kv"
+	"github.com/mirzakhany/pm/pkg/log"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMain(m *testing.M) {
+	log.ObserveForTest()
+	if _, err := kv.InitMock(context.Background()); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func Test_api_Session(t *testing.T) {
+	hash, err := auth.HashPassword("secret")
+	assert.Nil(t, err)
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "test", Password: hash, Enable: true, EmailVerified: true},
+	}}
+	a := api{service: NewService(repo)}
+	ctx := context.Background()
+
+	_, err = a.Login(ctx, &usersProto.LoginRequest{Username: "test", Password: "wrong"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	tokens, err := a.Login(ctx, &usersProto.LoginRequest{Username: "test", Password: "secret"})
+	assert.Nil(t, err)
+	principal, err := auth.Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, repo.items[0].UUID, principal.User.Uuid)
+
+	refreshed, err := a.RefreshToken(ctx, &usersProto.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	assert.Nil(t, err)
+	_, err = a.VerifyToken(ctx, &usersProto.VerifyTokenRequest{AccessToken: refreshed.AccessToken})
+	assert.Nil(t, err)
+
+	_, err = a.Logout(pkgAuth.ContextWithPrincipal(ctx, principal), &usersProto.LogoutRequest{})
+	assert.Nil(t, err)
+	_, err = a.VerifyToken(ctx, &usersProto.VerifyTokenRequest{AccessToken: refreshed.AccessToken})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = a.RefreshToken(ctx, &usersProto.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// a user disabled after login is signed out on refresh
+	tokens, err = a.Login(ctx, &usersProto.LoginRequest{Username: "test", Password: "secret"})
+	assert.Nil(t, err)
+	repo.items[0].Enable = false
+	_, err = a.RefreshToken(ctx, &usersProto.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = a.Login(ctx, &usersProto.LoginRequest{Username: "test", Password: "secret"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_api_Sessions(t *testing.T) {
+	hash, err := auth.HashPassword("secret")
+	assert.Nil(t, err)
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "sessions", Password: hash, Enable: true, EmailVerified: true},
+	}}
+	a := api{service: NewService(repo)}
+	ctx := context.Background()
+
+	first, err := a.Login(ctx, &usersProto.LoginRequest{Username: "sessions", Password: "secret", Device: "laptop"})
+	assert.Nil(t, err)
+	second, err := a.Login(ctx, &usersProto.LoginRequest{Username: "sessions", Password: "secret", Device: "phone"})
+	assert.Nil(t, err)
+	principal, err := auth.Authenticate(first.AccessToken)
+	assert.Nil(t, err)
+	asUser := pkgAuth.ContextWithPrincipal(ctx, principal)
+
+	res, err := a.ListSessions(asUser, &usersProto.ListSessionsRequest{})
+	assert.Nil(t, err)
+	if assert.Len(t, res.Sessions, 2) {
+		assert.Equal(t, "laptop", res.Sessions[0].Device)
+		assert.True(t, res.Sessions[0].Current)
+		assert.Equal(t, "phone", res.Sessions[1].Device)
+	}
+
+	_, err = a.RevokeSession(asUser, &usersProto.RevokeSessionRequest{SessionUuid: uuid.New().String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = a.RevokeSessions(asUser, &usersProto.RevokeSessionsRequest{KeepCurrent: true})
+	assert.Nil(t, err)
+	_, err = auth.Authenticate(second.AccessToken)
+	assert.NotNil(t, err)
+	_, err = auth.Authenticate(first.AccessToken)
+	assert.Nil(t, err)
+
+	// an admin of every workspace ends the sessions of another user, the roles of a workspace do not allow it
+	policy, ok := pkgAuth.MethodPolicyOf("/usersV1.UserService/RevokeUserSessions")
+	assert.True(t, ok)
+	assert.Equal(t, pkgAuth.RequireGlobal("sessions", "revoke"), policy)
+	_, err = a.RevokeUserSessions(ctx, &usersProto.RevokeUserSessionsRequest{UserUuid: uuid.New().String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = a.RevokeUserSessions(ctx, &usersProto.RevokeUserSessionsRequest{UserUuid: repo.items[0].UUID})
+	assert.Nil(t, err)
+	_, err = auth.Authenticate(first.AccessToken)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Client describes where a session was started from
+type Client struct {
+	Device    string `json:"device"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+// firstValue returns the first value of the first metadata key that is set
+func firstValue(md metadata.MD, keys ...string) string {
+	for _, key := range keys {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// ClientFromContext returns the client of the request, preferring the headers the gateway forwards
+// to the ones of its own grpc connection.
+func ClientFromContext(ctx context.Context, device string) Client {
+	c := Client{Device: device}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		c.UserAgent = firstValue(md, "grpcgateway-user-agent", "user-agent")
+		// the first address of x-forwarded-for is the one of the client
+		c.IP = strings.TrimSpace(strings.Split(firstValue(md, "x-forwarded-for"), ",")[0])
+	}
+	if c.IP == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			c.IP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(c.IP); err == nil {
+				c.IP = host
+			}
+		}
+	}
+	if c.Device == "" {
+		c.Device = c.UserAgent
+	}
+	return c
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SignEmailToken returns a token proving its holder received an email sent to the user at the address.
+func SignEmailToken(userUUID, email string, life time.Duration) (string, error) {
+	token, _, err := signClaims(claims{
+		StandardClaims: jwt.StandardClaims{Subject: userUUID},
+		Type:           emailToken,
+		Email:          email,
+	}, life)
+	return token, err
+}
+
+// ParseEmailToken verifies an email token and returns the user and the address it was sent to.
+func ParseEmailToken(token string) (string, string, error) {
+	c, err := parseClaims(emailToken, token)
+	if err != nil {
+		return "", "", err
+	}
+	return c.Subject, c.Email, nil
+}
@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/log"
+)
+
+var (
+	// keysDir is the directory of the signing keys, one PEM file per key named after its kid
+	keysDir = config.RegisterString("auth.keysDir", "")
+	// keysReloadInterval is the number of seconds between two loads of the keys directory
+	keysReloadInterval = config.RegisterInt("auth.keysReloadInterval", 300)
+	// temporaryKey allows signing the tokens with a key generated at startup when there is no keys directory.
+	// The other replicas reject its tokens and a restart invalidates them, it is meant for local development.
+	temporaryKey = config.RegisterBool("auth.temporaryKey", false)
+)
+
+// notBeforeHeader is the PEM header scheduling when a key starts signing tokens
+const notBeforeHeader = "Not-Before"
+
+// minRSABits is the smallest RSA key accepted
+const minRSABits = 2048
+
+var (
+	errNoKey     = errors.New("no signing key")
+	errNoKeysDir = errors.New("auth.keysDir is not set, set it or enable auth.temporaryKey for local development")
+)
+
+// signingKey is a private key tokens are signed with, identified by its kid
+type signingKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Signer crypto.Signer
+	// NotBefore is when the key starts signing, it is published for verification before
+	NotBefore time.Time
+}
+
+// keyring holds the keys of the service. The newest active key signs the tokens,
+// every key verifies them so the tokens of rotated keys stay valid until they expire.
+type keyring struct {
+	keys []*signingKey
+	lock sync.RWMutex
+}
+
+var keys = &keyring{}
+
+// set replaces the keys of the keyring
+func (k *keyring) set(keys []*signingKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].NotBefore.Before(keys[j].NotBefore)
+	})
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.keys = keys
+}
+
+// signing returns the key to sign the tokens with at the time,
+// generating a key for the life of the process if there are none.
+func (k *keyring) signing(now time.Time) (*signingKey, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if len(k.keys) == 0 {
+		key, err := generateKey()
+		if err != nil {
+			return nil, err
+		}
+		k.keys = []*signingKey{key}
+	}
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if !k.keys[i].NotBefore.After(now) {
+			return k.keys[i], nil
+		}
+	}
+	return nil, errNoKey
+}
+
+// get returns the key with the kid
+func (k *keyring) get(id string) (*signingKey, bool) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	for _, key := range k.keys {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// all returns every key of the keyring
+func (k *keyring) all() []*signingKey {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return append([]*signingKey(nil), k.keys...)
+}
+
+// generateKey returns a new Ed25519 key
+func generateKey() (*signingKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &signingKey{ID: uuid.New().String(), Method: SigningMethodEdDSA, Signer: private}, nil
+}
+
+// parseKey parses a PKCS #8 RSA or Ed25519 key, or a PKCS #1 RSA key, in PEM format.
+func parseKey(id string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data", id)
+	}
+	var (
+		private interface{}
+		err     error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+
+	key := &signingKey{ID: id}
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("key %s: RSA keys must have at least %d bits", id, minRSABits)
+		}
+		key.Method, key.Signer = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Signer = SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", id, private)
+	}
+
+	if v, ok := block.Headers[notBeforeHeader]; ok {
+		if key.NotBefore, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("key %s: invalid %s header: %w", id, notBeforeHeader, err)
+		}
+	}
+	return key, nil
+}
+
+// loadKeys parses the .pem files of the directory
+func loadKeys(dir string) ([]*signingKey, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	var res []*signingKey
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parseKey(strings.TrimSuffix(filepath.Base(f), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, key)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no keys in %s", dir)
+	}
+	return res, nil
+}
+
+// InitKeys loads the signing keys of the keys directory and reloads them periodically until the context is done,
+// so new keys can be added ahead of their Not-Before time and old keys removed once their tokens expired.
+// Without a keys directory the service refuses to start, unless the temporary key is enabled.
+func InitKeys(ctx context.Context) error {
+	dir := keysDir.String()
+	if dir == "" {
+		if !temporaryKey.Bool() {
+			return errNoKeysDir
+		}
+		log.Warn("auth.keysDir is not set, tokens are signed with a temporary key: " +
+			"they are rejected by the other replicas and invalidated by a restart")
+		return nil
+	}
+	loaded, err := loadKeys(dir)
+	if err != nil {
+		return err
+	}
+	keys.set(loaded)
+
+	go func() {
+		ticker := time.NewTicker(time.Second * time.Duration(keysReloadInterval.Int()))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				loaded, err := loadKeys(dir)
+				if err != nil {
+					log.Error("reload signing keys failed", log.Err(err))
+					continue
+				}
+				keys.set(loaded)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/mirzakhany/pm/pkg/config"
+	users "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKey(t *testing.T, dir, kid string, private interface{}, headers map[string]string) {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Headers: headers, Bytes: der})
+	if err := ioutil.WriteFile(filepath.Join(dir, kid+".pem"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tokenKid returns the kid of the token without verifying it
+func tokenKid(t *testing.T, token string) string {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, &claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestInitKeys_NoKeysDir(t *testing.T) {
+	assert.Equal(t, errNoKeysDir, InitKeys(context.Background()))
+
+	temporaryKey = config.RegisterBoolMock("auth.temporaryKey", true)
+	defer func() { temporaryKey = config.RegisterBoolMock("auth.temporaryKey", false) }()
+	assert.Nil(t, InitKeys(context.Background()))
+}
+
+func TestKeyRotation(t *testing.T) {
+	defer keys.set(keys.all())
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	dir := t.TempDir()
+	writeKey(t, dir, "current", rsaKey, nil)
+	next := time.Now().Add(time.Hour)
+	writeKey(t, dir, "next", edKey, map[string]string{notBeforeHeader: next.Format(time.RFC3339)})
+
+	loaded, err := loadKeys(dir)
+	assert.Nil(t, err)
+	assert.Len(t, loaded, 2)
+	keys.set(loaded)
+
+	// the scheduled key does not sign yet
+	user := &users.User{Uuid: "u1", Enable: true}
+	before, err := Login(user, Client{})
+	assert.Nil(t, err)
+	assert.Equal(t, "current", tokenKid(t, before.AccessToken))
+	_, err = Authenticate(before.AccessToken)
+	assert.Nil(t, err)
+
+	// once active it signs, the tokens of the previous key stay valid
+	signing, err := keys.signing(next.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, "next", signing.ID)
+	signing.NotBefore = time.Now().Add(-time.Second)
+	keys.set(loaded)
+	after, err := Login(user, Client{})
+	assert.Nil(t, err)
+	assert.Equal(t, "next", tokenKid(t, after.AccessToken))
+	_, err = Authenticate(after.AccessToken)
+	assert.Nil(t, err)
+	_, err = Authenticate(before.AccessToken)
+	assert.Nil(t, err)
+
+	// removing a key revokes its tokens
+	keys.set([]*signingKey{signing})
+	_, err = Authenticate(before.AccessToken)
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = Authenticate(after.AccessToken)
+	assert.Nil(t, err)
+}
+
+func TestJWKS(t *testing.T) {
+	defer keys.set(keys.all())
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	keys.set([]*signingKey{
+		{ID: "rsa", Method: jwt.SigningMethodRS256, Signer: rsaKey},
+		{ID: "ed", Method: SigningMethodEdDSA, Signer: edKey, NotBefore: time.Now().Add(time.Hour)},
+	})
+	tokens, err := Login(&users.User{Uuid: "u1", Enable: true}, Client{})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	JWKSHandler(w, httptest.NewRequest("GET", JWKSPath, nil))
+	assert.Equal(t, 200, w.Code)
+	var set JWKS
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &set))
+	assert.Len(t, set.Keys, 2)
+
+	// another service verifies the tokens with the published keys only
+	published := make(map[string]interface{})
+	for _, k := range set.Keys {
+		switch k.KeyType {
+		case "RSA":
+			n, _ := base64.RawURLEncoding.DecodeString(k.N)
+			e, _ := base64.RawURLEncoding.DecodeString(k.E)
+			published[k.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "OKP":
+			x, _ := base64.RawURLEncoding.DecodeString(k.X)
+			assert.Equal(t, "Ed25519", k.Curve)
+			published[k.KeyID] = ed25519.PublicKey(x)
+		}
+	}
+	assert.Equal(t, edKey.Public(), published["ed"])
+	parsed, err := jwt.Parse(tokens.AccessToken, func(token *jwt.Token) (interface{}, error) {
+		return published[token.Header["kid"].(string)], nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, parsed.Valid)
+}
+
+func TestParseToken_Rejected(t *testing.T) {
+	defer keys.set(keys.all())
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	keys.set([]*signingKey{{ID: "rsa", Method: jwt.SigningMethodRS256, Signer: rsaKey}})
+	c := claims{StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()}, SessionID: "s1", Type: accessToken}
+
+	// HS256 with the public key as the secret
+	der, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	assert.Nil(t, err)
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+	hs.Header["kid"] = "rsa"
+	token, err := hs.SignedString(der)
+	assert.Nil(t, err)
+	_, err = parseToken(accessToken, token)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	// unknown kid
+	rs := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	rs.Header["kid"] = "other"
+	token, err = rs.SignedString(rsaKey)
+	assert.Nil(t, err)
+	_, err = parseToken(accessToken, token)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	rs.Header["kid"] = "rsa"
+	token, err = rs.SignedString(rsaKey)
+	assert.Nil(t, err)
+	_, err = parseToken(accessToken, token)
+	assert.Nil(t, err)
+}
+
+func TestParseKey(t *testing.T) {
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	_, err = parseKey("small", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(small)}))
+	assert.NotNil(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.Nil(t, err)
+	key, err := parseKey("ed", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.Nil(t, err)
+	assert.Equal(t, "EdDSA", key.Method.Alg())
+	assert.True(t, key.NotBefore.IsZero())
+
+	_, err = parseKey("ed", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Headers: map[string]string{notBeforeHeader: "tomorrow"}, Bytes: der}))
+	assert.NotNil(t, err)
+	_, err = parseKey("none", []byte("not a key"))
+	assert.NotNil(t, err)
+}
@@ -2,11 +2,10 @@ package auth
 
 import (
 	"context"
-	"errors"
 
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
-	"github.com/mirzakhany/pm/pkg/kv"
-	"github.com/mirzakhany/pm/pkg/session"
+	pkgAuth "github.com/mirzakhany/pm/pkg/auth"
 	users "github.com/mirzakhany/pm/protobuf/users"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,71 +14,53 @@ import (
 	"google.golang.org/grpc"
 )
 
-type contextKey int
-
-const (
-	resourceKey contextKey = iota
-	userKey
-	tokenKey
-	fullMethodKey
-)
-
-func streamExtractor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	return handler(srv, ss)
-}
-
-func unaryExtractor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-	res, ok := kv.Memory().Get(info.FullMethod)
+// authenticate resolves the bearer token into the principal of the request unless the method is public.
+// Methods without a declared policy are denied.
+func authenticate(ctx context.Context, method string) (context.Context, error) {
+	policy, ok := pkgAuth.MethodPolicyOf(method)
 	if !ok {
-		ctx = context.WithValue(ctx, resourceKey, res)
+		return ctx, status.Errorf(codes.PermissionDenied, "method %s is not allowed", method)
 	}
-	ctx = context.WithValue(ctx, fullMethodKey, info.FullMethod)
-	return handler(ctx, req)
-}
-
-func authHandler(ctx context.Context) (context.Context, error) {
-	r := ctx.Value(resourceKey)
-	if r == nil { // No user requested here
+	if policy.Access == pkgAuth.AccessPublic {
 		return ctx, nil
 	}
 	token, err := grpc_auth.AuthFromMD(ctx, "bearer")
 	if err != nil {
-		return ctx, status.Errorf(codes.InvalidArgument, "invalid token format")
+		return ctx, status.Errorf(codes.Unauthenticated, "invalid token format")
 	}
-	var user *users.User
-	err = session.Get(token, &user)
+	principal, err := Authenticate(token)
 	if err != nil {
-		return ctx, status.Errorf(codes.Unauthenticated, "invalid token")
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
 	}
-	return context.WithValue(context.WithValue(ctx, userKey, user), tokenKey, token), nil
+	return pkgAuth.ContextWithPrincipal(ctx, principal), nil
 }
 
-// ExtractUser try to extract the current user from the context
-func ExtractUser(ctx context.Context) (*users.User, error) {
-	u, ok := ctx.Value(userKey).(*users.User)
-	if !ok {
-		return nil, errors.New("no user in context")
+func unaryAuthenticator(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
 	}
-	return u, nil
+	return handler(ctx, req)
 }
 
-// ExtractToken try to extract token from context
-func ExtractToken(ctx context.Context) (string, error) {
-	tok, ok := ctx.Value(tokenKey).(string)
-	if !ok {
-		return "", errors.New("no token in context")
+func streamAuthenticator(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
 	}
-	return tok, nil
+	wrapped := grpcMiddleware.WrapServerStream(ss)
+	wrapped.WrappedContext = ctx
+	return handler(srv, wrapped)
 }
 
-func init() {
-	grpcgw.RegisterInterceptors(grpcgw.Interceptor{
-		Unary:  unaryExtractor,
-		Stream: streamExtractor,
-	})
+// ExtractUser try to extract the current user from the context
+func ExtractUser(ctx context.Context) (*users.User, error) {
+	return pkgAuth.ExtractUser(ctx)
+}
 
+func init() {
 	grpcgw.RegisterInterceptors(grpcgw.Interceptor{
-		Unary:  grpc_auth.UnaryServerInterceptor(authHandler),
-		Stream: grpc_auth.StreamServerInterceptor(authHandler),
+		Unary:  unaryAuthenticator,
+		Stream: streamAuthenticator,
 	})
 }
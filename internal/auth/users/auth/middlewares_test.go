@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	pkgAuth "github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/kv"
+	"github.com/mirzakhany/pm/pkg/log"
+	users "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// logs records the entries logged by the tests
+var logs *observer.ObservedLogs
+
+func TestMain(m *testing.M) {
+	logs = log.ObserveForTest()
+	if _, err := kv.InitMock(context.Background()); err != nil {
+		panic(err)
+	}
+	pkgAuth.RegisterMethods(map[string]pkgAuth.MethodPolicy{
+		"/test.Service/Public":        pkgAuth.Public,
+		"/test.Service/Authenticated": pkgAuth.Authenticated,
+		"/test.Service/Authorized":    pkgAuth.Require("tests", "get"),
+	})
+	os.Exit(m.Run())
+}
+
+func TestAuthenticator(t *testing.T) {
+	user := &users.User{Uuid: "u1", Username: "test", Enable: true}
+	tokens, err := Login(user, Client{})
+	assert.Nil(t, err)
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "bearer "+token))
+	}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		want     codes.Code
+		wantUser bool
+	}{
+		{"unknown method", withToken(tokens.AccessToken), "/test.Service/Unknown", codes.PermissionDenied, false},
+		{"public", context.Background(), "/test.Service/Public", codes.OK, false},
+		{"no token", context.Background(), "/test.Service/Authenticated", codes.Unauthenticated, false},
+		{"invalid token", withToken(tokens.RefreshToken), "/test.Service/Authenticated", codes.Unauthenticated, false},
+		{"authenticated", withToken(tokens.AccessToken), "/test.Service/Authenticated", codes.OK, true},
+		{"authorized", withToken(tokens.AccessToken), "/test.Service/Authorized", codes.OK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := func(ctx context.Context) {
+				u, err := ExtractUser(ctx)
+				assert.Equal(t, tt.wantUser, err == nil)
+				if tt.wantUser {
+					assert.Equal(t, user.Uuid, u.Uuid)
+				}
+			}
+
+			_, err := unaryAuthenticator(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					check(ctx)
+					return nil, nil
+				})
+			assert.Equal(t, tt.want, status.Code(err))
+
+			// streams follow the same policies
+			ss := &grpcMiddleware.WrappedServerStream{WrappedContext: tt.ctx}
+			err = streamAuthenticator(nil, ss, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(srv interface{}, stream grpc.ServerStream) error {
+					check(stream.Context())
+					return nil
+				})
+			assert.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
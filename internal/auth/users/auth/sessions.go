@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	pkgAuth "github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/log"
+	"github.com/mirzakhany/pm/pkg/session"
+	users "github.com/mirzakhany/pm/protobuf/users"
+)
+
+// Session is the server side state of a login, the tokens of the login carry its ID.
+// The refresh tokens of a session form a family: each one is single use and replaced on refresh.
+// Removing the session revokes all of its tokens.
+type Session struct {
+	ID   string      `json:"id"`
+	User *users.User `json:"user"`
+	// RefreshID is the ID of the only refresh token the session accepts
+	RefreshID string `json:"refresh_id"`
+	// Rotations is the number of times the refresh token was replaced
+	Rotations   int       `json:"rotations"`
+	Client      Client    `json:"client"`
+	CreatedAt   time.Time `json:"created_at"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// LastUsedAt is kept apart from the session, see touchSession
+	LastUsedAt time.Time `json:"-"`
+}
+
+// ToProto returns the session as shown to its user, current being the session of the request
+func (s Session) ToProto(current string) *users.Session {
+	c, _ := ptypes.TimestampProto(s.CreatedAt)
+	u, _ := ptypes.TimestampProto(s.LastUsedAt)
+	return &users.Session{
+		Uuid:       s.ID,
+		Device:     s.Client.Device,
+		Ip:         s.Client.IP,
+		UserAgent:  s.Client.UserAgent,
+		CreatedAt:  c,
+		LastUsedAt: u,
+		Current:    s.ID == current,
+	}
+}
+
+// UserLoader returns the current state of the user with the UUID, pg.ErrNoRows if the user was deleted
+type UserLoader func(ctx context.Context, userUUID string) (*users.User, error)
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+// lastUsedKey holds when the session was last used, apart from the session so authenticated
+// calls never overwrite a refresh happening at the same time
+func lastUsedKey(id string) string {
+	return "session_used:" + id
+}
+
+// userSessionsKey indexes the sessions of the user
+func userSessionsKey(userUUID string) string {
+	return "user_sessions:" + userUUID
+}
+
+// usedRefreshKey marks a refresh token as used, it lives as long as the token could
+func usedRefreshKey(id string) string {
+	return "refresh_used:" + id
+}
+
+func loadSession(id string) (*Session, error) {
+	var s Session
+	if err := session.Get(sessionKey(id), &s); err != nil {
+		return nil, ErrSessionExpired
+	}
+	if err := session.Get(lastUsedKey(id), &s.LastUsedAt); err != nil {
+		s.LastUsedAt = s.RefreshedAt
+	}
+	return &s, nil
+}
+
+// touchSession records the session was used now
+func touchSession(s *Session) error {
+	life := time.Until(s.ExpiresAt)
+	if life <= 0 {
+		return nil
+	}
+	return session.Set(lastUsedKey(s.ID), time.Now(), life)
+}
+
+// issueTokens signs a new pair of tokens for the session and saves the session,
+// which lives as long as its refresh token.
+func issueTokens(s *Session) (*users.LoginResponse, error) {
+	access, _, err := signToken(accessToken, s, time.Minute*time.Duration(accessTokenLife.Int()))
+	if err != nil {
+		return nil, err
+	}
+	life := time.Hour * time.Duration(refreshTokenLife.Int())
+	refresh, refreshID, err := signToken(refreshToken, s, life)
+	if err != nil {
+		return nil, err
+	}
+	s.RefreshID = refreshID
+	s.ExpiresAt = time.Now().Add(life)
+	if err := session.Set(sessionKey(s.ID), s, life); err != nil {
+		return nil, err
+	}
+	if err := touchSession(s); err != nil {
+		return nil, err
+	}
+	if err := session.AddToIndex(userSessionsKey(s.User.Uuid), s.ID, life); err != nil {
+		return nil, err
+	}
+	return &users.LoginResponse{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+// Login starts a new session for the user on the client and returns its tokens.
+func Login(user *users.User, client Client) (*users.LoginResponse, error) {
+	if !user.Enable {
+		return nil, ErrUserDisabled
+	}
+	now := time.Now()
+	return issueTokens(&Session{
+		ID:          uuid.New().String(),
+		User:        sessionUser(user),
+		Client:      client,
+		CreatedAt:   now,
+		RefreshedAt: now,
+	})
+}
+
+// Authenticate verifies the access token and returns the principal of its session.
+func Authenticate(token string) (*pkgAuth.Principal, error) {
+	c, err := parseToken(accessToken, token)
+	if err != nil {
+		return nil, err
+	}
+	s, err := loadSession(c.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := touchSession(s); err != nil {
+		return nil, err
+	}
+	return &pkgAuth.Principal{User: s.User, SessionID: s.ID}, nil
+}
+
+// Refresh replaces the refresh token of a session with a new pair of tokens,
+// reloading the user of the session so deleted and disabled users are signed out.
+// Failing to load the user keeps the session and its refresh token, the client can refresh again.
+// A refresh token presented twice was stolen from the user or by the user from an attacker,
+// so the whole session is revoked.
+func Refresh(ctx context.Context, token string, load UserLoader) (*users.LoginResponse, error) {
+	c, err := parseToken(refreshToken, token)
+	if err != nil {
+		return nil, err
+	}
+	// marking the token used first makes concurrent refreshes with the same token fail
+	first, err := session.SetNX(usedRefreshKey(c.Id), c.SessionID, time.Hour*time.Duration(refreshTokenLife.Int()))
+	if err != nil {
+		return nil, err
+	}
+	s, err := loadSession(c.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !first || s.RefreshID != c.Id {
+		log.Warn("refresh token reuse detected, session revoked",
+			log.String("session", s.ID), log.String("user", s.User.Uuid), log.String("token", c.Id))
+		if err := Logout(s.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrTokenReused
+	}
+
+	user, err := load(ctx, s.User.Uuid)
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		// the token was not replaced, it stays valid
+		if err := session.Delete(usedRefreshKey(c.Id)); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	if err != nil || !user.Enable {
+		if err := Logout(s.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrUserDisabled
+	}
+	s.User = sessionUser(user)
+	s.Rotations++
+	s.RefreshedAt = time.Now()
+	return issueTokens(s)
+}
+
+// Logout ends the session, revoking its tokens.
+func Logout(sessionID string) error {
+	s, err := loadSession(sessionID)
+	if err != nil {
+		// already ended
+		return nil
+	}
+	return endSession(s)
+}
+
+func endSession(s *Session) error {
+	if err := session.Delete(sessionKey(s.ID)); err != nil {
+		return err
+	}
+	if err := session.Delete(lastUsedKey(s.ID)); err != nil {
+		return err
+	}
+	return session.RemoveFromIndex(userSessionsKey(s.User.Uuid), s.ID)
+}
+
+// Sessions returns the active sessions of the user, oldest first.
+func Sessions(userUUID string) ([]*Session, error) {
+	ids, err := session.Index(userSessionsKey(userUUID))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Session, 0, len(ids))
+	for _, id := range ids {
+		s, err := loadSession(id)
+		if err != nil {
+			// expired sessions leave their ID behind
+			if err := session.RemoveFromIndex(userSessionsKey(userUUID), id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+	return res, nil
+}
+
+// RevokeSession ends the session of the user with the ID.
+func RevokeSession(userUUID, sessionID string) error {
+	s, err := loadSession(sessionID)
+	if err != nil || s.User.Uuid != userUUID {
+		return ErrSessionNotFound
+	}
+	return endSession(s)
+}
+
+// RevokeSessions ends every session of the user except the one with the ID, returning the number ended.
+func RevokeSessions(userUUID, except string) (int, error) {
+	sessions, err := Sessions(userUUID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, s := range sessions {
+		if s.ID == except {
+			continue
+		}
+		if err := endSession(s); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// sessionUser returns a copy of the user without its password hash
+func sessionUser(user *users.User) *users.User {
+	u := proto.Clone(user).(*users.User)
+	u.Password = ""
+	return u
+}
@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-pg/pg/v10"
+	pkgAuth "github.com/mirzakhany/pm/pkg/auth"
+	users "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestSessionFlow(t *testing.T) {
+	user := &users.User{Id: 1, Uuid: "u1", Username: "test", Password: "hash", Enable: true}
+	load := func(ctx context.Context, userUUID string) (*users.User, error) {
+		if userUUID != user.Uuid {
+			return nil, errors.New("not found")
+		}
+		return user, nil
+	}
+	// call makes an authenticated call with the token and returns the principal the service sees
+	call := func(token string) (*pkgAuth.Principal, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "bearer "+token))
+		var principal *pkgAuth.Principal
+		_, err := unaryAuthenticator(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Authenticated"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				p, err := pkgAuth.ExtractPrincipal(ctx)
+				principal = p
+				return nil, err
+			})
+		return principal, err
+	}
+
+	// login
+	tokens, err := Login(user, Client{})
+	assert.Nil(t, err)
+
+	// authenticated call
+	principal, err := call(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Uuid, principal.User.Uuid)
+	assert.Equal(t, user.Id, principal.User.Id)
+	assert.Empty(t, principal.User.Password)
+	assert.NotEmpty(t, principal.SessionID)
+
+	// a refresh token is not an access token and the reverse
+	_, err = call(tokens.RefreshToken)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = Refresh(context.Background(), tokens.AccessToken, load)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	// refresh keeps the session and replaces the refresh token
+	refreshed, err := Refresh(context.Background(), tokens.RefreshToken, load)
+	assert.Nil(t, err)
+	p, err := call(refreshed.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, principal.SessionID, p.SessionID)
+
+	// logout revokes every token of the session
+	assert.Nil(t, Logout(p.SessionID))
+	_, err = call(refreshed.AccessToken)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = call(tokens.AccessToken)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = Refresh(context.Background(), refreshed.RefreshToken, load)
+	assert.Equal(t, ErrSessionExpired, err)
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	user := &users.User{Uuid: "u3", Username: "test", Enable: true}
+	load := func(ctx context.Context, userUUID string) (*users.User, error) {
+		return user, nil
+	}
+	tokens, err := Login(user, Client{})
+	assert.Nil(t, err)
+	first, err := Refresh(context.Background(), tokens.RefreshToken, load)
+	assert.Nil(t, err)
+	second, err := Refresh(context.Background(), first.RefreshToken, load)
+	assert.Nil(t, err)
+	principal, err := Authenticate(second.AccessToken)
+	assert.Nil(t, err)
+	s, err := loadSession(principal.SessionID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Rotations)
+
+	// presenting a rotated token again revokes the whole family
+	logs.TakeAll()
+	_, err = Refresh(context.Background(), first.RefreshToken, load)
+	assert.Equal(t, ErrTokenReused, err)
+	entries := logs.FilterMessage("refresh token reuse detected, session revoked").All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, principal.SessionID, entries[0].ContextMap()["session"])
+		assert.Equal(t, user.Uuid, entries[0].ContextMap()["user"])
+	}
+	_, err = Authenticate(second.AccessToken)
+	assert.Equal(t, ErrSessionExpired, err)
+	_, err = Refresh(context.Background(), second.RefreshToken, load)
+	assert.Equal(t, ErrSessionExpired, err)
+
+	// only one of the refreshes with the same token succeeds
+	tokens, err = Login(user, Client{})
+	assert.Nil(t, err)
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := Refresh(context.Background(), tokens.RefreshToken, load)
+			results <- err
+		}()
+	}
+	errs := []error{<-results, <-results}
+	assert.Contains(t, errs, nil)
+	assert.Contains(t, errs, ErrTokenReused)
+}
+
+func TestSessionDisabledUser(t *testing.T) {
+	user := &users.User{Uuid: "u2", Username: "test", Enable: true}
+	tokens, err := Login(user, Client{})
+	assert.Nil(t, err)
+
+	disabled := &users.User{Uuid: "u2", Username: "test"}
+	_, err = Login(disabled, Client{})
+	assert.Equal(t, ErrUserDisabled, err)
+
+	// the session of a disabled user ends on refresh
+	_, err = Refresh(context.Background(), tokens.RefreshToken, func(ctx context.Context, userUUID string) (*users.User, error) {
+		return disabled, nil
+	})
+	assert.Equal(t, ErrUserDisabled, err)
+	_, err = Authenticate(tokens.AccessToken)
+	assert.Equal(t, ErrSessionExpired, err)
+
+	// so does the session of a deleted user
+	tokens, err = Login(user, Client{})
+	assert.Nil(t, err)
+	_, err = Refresh(context.Background(), tokens.RefreshToken, func(ctx context.Context, userUUID string) (*users.User, error) {
+		return nil, pg.ErrNoRows
+	})
+	assert.Equal(t, ErrUserDisabled, err)
+	_, err = Authenticate(tokens.AccessToken)
+	assert.Equal(t, ErrSessionExpired, err)
+}
+
+func TestRefreshLoadFailure(t *testing.T) {
+	user := &users.User{Uuid: "u6", Username: "test", Enable: true}
+	tokens, err := Login(user, Client{})
+	assert.Nil(t, err)
+
+	// the session outlives an outage of the database
+	outage := errors.New("connection refused")
+	_, err = Refresh(context.Background(), tokens.RefreshToken, func(ctx context.Context, userUUID string) (*users.User, error) {
+		return nil, outage
+	})
+	assert.Equal(t, outage, err)
+	_, err = Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+	_, err = Refresh(context.Background(), tokens.RefreshToken, func(ctx context.Context, userUUID string) (*users.User, error) {
+		return user, nil
+	})
+	assert.Nil(t, err)
+}
+
+func TestSessions(t *testing.T) {
+	user := &users.User{Uuid: "u4", Username: "test", Enable: true}
+	laptop, err := Login(user, Client{Device: "laptop", IP: "10.0.0.1", UserAgent: "firefox"})
+	assert.Nil(t, err)
+	phone, err := Login(user, Client{Device: "phone", IP: "10.0.0.2"})
+	assert.Nil(t, err)
+	tablet, err := Login(user, Client{Device: "tablet"})
+	assert.Nil(t, err)
+	_, err = Login(&users.User{Uuid: "u5", Enable: true}, Client{})
+	assert.Nil(t, err)
+
+	current, err := Authenticate(laptop.AccessToken)
+	assert.Nil(t, err)
+	sessions, err := Sessions(user.Uuid)
+	assert.Nil(t, err)
+	if assert.Len(t, sessions, 3) {
+		assert.Equal(t, "laptop", sessions[0].Client.Device)
+		assert.False(t, sessions[0].LastUsedAt.Before(sessions[0].CreatedAt))
+		res := sessions[0].ToProto(current.SessionID)
+		assert.Equal(t, "10.0.0.1", res.Ip)
+		assert.Equal(t, "firefox", res.UserAgent)
+		assert.True(t, res.Current)
+		assert.False(t, sessions[1].ToProto(current.SessionID).Current)
+	}
+
+	// users revoke their own sessions only
+	other, err := Authenticate(phone.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrSessionNotFound, RevokeSession("u5", other.SessionID))
+	assert.Nil(t, RevokeSession(user.Uuid, other.SessionID))
+	_, err = Authenticate(phone.AccessToken)
+	assert.Equal(t, ErrSessionExpired, err)
+	assert.Equal(t, ErrSessionNotFound, RevokeSession(user.Uuid, other.SessionID))
+
+	// revoking every other session keeps the current one
+	n, err := RevokeSessions(user.Uuid, current.SessionID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	_, err = Authenticate(tablet.AccessToken)
+	assert.Equal(t, ErrSessionExpired, err)
+	_, err = Authenticate(laptop.AccessToken)
+	assert.Nil(t, err)
+
+	n, err = RevokeSessions(user.Uuid, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	sessions, err = Sessions(user.Uuid)
+	assert.Nil(t, err)
+	assert.Empty(t, sessions)
+	sessions, err = Sessions("u5")
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestClientFromContext(t *testing.T) {
+	md := metadata.Pairs("grpcgateway-user-agent", "firefox", "user-agent", "grpc-go", "x-forwarded-for", "10.0.0.1, 10.0.0.2")
+	c := ClientFromContext(metadata.NewIncomingContext(context.Background(), md), "")
+	assert.Equal(t, Client{Device: "firefox", IP: "10.0.0.1", UserAgent: "firefox"}, c)
+
+	c = ClientFromContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-agent", "grpc-go")), "cli")
+	assert.Equal(t, Client{Device: "cli", UserAgent: "grpc-go"}, c)
+}
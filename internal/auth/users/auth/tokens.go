@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+// Types of the tokens, a token of one type is never accepted as the other
+const (
+	accessToken  = "access"
+	refreshToken = "refresh"
+	emailToken   = "email"
+)
+
+// claims are the claims of the tokens, the subject being the user UUID and the ID unique to every token
+type claims struct {
+	jwt.StandardClaims
+	SessionID string `json:"sid,omitempty"`
+	Type      string `json:"typ"`
+	// Email is the address an email token was sent to
+	Email string `json:"email,omitempty"`
+}
+
+// tokenParser accepts the algorithms of the signing keys only
+var tokenParser = &jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg(), SigningMethodEdDSA.Alg()}}
+
+// signToken returns a new token of the type for the session, and its ID.
+func signToken(tokenType string, session *Session, life time.Duration) (string, string, error) {
+	return signClaims(claims{
+		StandardClaims: jwt.StandardClaims{Subject: session.User.Uuid},
+		SessionID:      session.ID,
+		Type:           tokenType,
+	}, life)
+}
+
+// signClaims signs the claims with a new token ID and the given life, returning the token and its ID.
+func signClaims(c claims, life time.Duration) (string, string, error) {
+	now := time.Now()
+	key, err := keys.signing(now)
+	if err != nil {
+		return "", "", err
+	}
+	c.Id = uuid.New().String()
+	c.IssuedAt = now.Unix()
+	c.ExpiresAt = now.Add(life).Unix()
+	token := jwt.NewWithClaims(key.Method, c)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.Signer)
+	return signed, c.Id, err
+}
+
+// parseToken verifies the signature, the expiry and the type of a session token and returns its claims.
+func parseToken(tokenType, token string) (*claims, error) {
+	c, err := parseClaims(tokenType, token)
+	if err != nil || c.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+	return c, nil
+}
+
+// parseClaims verifies the signature, the expiry and the type of the token and returns its claims.
+func parseClaims(tokenType, token string) (*claims, error) {
+	c := &claims{}
+	_, err := tokenParser.ParseWithClaims(token, c, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := keys.get(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return key.Signer.Public(), nil
+	})
+	if err != nil || c.Type != tokenType {
+		return nil, ErrInvalidToken
+	}
+	return c, nil
+}
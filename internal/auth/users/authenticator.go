@@ -0,0 +1,87 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/pkg/config"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+)
+
+// authenticators names the authenticators checking password logins, in the order they are tried, separated by spaces
+var authenticators = config.RegisterString("auth.authenticators", PasswordAuthenticatorName)
+
+var (
+	// ErrUnknownUser is returned by authenticators which do not know the username
+	ErrUnknownUser = errors.New("unknown user")
+	// ErrInvalidCredentials is returned by authenticators which know the username but not the password
+	ErrInvalidCredentials = errors.New("username or password is not valid")
+)
+
+// Authenticator checks the credentials of a password login
+type Authenticator interface {
+	// Name names the authenticator in the auth.authenticators config
+	Name() string
+	// Authenticate returns the user of the credentials, ErrUnknownUser or ErrInvalidCredentials
+	Authenticate(ctx context.Context, username, password string) (*usersProto.User, error)
+}
+
+// PasswordAuthenticatorName names the authenticator checking the password hashes of the users
+const PasswordAuthenticatorName = "password"
+
+// passwordAuthenticator checks the password against the bcrypt hash of the user
+type passwordAuthenticator struct {
+	repo Repository
+}
+
+func (a passwordAuthenticator) Name() string {
+	return PasswordAuthenticatorName
+}
+
+func (a passwordAuthenticator) Authenticate(ctx context.Context, username, password string) (*usersProto.User, error) {
+	user, err := a.repo.WhereOne(ctx, "username = ?", username)
+	if err == pg.ErrNoRows {
+		return nil, ErrUnknownUser
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !auth.CheckPasswordHash(password, user.Password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user.ToProto(false /*secure*/), nil
+}
+
+// Authenticate checks the credentials with the configured authenticators in order, the first accepting them wins.
+// An authenticator failing, such as a directory being down, does not stop the others from being tried.
+// It returns ErrUnknownUser if none knows the username.
+func (s service) Authenticate(ctx context.Context, username, password string) (*usersProto.User, error) {
+	res := ErrUnknownUser
+	var failed error
+	for _, name := range strings.Fields(authenticators.String()) {
+		a, ok := s.authenticators[name]
+		if !ok {
+			return nil, fmt.Errorf("authenticator %q is not available", name)
+		}
+		user, err := a.Authenticate(ctx, username, password)
+		switch err {
+		case nil:
+			return user, nil
+		case ErrUnknownUser:
+		case ErrInvalidCredentials:
+			res = err
+		default:
+			if failed == nil {
+				failed = fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	return nil, res
+}
@@ -0,0 +1,119 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/log"
+)
+
+var (
+	errProviderEmailUnverified = errors.New("the identity provider did not verify the email of the account")
+	errLinkUnverified          = errors.New("an account with the email exists but its email is not verified")
+)
+
+// externalAccount is the account of a user at an identity provider
+type externalAccount struct {
+	Issuer  string
+	Subject string
+	Email   string
+	// EmailVerified tells the provider vouches for the email
+	EmailVerified bool
+	// Username is the username the account prefers
+	Username string
+}
+
+// externalUser returns the user of an account at an identity provider. Users are matched by their account,
+// then linked by verified email or created on their first login.
+func (s service) externalUser(ctx context.Context, account externalAccount) (entity.User, error) {
+	identity, err := s.repo.GetIdentity(ctx, account.Issuer, account.Subject)
+	if err == nil {
+		return s.repo.WhereOne(ctx, "id = ?", identity.UserID)
+	}
+	if err != pg.ErrNoRows {
+		return entity.User{}, err
+	}
+
+	// the email decides which user the account is, so the provider has to vouch for it
+	if account.Email == "" || !account.EmailVerified {
+		return entity.User{}, errProviderEmailUnverified
+	}
+	user, err := s.repo.WhereOne(ctx, "email = ?", account.Email)
+	switch {
+	case err == pg.ErrNoRows:
+		if user, err = s.provisionUser(ctx, account); err != nil {
+			return entity.User{}, err
+		}
+		log.Info("user provisioned by an identity provider", log.String("user", user.UUID), log.String("issuer", account.Issuer))
+	case err != nil:
+		return entity.User{}, err
+	case !user.EmailVerified:
+		// whoever registered the email without verifying it must not get the account of its owner
+		return entity.User{}, errLinkUnverified
+	default:
+		log.Info("user linked to an identity provider", log.String("user", user.UUID), log.String("issuer", account.Issuer))
+	}
+
+	err = s.repo.CreateIdentity(ctx, entity.UserIdentity{
+		UserID:    user.ID,
+		Issuer:    account.Issuer,
+		Subject:   account.Subject,
+		CreatedAt: time.Now(),
+	})
+	return user, err
+}
+
+// provisionUser creates the user of an account at the provider, without a password
+func (s service) provisionUser(ctx context.Context, account externalAccount) (entity.User, error) {
+	username, err := s.freeUsername(ctx, account)
+	if err != nil {
+		return entity.User{}, err
+	}
+	now := time.Now()
+	id := uuid.New().String()
+	err = s.repo.Create(ctx, entity.User{
+		UUID:          id,
+		Username:      username,
+		Email:         account.Email,
+		Enable:        true,
+		EmailVerified: true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// freeUsername returns the username the account prefers, or the name of its email,
+// numbered if a user has it already
+func (s service) freeUsername(ctx context.Context, account externalAccount) (string, error) {
+	base := account.Username
+	if base == "" {
+		base = strings.SplitN(account.Email, "@", 2)[0]
+	}
+	if len(base) > 100 {
+		base = base[:100]
+	}
+	for i := 1; i <= 10; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		_, err := s.repo.WhereOne(ctx, "username = ?", name)
+		if err == pg.ErrNoRows {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return base + "-" + uuid.New().String()[:8], nil
+}
@@ -0,0 +1,264 @@
+package users
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/log"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+)
+
+// LDAPAuthenticatorName names the directory authenticator in the auth.authenticators config
+const LDAPAuthenticatorName = "ldap"
+
+var (
+	// ldapURL is the ldap:// or ldaps:// URL of the directory
+	ldapURL = config.RegisterString("auth.ldap.url", "")
+	// ldapStartTLS upgrades ldap:// connections to TLS
+	ldapStartTLS           = config.RegisterBool("auth.ldap.startTLS", false)
+	ldapInsecureSkipVerify = config.RegisterBool("auth.ldap.insecureSkipVerify", false)
+	// ldapTimeout is the number of seconds to wait for the directory
+	ldapTimeout = config.RegisterInt("auth.ldap.timeout", 10)
+	// ldapBindDN and ldapBindPassword are the account searching the directory, the search is anonymous without them
+	ldapBindDN       = config.RegisterString("auth.ldap.bindDN", "")
+	ldapBindPassword = config.RegisterString("auth.ldap.bindPassword", "")
+	ldapBaseDN       = config.RegisterString("auth.ldap.baseDN", "")
+	// ldapUserFilter finds the entry of a user, %s being the username
+	ldapUserFilter        = config.RegisterString("auth.ldap.userFilter", "(uid=%s)")
+	ldapUsernameAttribute = config.RegisterString("auth.ldap.usernameAttribute", "uid")
+	ldapEmailAttribute    = config.RegisterString("auth.ldap.emailAttribute", "mail")
+	// ldapGroupAttribute lists the groups of a user in their entry
+	ldapGroupAttribute = config.RegisterString("auth.ldap.groupAttribute", "memberOf")
+	// ldapGroupFilter finds the groups of a user under ldapGroupBaseDN instead, %s being the DN of the user,
+	// for directories without a group attribute
+	ldapGroupFilter = config.RegisterString("auth.ldap.groupFilter", "")
+	ldapGroupBaseDN = config.RegisterString("auth.ldap.groupBaseDN", "")
+	// ldapGroupRoles maps the DNs of groups to the global roles of their members, as "<group DN> => <role>" separated by ";"
+	ldapGroupRoles = config.RegisterString("auth.ldap.groupRoles", "")
+)
+
+var errLDAPDisabled = errors.New("ldap is not configured")
+
+// RoleSyncer keeps the global roles of users in line with their groups in a directory
+type RoleSyncer interface {
+	// SyncRoles gives the user the roles, and takes back the managed roles which are not among them
+	SyncRoles(userUUID string, roles, managed []string) error
+}
+
+// ldapConn is the part of a directory connection the authenticator uses
+type ldapConn interface {
+	Bind(username, password string) error
+	Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close()
+}
+
+// ldapAuthenticator finds the entry of the user with the search account, then binds as the user to check the password.
+// Users are created on their first login and get the roles of their groups on every login.
+type ldapAuthenticator struct {
+	service service
+	roles   RoleSyncer
+	dial    func() (ldapConn, error)
+}
+
+// NewLDAPAuthenticator creates the authenticator checking passwords against the directory, syncing the roles of the groups with roles.
+func NewLDAPAuthenticator(repo Repository, roles RoleSyncer) Authenticator {
+	return ldapAuthenticator{service: service{repo: repo}, roles: roles, dial: dialLDAP}
+}
+
+func (a ldapAuthenticator) Name() string {
+	return LDAPAuthenticatorName
+}
+
+func (a ldapAuthenticator) Authenticate(ctx context.Context, username, password string) (*usersProto.User, error) {
+	if ldapURL.String() == "" {
+		return nil, errLDAPDisabled
+	}
+	// directories accept a bind without a password as an anonymous one
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	conn, err := a.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := bindSearcher(conn); err != nil {
+		return nil, err
+	}
+	entry, err := findLDAPUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	groups, err := ldapGroups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	name := entry.GetAttributeValue(ldapUsernameAttribute.String())
+	if name == "" {
+		name = username
+	}
+	user, err := a.service.externalUser(ctx, externalAccount{
+		Issuer:  "ldap:" + strings.ToLower(ldapBaseDN.String()),
+		Subject: strings.ToLower(name),
+		Email:   entry.GetAttributeValue(ldapEmailAttribute.String()),
+		// the directory is run by the organization, which vouches for the emails in it
+		EmailVerified: true,
+		Username:      name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if mapping := groupRoles(); a.roles != nil && len(mapping) > 0 {
+		roles, managed := mapGroups(mapping, groups)
+		if err := a.roles.SyncRoles(user.UUID, roles, managed); err != nil {
+			return nil, err
+		}
+		log.Info("ldap roles synced", log.String("user", user.UUID), log.Any("roles", roles))
+	}
+	return user.ToProto(false /*secure*/), nil
+}
+
+func dialLDAP() (ldapConn, error) {
+	u, err := url.Parse(ldapURL.String())
+	if err != nil {
+		return nil, err
+	}
+	timeout := time.Second * time.Duration(ldapTimeout.Int())
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: ldapInsecureSkipVerify.Bool()} //nolint:gosec // opt-in for test directories
+	conn, err := ldap.DialURL(u.String(), ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+	if ldapStartTLS.Bool() && u.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// bindSearcher binds the connection as the search account, if there is one
+func bindSearcher(conn ldapConn) error {
+	if ldapBindDN.String() == "" {
+		return nil
+	}
+	if err := conn.Bind(ldapBindDN.String(), ldapBindPassword.String()); err != nil {
+		return fmt.Errorf("ldap: bind of the search account failed: %w", err)
+	}
+	return nil
+}
+
+func findLDAPUser(conn ldapConn, username string) (*ldap.Entry, error) {
+	res, err := conn.Search(ldap.NewSearchRequest(
+		ldapBaseDN.String(), ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(ldapUserFilter.String(), ldap.EscapeFilter(username)),
+		[]string{ldapUsernameAttribute.String(), ldapEmailAttribute.String(), ldapGroupAttribute.String()},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	switch len(res.Entries) {
+	case 0:
+		return nil, ErrUnknownUser
+	case 1:
+		return res.Entries[0], nil
+	}
+	return nil, fmt.Errorf("ldap: the username %q matches several entries", username)
+}
+
+// ldapGroups returns the DNs of the groups of the user
+func ldapGroups(conn ldapConn, entry *ldap.Entry) ([]string, error) {
+	if ldapGroupFilter.String() == "" {
+		return entry.GetAttributeValues(ldapGroupAttribute.String()), nil
+	}
+	// the connection is bound as the user, who may not see the groups
+	if err := bindSearcher(conn); err != nil {
+		return nil, err
+	}
+	res, err := conn.Search(ldap.NewSearchRequest(
+		ldapGroupBaseDN.String(), ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(ldapGroupFilter.String(), ldap.EscapeFilter(entry.DN)),
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]string, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		groups = append(groups, e.DN)
+	}
+	return groups, nil
+}
+
+// groupRoles returns the roles of the groups of the config, by normalized group DN
+func groupRoles() map[string]string {
+	res := map[string]string{}
+	for _, entry := range strings.Split(ldapGroupRoles.String(), ";") {
+		i := strings.LastIndex(entry, "=>")
+		if i < 0 {
+			continue
+		}
+		group, role := normalizeDN(entry[:i]), strings.TrimSpace(entry[i+2:])
+		if group != "" && role != "" {
+			res[group] = role
+		}
+	}
+	return res
+}
+
+// mapGroups returns the roles of the groups, and all the roles of the mapping
+func mapGroups(mapping map[string]string, groups []string) ([]string, []string) {
+	var roles, managed []string
+	seen := map[string]bool{}
+	for _, role := range mapping {
+		if !seen[role] {
+			seen[role] = true
+			managed = append(managed, role)
+		}
+	}
+	granted := map[string]bool{}
+	for _, g := range groups {
+		if role, ok := mapping[normalizeDN(g)]; ok && !granted[role] {
+			granted[role] = true
+			roles = append(roles, role)
+		}
+	}
+	return roles, managed
+}
+
+// normalizeDN returns the DN in a form equal for all the ways of writing it, ignoring case
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(strings.TrimSpace(dn))
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(dn))
+	}
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		attrs := make([]string, 0, len(rdn.Attributes))
+		for _, a := range rdn.Attributes {
+			attrs = append(attrs, strings.ToLower(a.Type)+"="+strings.ToLower(a.Value))
+		}
+		rdns = append(rdns, strings.Join(attrs, "+"))
+	}
+	return strings.Join(rdns, ",")
+}
@@ -0,0 +1,177 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeEntry struct {
+	password string
+	attrs    map[string][]string
+}
+
+// fakeDirectory answers binds and the single attribute filters the authenticator sends, ignoring case as directories do
+type fakeDirectory struct {
+	entries map[string]fakeEntry
+	down    bool
+}
+
+func (d *fakeDirectory) dial() (ldapConn, error) {
+	if d.down {
+		return nil, errors.New("connection refused")
+	}
+	return fakeConn{d}, nil
+}
+
+type fakeConn struct {
+	dir *fakeDirectory
+}
+
+func (c fakeConn) Bind(dn, password string) error {
+	// like a real directory, an empty password makes an anonymous bind
+	if password == "" {
+		return nil
+	}
+	if e, ok := c.dir.entries[dn]; !ok || e.password != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	return nil
+}
+
+func (c fakeConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	res := &ldap.SearchResult{}
+	for dn, e := range c.dir.entries {
+		if !strings.HasSuffix(dn, req.BaseDN) {
+			continue
+		}
+	match:
+		for attr, values := range e.attrs {
+			for _, v := range values {
+				if strings.EqualFold(fmt.Sprintf("(%s=%s)", attr, ldap.EscapeFilter(v)), req.Filter) {
+					res.Entries = append(res.Entries, ldap.NewEntry(dn, e.attrs))
+					break match
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+func (c fakeConn) Close() {}
+
+type syncedRoles struct {
+	user           string
+	roles, managed []string
+}
+
+type fakeRoleSyncer struct {
+	synced []syncedRoles
+}
+
+func (s *fakeRoleSyncer) SyncRoles(userUUID string, roles, managed []string) error {
+	s.synced = append(s.synced, syncedRoles{userUUID, roles, managed})
+	return nil
+}
+
+func mockLDAP() func() {
+	authenticators = config.RegisterStringMock("auth.authenticators", "ldap password")
+	ldapURL = config.RegisterStringMock("auth.ldap.url", "ldap://directory")
+	ldapBindDN = config.RegisterStringMock("auth.ldap.bindDN", "cn=pm,dc=corp,dc=com")
+	ldapBindPassword = config.RegisterStringMock("auth.ldap.bindPassword", "search")
+	ldapBaseDN = config.RegisterStringMock("auth.ldap.baseDN", "ou=people,dc=corp,dc=com")
+	ldapGroupRoles = config.RegisterStringMock("auth.ldap.groupRoles",
+		"cn=admins,ou=groups,dc=corp,dc=com => role:admin; cn=auditors,ou=groups,dc=corp,dc=com => role:auditor")
+	return func() {
+		authenticators = config.RegisterStringMock("auth.authenticators", PasswordAuthenticatorName)
+		ldapURL = config.RegisterStringMock("auth.ldap.url", "")
+		ldapGroupRoles = config.RegisterStringMock("auth.ldap.groupRoles", "")
+	}
+}
+
+func Test_api_LDAPLogin(t *testing.T) {
+	defer mockLDAP()()
+	ctx := context.Background()
+	hash, err := auth.HashPassword("local")
+	assert.Nil(t, err)
+	repo := &mockRepository{lastID: 10, items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "local", Password: hash, Enable: true, EmailVerified: true},
+	}}
+	dir := &fakeDirectory{entries: map[string]fakeEntry{
+		"cn=pm,dc=corp,dc=com": {password: "search"},
+		"uid=jane,ou=people,dc=corp,dc=com": {password: "directory", attrs: map[string][]string{
+			"uid":      {"Jane"},
+			"mail":     {"jane@corp.com"},
+			"memberOf": {"CN=Admins, OU=Groups, DC=corp, DC=com", "cn=staff,ou=groups,dc=corp,dc=com"},
+		}},
+	}}
+	roles := &fakeRoleSyncer{}
+	a := api{service: NewService(repo, ldapAuthenticator{service: service{repo: repo}, roles: roles, dial: dir.dial})}
+	login := func(username, password string) (*usersProto.LoginResponse, error) {
+		return a.Login(ctx, &usersProto.LoginRequest{Username: username, Password: password})
+	}
+
+	_, err = login("jane", "wrong")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = login("jane", "")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = login("ghost", "directory")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Len(t, repo.items, 1)
+
+	// the first login creates the user and grants the roles of their groups
+	tokens, err := login("jane", "directory")
+	assert.Nil(t, err)
+	principal, err := auth.Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Len(t, repo.items, 2)
+	jane := repo.items[1]
+	assert.Equal(t, jane.UUID, principal.User.Uuid)
+	assert.Equal(t, "Jane", jane.Username)
+	assert.Equal(t, "jane@corp.com", jane.Email)
+	assert.True(t, jane.EmailVerified)
+	assert.Empty(t, jane.Password)
+	if assert.Len(t, roles.synced, 1) {
+		assert.Equal(t, jane.UUID, roles.synced[0].user)
+		assert.Equal(t, []string{"role:admin"}, roles.synced[0].roles)
+		assert.ElementsMatch(t, []string{"role:admin", "role:auditor"}, roles.synced[0].managed)
+	}
+
+	// leaving the group takes the role back on the next login, which finds the same user
+	dir.entries["uid=jane,ou=people,dc=corp,dc=com"].attrs["memberOf"] = nil
+	_, err = login("jane", "directory")
+	assert.Nil(t, err)
+	assert.Len(t, repo.items, 2)
+	if assert.Len(t, roles.synced, 2) {
+		assert.Empty(t, roles.synced[1].roles)
+	}
+
+	// users unknown to the directory fall back to their password
+	_, err = login("local", "local")
+	assert.Nil(t, err)
+
+	// the users of pm can still login when the directory is down
+	dir.down = true
+	_, err = login("local", "local")
+	assert.Nil(t, err)
+	_, err = login("jane", "directory")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func Test_groupRoles(t *testing.T) {
+	ldapGroupRoles = config.RegisterStringMock("auth.ldap.groupRoles", " cn=a,dc=x => role:a ;broken; cn=b,dc=x=>role:b; cn=c,dc=x => ")
+	defer func() { ldapGroupRoles = config.RegisterStringMock("auth.ldap.groupRoles", "") }()
+	assert.Equal(t, map[string]string{"cn=a,dc=x": "role:a", "cn=b,dc=x": "role:b"}, groupRoles())
+}
@@ -0,0 +1,164 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/log"
+	"github.com/mirzakhany/pm/pkg/mail"
+	"github.com/mirzakhany/pm/pkg/session"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+)
+
+var (
+	// passwordResetLife is the number of minutes a password reset token is valid for
+	passwordResetLife = config.RegisterInt("auth.passwordResetLife", 30)
+	// passwordResetURL is the page of the web app resetting the password, the token is added as a query parameter
+	passwordResetURL = config.RegisterString("auth.passwordResetURL", "")
+	// passwordResetInterval is the number of seconds to wait before sending another reset email to an address
+	passwordResetInterval = config.RegisterInt("auth.passwordResetInterval", 60)
+	// passwordResetMaxSends is the number of reset emails sent to an address in a day at most
+	passwordResetMaxSends = config.RegisterInt("auth.passwordResetMaxSends", 5)
+	// passwordResetMaxRequests is the number of resets a client can request in an hour at most
+	passwordResetMaxRequests = config.RegisterInt("auth.passwordResetMaxRequests", 20)
+)
+
+var (
+	errInvalidResetToken    = errors.New("invalid or expired password reset token")
+	errTooManyResetRequests = errors.New("too many password reset requests, try again later")
+)
+
+// ValidateResetPasswordRequest validates the ResetPasswordRequest fields.
+func ValidateResetPasswordRequest(r *usersProto.ResetPasswordRequest) error {
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Token, validation.Required),
+		validation.Field(&r.Password, validation.Required, validation.Length(0, 128)),
+	)
+}
+
+// newToken returns a random token
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// tokenKey returns the kv key of a one-time token, the store only holds its hash
+func tokenKey(prefix, token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return prefix + hex.EncodeToString(sum[:])
+}
+
+// RequestPasswordReset emails a single use password reset token to the user with the email.
+// Nothing tells whether the email belongs to a user, the requests for unknown emails count against the limits too.
+func (s service) RequestPasswordReset(ctx context.Context, email string) error {
+	if err := validation.Validate(email, validation.Required, is.EmailFormat); err != nil {
+		return err
+	}
+	if err := limitPasswordReset(email, auth.ClientFromContext(ctx, "").IP); err != nil {
+		return err
+	}
+	user, err := s.repo.WhereOne(ctx, "lower(email) = lower(?)", email)
+	if err == pg.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Enable {
+		return nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	life := time.Minute * time.Duration(passwordResetLife.Int())
+	if err := session.Set(tokenKey("password_reset:", token), user.UUID, life); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nUse this token to reset your password: %s\n", user.Username, token)
+	if u := passwordResetURL.String(); u != "" {
+		body = fmt.Sprintf("Hi %s,\n\nOpen this link to reset your password: %s?token=%s\n", user.Username, u, token)
+	}
+	body += fmt.Sprintf("\nIt expires in %d minutes. If you did not ask for it, ignore this email.\n", passwordResetLife.Int())
+	return mail.Send(ctx, mail.Message{To: []string{user.Email}, Subject: "Reset your password", Body: body})
+}
+
+// limitPasswordReset counts a reset request for the address from the client, failing if the client
+// requested too many resets this hour, or the last email to the address was sent too recently or too many were sent today
+func limitPasswordReset(email, client string) error {
+	if client != "" {
+		n, err := session.Incr(tokenKey("password_reset_requests:", client), time.Hour)
+		if err != nil {
+			return err
+		}
+		if n > int64(passwordResetMaxRequests.Int()) {
+			return errTooManyResetRequests
+		}
+	}
+	email = strings.ToLower(email)
+	interval := time.Second * time.Duration(passwordResetInterval.Int())
+	if interval > 0 {
+		ok, err := session.SetNX(tokenKey("password_reset_wait:", email), true, interval)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errTooManyResetRequests
+		}
+	}
+	n, err := session.Incr(tokenKey("password_reset_sends:", email), time.Hour*24)
+	if err != nil {
+		return err
+	}
+	if n > int64(passwordResetMaxSends.Int()) {
+		return errTooManyResetRequests
+	}
+	return nil
+}
+
+// ResetPassword sets the password of the user of the reset token and ends all of their sessions.
+func (s service) ResetPassword(ctx context.Context, req *usersProto.ResetPasswordRequest) error {
+	if err := ValidateResetPasswordRequest(req); err != nil {
+		return err
+	}
+	var userUUID string
+	if err := session.Take(tokenKey("password_reset:", req.Token), &userUUID); err != nil {
+		return errInvalidResetToken
+	}
+	user, err := s.repo.Get(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if user.Password, err = auth.HashPassword(req.Password); err != nil {
+		return err
+	}
+	// the token was emailed to the user, so it proves they own the email as well
+	user.EmailVerified = true
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, user); err != nil {
+		return err
+	}
+	n, err := auth.RevokeSessions(user.UUID, "")
+	if err != nil {
+		return err
+	}
+	log.Info("password reset", log.String("user", user.UUID), log.Any("revokedSessions", n))
+	return nil
+}
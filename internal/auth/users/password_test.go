@@ -0,0 +1,76 @@
+package users
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/mail"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+var resetTokenPattern = regexp.MustCompile(`reset your password: (\S+)`)
+
+func Test_service_PasswordReset(t *testing.T) {
+	mails := mail.InitMock()
+	hash, err := auth.HashPassword("old")
+	assert.Nil(t, err)
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "reset", Email: "reset@web.com", Password: hash, Enable: true},
+		{ID: 2, UUID: uuid.New().String(), Username: "disabled", Email: "disabled@web.com", Password: hash},
+	}}
+	s := NewService(repo)
+	ctx := context.Background()
+	user := &usersProto.User{Uuid: repo.items[0].UUID, Enable: true}
+	tokens, err := auth.Login(user, auth.Client{})
+	assert.Nil(t, err)
+
+	// unknown and disabled users get no email, without an error telling so
+	assert.NotNil(t, s.RequestPasswordReset(ctx, "not an email"))
+	assert.Nil(t, s.RequestPasswordReset(ctx, "unknown@web.com"))
+	assert.Nil(t, s.RequestPasswordReset(ctx, "disabled@web.com"))
+	assert.Empty(t, mails.Messages())
+
+	// the email is matched whatever its case, and another email waits
+	assert.Nil(t, s.RequestPasswordReset(ctx, "Reset@Web.com"))
+	assert.Equal(t, errTooManyResetRequests, s.RequestPasswordReset(ctx, "reset@web.com"))
+	assert.Len(t, mails.Messages(), 1)
+	msg, ok := mails.Last("reset@web.com")
+	assert.True(t, ok)
+	match := resetTokenPattern.FindStringSubmatch(msg.Body)
+	if !assert.Len(t, match, 2) {
+		return
+	}
+	token := match[1]
+
+	assert.NotNil(t, s.ResetPassword(ctx, &usersProto.ResetPasswordRequest{Token: token}))
+	assert.Equal(t, errInvalidResetToken, s.ResetPassword(ctx, &usersProto.ResetPasswordRequest{Token: "other", Password: "new"}))
+	assert.Nil(t, s.ResetPassword(ctx, &usersProto.ResetPasswordRequest{Token: token, Password: "new"}))
+	assert.True(t, auth.CheckPasswordHash("new", repo.items[0].Password))
+	assert.False(t, auth.CheckPasswordHash("old", repo.items[0].Password))
+
+	// the token is single use and the sessions of the user ended
+	assert.Equal(t, errInvalidResetToken, s.ResetPassword(ctx, &usersProto.ResetPasswordRequest{Token: token, Password: "again"}))
+	_, err = auth.Authenticate(tokens.AccessToken)
+	assert.Equal(t, auth.ErrSessionExpired, err)
+}
+
+func Test_service_PasswordResetClientLimit(t *testing.T) {
+	mail.InitMock()
+	passwordResetMaxRequests = config.RegisterIntMock("auth.passwordResetMaxRequests", 2)
+	defer func() { passwordResetMaxRequests = config.RegisterIntMock("auth.passwordResetMaxRequests", 20) }()
+	s := NewService(&mockRepository{})
+	client := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "10.0.0.9"))
+
+	assert.Nil(t, s.RequestPasswordReset(client, "first@web.com"))
+	assert.Nil(t, s.RequestPasswordReset(client, "second@web.com"))
+	assert.Equal(t, errTooManyResetRequests, s.RequestPasswordReset(client, "third@web.com"))
+	// other clients are not limited
+	assert.Nil(t, s.RequestPasswordReset(context.Background(), "third@web.com"))
+}
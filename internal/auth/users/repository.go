@@ -25,6 +25,10 @@ type Repository interface {
 	Where(ctx context.Context, condition string, params ...interface{}) ([]entity.User, int, error)
 	// WhereOne returns the one of users with the given condition
 	WhereOne(ctx context.Context, condition string, params ...interface{}) (entity.User, error)
+	// GetIdentity returns the identity of the provider with the given issuer and subject.
+	GetIdentity(ctx context.Context, issuer, subject string) (entity.UserIdentity, error)
+	// CreateIdentity links a user to their account at a provider.
+	CreateIdentity(ctx context.Context, identity entity.UserIdentity) error
 }
 
 // repository persists users in database
@@ -99,3 +103,16 @@ func (r repository) WhereOne(ctx context.Context, condition string, params ...in
 	err := r.db.With(ctx).Model(&user).Where(condition, params...).First()
 	return user, err
 }
+
+// GetIdentity reads the identity of the provider with the given issuer and subject from the database.
+func (r repository) GetIdentity(ctx context.Context, issuer, subject string) (entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+	err := r.db.With(ctx).Model(&identity).Where("issuer = ?", issuer).Where("subject = ?", subject).First()
+	return identity, err
+}
+
+// CreateIdentity saves a new identity record in the database.
+func (r repository) CreateIdentity(ctx context.Context, identity entity.UserIdentity) error {
+	_, err := r.db.With(ctx).Model(&identity).Insert()
+	return err
+}
@@ -3,6 +3,7 @@ package users
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/mirzakhany/pm/internal/entity"
 
@@ -12,7 +13,9 @@ import (
 var errCRUD = errors.New("error crud")
 
 type mockRepository struct {
-	items []entity.User
+	items      []entity.User
+	identities []entity.UserIdentity
+	lastID     uint64
 }
 
 func (m mockRepository) Get(ctx context.Context, id string) (entity.User, error) {
@@ -36,6 +39,8 @@ func (m *mockRepository) Create(ctx context.Context, user entity.User) error {
 	if user.Username == "error" {
 		return errCRUD
 	}
+	m.lastID++
+	user.ID = m.lastID
 	m.items = append(m.items, user)
 	return nil
 }
@@ -68,5 +73,41 @@ func (m mockRepository) Where(ctx context.Context, condition string, params ...i
 	return m.items, len(m.items), nil
 }
 func (m mockRepository) WhereOne(ctx context.Context, condition string, params ...interface{}) (entity.User, error) {
-	return m.items[0], nil
+	for _, item := range m.items {
+		switch condition {
+		case "username = ?":
+			if item.Username == params[0] {
+				return item, nil
+			}
+		case "email = ?":
+			if item.Email == params[0] {
+				return item, nil
+			}
+		case "lower(email) = lower(?)":
+			if strings.EqualFold(item.Email, params[0].(string)) {
+				return item, nil
+			}
+		case "id = ?":
+			if item.ID == params[0] {
+				return item, nil
+			}
+		default:
+			return item, nil
+		}
+	}
+	return entity.User{}, pg.ErrNoRows
+}
+
+func (m mockRepository) GetIdentity(ctx context.Context, issuer, subject string) (entity.UserIdentity, error) {
+	for _, identity := range m.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return entity.UserIdentity{}, pg.ErrNoRows
+}
+
+func (m *mockRepository) CreateIdentity(ctx context.Context, identity entity.UserIdentity) error {
+	m.identities = append(m.identities, identity)
+	return nil
 }
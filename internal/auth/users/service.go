@@ -2,13 +2,16 @@ package users
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
 	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/log"
 	usersProto "github.com/mirzakhany/pm/protobuf/users"
 )
 
@@ -20,8 +23,38 @@ type Service interface {
 	Create(ctx context.Context, input *usersProto.CreateUserRequest) (*usersProto.User, error)
 	Update(ctx context.Context, input *usersProto.UpdateUserRequest) (*usersProto.User, error)
 	Delete(ctx context.Context, uuid string) (*usersProto.User, error)
+	// Authenticate returns the user of the credentials of a password login
+	Authenticate(ctx context.Context, username, password string) (*usersProto.User, error)
 	// GetByUsername returns the users if username found
 	GetByUsername(ctx context.Context, username string) (*usersProto.User, error)
+	// GetByUUID returns the user with the specified UUID including its internal fields
+	GetByUUID(ctx context.Context, uuid string) (*usersProto.User, error)
+	// RequestPasswordReset emails a password reset token to the user with the email
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword sets the password of the user of a reset token and ends their sessions
+	ResetPassword(ctx context.Context, req *usersProto.ResetPasswordRequest) error
+	// Register creates a user who signed up and emails them a link to verify their email
+	Register(ctx context.Context, input *usersProto.CreateUserRequest) (*usersProto.User, error)
+	// VerifyEmail marks the email of the user of a verification token as verified
+	VerifyEmail(ctx context.Context, token string) error
+	// ResendVerificationEmail sends the verification link again to the unverified user with the email
+	ResendVerificationEmail(ctx context.Context, email string) error
+	// EnrollTOTP creates a pending TOTP secret for the user
+	EnrollTOTP(ctx context.Context, userUUID string) (*usersProto.EnrollTOTPResponse, error)
+	// ConfirmTOTP enables two-factor authentication if the code matches the pending secret, returning the recovery codes
+	ConfirmTOTP(ctx context.Context, userUUID, code string) ([]string, error)
+	// DisableTOTP disables two-factor authentication if the code is a valid TOTP or recovery code
+	DisableTOTP(ctx context.Context, userUUID, code string) error
+	// RegenerateRecoveryCodes replaces the recovery codes of the user if the code is valid
+	RegenerateRecoveryCodes(ctx context.Context, userUUID, code string) ([]string, error)
+	// LoginChallenge starts the second step of the login of a user with two-factor authentication
+	LoginChallenge(ctx context.Context, userUUID, device string) (string, error)
+	// LoginTOTP completes a login challenge with a TOTP or recovery code, returning the user and the device of the login
+	LoginTOTP(ctx context.Context, challenge, code string) (*usersProto.User, string, error)
+	// StartSSO returns the URL of the identity provider to send the user to for login
+	StartSSO(ctx context.Context, device string) (string, error)
+	// CompleteSSO returns the user who logged in at the identity provider, and the device of the login
+	CompleteSSO(ctx context.Context, state, code string) (*usersProto.User, string, error)
 }
 
 // ValidateCreateRequest validates the CreateUserRequest fields.
@@ -38,17 +71,21 @@ func ValidateUpdateRequest(u *usersProto.UpdateUserRequest) error {
 	return validation.ValidateStruct(u,
 		validation.Field(&u.Username, validation.Required, validation.Length(0, 128)),
 		validation.Field(&u.Email, validation.Required, is.Email),
-		validation.Field(&u.Enable, validation.Required),
 	)
 }
 
 type service struct {
-	repo Repository
+	repo           Repository
+	authenticators map[string]Authenticator
 }
 
-// NewService creates a new user service.
-func NewService(repo Repository) Service {
-	return service{repo}
+// NewService creates a new user service, checking passwords with the hashes of the users and the given authenticators.
+func NewService(repo Repository, authenticators ...Authenticator) Service {
+	s := service{repo: repo, authenticators: map[string]Authenticator{}}
+	for _, a := range append([]Authenticator{passwordAuthenticator{repo}}, authenticators...) {
+		s.authenticators[a.Name()] = a
+	}
+	return s
 }
 
 // NewServiceForTest creates a new user service for test.
@@ -67,21 +104,26 @@ func (s service) Get(ctx context.Context, UUID string) (*usersProto.User, error)
 	return user.ToProto(true /*secure*/), nil
 }
 
-// Create creates a new user.
+// Create creates a new user, its email is trusted as the user is created by an administrator.
 func (s service) Create(ctx context.Context, req *usersProto.CreateUserRequest) (*usersProto.User, error) {
+	return s.create(ctx, req, true)
+}
+
+func (s service) create(ctx context.Context, req *usersProto.CreateUserRequest, emailVerified bool) (*usersProto.User, error) {
 	if err := ValidateCreateRequest(req); err != nil {
 		return nil, err
 	}
 	now := time.Now()
 	id := uuid.New().String()
 	err := s.repo.Create(ctx, entity.User{
-		UUID:      id,
-		Username:  req.Username,
-		Password:  req.Password,
-		Email:     req.Email,
-		Enable:    req.Enable,
-		CreatedAt: now,
-		UpdatedAt: now,
+		UUID:          id,
+		Username:      req.Username,
+		Password:      req.Password,
+		Email:         req.Email,
+		Enable:        req.Enable,
+		EmailVerified: emailVerified,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	})
 	if err != nil {
 		return nil, err
@@ -90,6 +132,7 @@ func (s service) Create(ctx context.Context, req *usersProto.CreateUserRequest)
 }
 
 // Update updates the user with the specified UUID.
+// A new email is not verified, a link to verify it is sent to it.
 func (s service) Update(ctx context.Context, req *usersProto.UpdateUserRequest) (*usersProto.User, error) {
 	if err := ValidateUpdateRequest(req); err != nil {
 		return nil, err
@@ -101,25 +144,52 @@ func (s service) Update(ctx context.Context, req *usersProto.UpdateUserRequest)
 	}
 	now := time.Now()
 
+	emailChanged := !strings.EqualFold(user.Email, req.Email)
+	disabled := user.Enable && !req.Enable
 	user.Username = req.Username
 	user.Email = req.Email
 	user.Enable = req.Enable
 	user.UpdatedAt = now
+	if emailChanged {
+		user.EmailVerified = false
+	}
 
 	userModel := entity.User{
-		ID:        user.ID,
-		UUID:      user.UUID,
-		Username:  req.Username,
-		Password:  user.Password,
-		Email:     req.Email,
-		Enable:    req.Enable,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: now,
+		ID:            user.ID,
+		UUID:          user.UUID,
+		Username:      req.Username,
+		Password:      user.Password,
+		Email:         req.Email,
+		Enable:        req.Enable,
+		EmailVerified: user.EmailVerified,
+		TOTPSecret:    user.TOTPSecret,
+		TOTPEnabled:   user.TOTPEnabled,
+		RecoveryCodes: user.RecoveryCodes,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     now,
 	}
 
 	if err := s.repo.Update(ctx, userModel); err != nil {
 		return nil, err
 	}
+	if disabled {
+		// a disabled user is signed out now rather than when their access tokens expire
+		n, err := auth.RevokeSessions(user.UUID, "")
+		if err != nil {
+			return nil, err
+		}
+		log.Info("user disabled", log.String("user", user.UUID), log.Any("revokedSessions", n))
+	}
+	if emailChanged {
+		// like at registration, failing to send the email does not fail the update
+		err = limitVerification(user.Email)
+		if err == nil {
+			err = s.sendVerification(ctx, user)
+		}
+		if err != nil {
+			log.Error("failed to send the verification email", log.String("user", user.UUID), log.Err(err))
+		}
+	}
 	return user.ToProto(true /*secure*/), nil
 }
 
@@ -162,3 +232,12 @@ func (s service) GetByUsername(ctx context.Context, username string) (*usersProt
 	}
 	return user.ToProto(false /*secure*/), nil
 }
+
+// GetByUUID returns the user with the specified UUID including its internal fields
+func (s service) GetByUUID(ctx context.Context, UUID string) (*usersProto.User, error) {
+	user, err := s.repo.Get(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+	return user.ToProto(false /*secure*/), nil
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/mail"
 	users "github.com/mirzakhany/pm/protobuf/users"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +38,7 @@ func TestUpdateUserRequest_Validate(t *testing.T) {
 	}{
 		{"success", users.UpdateUserRequest{Username: "test", Email: "test@test.com", Enable: true}, false},
 		{"required", users.UpdateUserRequest{Username: "", Email: "test@test.com", Enable: true}, true},
+		{"disabled", users.UpdateUserRequest{Username: "test", Email: "test@test.com"}, false},
 		{"too long", users.UpdateUserRequest{Email: "test@test.com", Enable: true, Username: "1234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890"}, true},
 	}
 	for _, tt := range tests {
@@ -81,6 +86,16 @@ func Test_service_CRUD(t *testing.T) {
 	user, err = s.Update(ctx, &users.UpdateUserRequest{Username: "test-updated", Email: "test@test.com", Enable: true, Uuid: id})
 	assert.Nil(t, err)
 	assert.Equal(t, "test-updated", user.Username)
+
+	// a new email must be verified again
+	mails := mail.InitMock()
+	user, err = s.Update(ctx, &users.UpdateUserRequest{Username: "test-updated", Email: "new@test.com", Enable: true, Uuid: id})
+	assert.Nil(t, err)
+	assert.False(t, user.EmailVerified)
+	_, ok := mails.Last("new@test.com")
+	assert.True(t, ok)
+	_, err = s.Update(ctx, &users.UpdateUserRequest{Username: "test-updated", Email: "test@test.com", Enable: true, Uuid: id})
+	assert.Nil(t, err)
 	_, err = s.Update(ctx, &users.UpdateUserRequest{Username: "test-updated", Email: "test@test.com", Enable: true, Uuid: "none"})
 	assert.NotNil(t, err)
 
@@ -117,3 +132,27 @@ func Test_service_CRUD(t *testing.T) {
 	count, _ = s.Count(ctx)
 	assert.Equal(t, int64(1), count)
 }
+
+func Test_service_UpdateDisable(t *testing.T) {
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "disable", Email: "disable@example.com", Enable: true},
+	}}
+	s := NewService(repo)
+	ctx := context.Background()
+	user := &users.User{Uuid: repo.items[0].UUID, Enable: true}
+	tokens, err := auth.Login(user, auth.Client{})
+	assert.Nil(t, err)
+
+	// updating an enabled user keeps their sessions
+	_, err = s.Update(ctx, &users.UpdateUserRequest{Uuid: user.Uuid, Username: "disable", Email: "disable@example.com", Enable: true})
+	assert.Nil(t, err)
+	_, err = auth.Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+
+	// disabling the user ends them
+	res, err := s.Update(ctx, &users.UpdateUserRequest{Uuid: user.Uuid, Username: "disable", Email: "disable@example.com"})
+	assert.Nil(t, err)
+	assert.False(t, res.Enable)
+	_, err = auth.Authenticate(tokens.AccessToken)
+	assert.Equal(t, auth.ErrSessionExpired, err)
+}
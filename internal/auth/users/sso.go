@@ -0,0 +1,125 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/oidc"
+	"github.com/mirzakhany/pm/pkg/session"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+)
+
+var (
+	// oidcIssuer is the URL of the OpenID Connect provider, single sign-on is disabled without it
+	oidcIssuer       = config.RegisterString("auth.oidc.issuer", "")
+	oidcClientID     = config.RegisterString("auth.oidc.clientID", "")
+	oidcClientSecret = config.RegisterString("auth.oidc.clientSecret", "")
+	// oidcRedirectURL is the callback registered at the provider, it passes the code and the state to SSOCallback
+	oidcRedirectURL = config.RegisterString("auth.oidc.redirectURL", "")
+	// oidcScopes are requested in addition to openid, separated by spaces
+	oidcScopes = config.RegisterString("auth.oidc.scopes", "email profile")
+	// oidcTrustEmail treats the emails of the provider as verified, for providers which do not say
+	oidcTrustEmail = config.RegisterBool("auth.oidc.trustEmail", false)
+	// passwordLogin lets users login with a password, turn it off to login with single sign-on only
+	passwordLogin = config.RegisterBool("auth.passwordLogin", true)
+)
+
+// ssoStateLife is the time a user has to login at the provider
+const ssoStateLife = 10 * time.Minute
+
+var (
+	errSSODisabled     = errors.New("single sign-on is not configured")
+	errInvalidSSOState = errors.New("invalid or expired single sign-on state")
+)
+
+// ssoState is kept between sending the user to the provider and their return
+type ssoState struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Device   string `json:"device"`
+}
+
+// ssoProvider caches the discovered provider until the config changes
+var ssoProvider struct {
+	sync.Mutex
+	provider *oidc.Provider
+}
+
+func provider(ctx context.Context) (*oidc.Provider, error) {
+	c := oidc.Config{
+		Issuer:       oidcIssuer.String(),
+		ClientID:     oidcClientID.String(),
+		ClientSecret: oidcClientSecret.String(),
+		RedirectURL:  oidcRedirectURL.String(),
+		Scopes:       strings.Fields(oidcScopes.String()),
+	}
+	if c.Issuer == "" {
+		return nil, errSSODisabled
+	}
+	ssoProvider.Lock()
+	defer ssoProvider.Unlock()
+	if p := ssoProvider.provider; p != nil && reflect.DeepEqual(p.Config(), c) {
+		return p, nil
+	}
+	p, err := oidc.Discover(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	ssoProvider.provider = p
+	return p, nil
+}
+
+// StartSSO returns the URL of the provider to send the user to for login.
+func (s service) StartSSO(ctx context.Context, device string) (string, error) {
+	p, err := provider(ctx)
+	if err != nil {
+		return "", err
+	}
+	var state, nonce, verifier string
+	for _, v := range []*string{&state, &nonce, &verifier} {
+		if *v, err = oidc.NewVerifier(); err != nil {
+			return "", err
+		}
+	}
+	st := ssoState{Nonce: nonce, Verifier: verifier, Device: device}
+	if err := session.Set(tokenKey("sso_state:", state), st, ssoStateLife); err != nil {
+		return "", err
+	}
+	return p.AuthCodeURL(state, nonce, verifier), nil
+}
+
+// CompleteSSO exchanges the code the provider returned with the state for the user, returning the user and the device of the login.
+func (s service) CompleteSSO(ctx context.Context, state, code string) (*usersProto.User, string, error) {
+	p, err := provider(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	var st ssoState
+	if err := session.Take(tokenKey("sso_state:", state), &st); err != nil {
+		return nil, "", errInvalidSSOState
+	}
+	idToken, err := p.Exchange(ctx, code, st.Verifier)
+	if err != nil {
+		return nil, "", err
+	}
+	claims, err := p.Verify(ctx, idToken, st.Nonce)
+	if err != nil {
+		return nil, "", err
+	}
+	user, err := s.externalUser(ctx, externalAccount{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified) || oidcTrustEmail.Bool(),
+		Username:      claims.PreferredUsername,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return user.ToProto(false /*secure*/), st.Device, nil
+}
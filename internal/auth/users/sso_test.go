@@ -0,0 +1,137 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/oidc"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func mockSSO(t *testing.T) (*oidc.MockServer, func()) {
+	m, err := oidc.NewMockServer("pm", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oidcIssuer = config.RegisterStringMock("auth.oidc.issuer", m.URL)
+	oidcClientID = config.RegisterStringMock("auth.oidc.clientID", m.ClientID)
+	oidcClientSecret = config.RegisterStringMock("auth.oidc.clientSecret", m.ClientSecret)
+	oidcRedirectURL = config.RegisterStringMock("auth.oidc.redirectURL", "http://localhost/sso/callback")
+	return m, func() {
+		m.Close()
+		oidcIssuer = config.RegisterStringMock("auth.oidc.issuer", "")
+		ssoProvider.provider = nil
+	}
+}
+
+// ssoLogin logs the user of the mock provider in, as the browser and the web app would
+func ssoLogin(t *testing.T, a api, m *oidc.MockServer) (*usersProto.LoginResponse, error) {
+	ctx := context.Background()
+	start, err := a.SSOLogin(ctx, &usersProto.SSOLoginRequest{Device: "sso"})
+	if !assert.Nil(t, err) {
+		return nil, err
+	}
+	code, state, err := m.Authorize(start.AuthorizationUrl)
+	if !assert.Nil(t, err) {
+		return nil, err
+	}
+	return a.SSOCallback(ctx, &usersProto.SSOCallbackRequest{State: state, Code: code})
+}
+
+func Test_api_SSO(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockRepository{lastID: 10, items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "jane", Email: "jane@corp.com", Enable: true, EmailVerified: true},
+		{ID: 2, UUID: uuid.New().String(), Username: "squatter", Email: "john@corp.com", Enable: true},
+	}}
+	a := api{service: NewService(repo)}
+
+	_, err := a.SSOLogin(ctx, &usersProto.SSOLoginRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	m, done := mockSSO(t)
+	defer done()
+
+	// the first login creates the user, with a free username
+	m.SetUser(oidc.MockUser{Subject: "1", Email: "new@corp.com", EmailVerified: true, PreferredUsername: "jane"})
+	tokens, err := ssoLogin(t, a, m)
+	assert.Nil(t, err)
+	principal, err := auth.Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Len(t, repo.items, 3)
+	created := repo.items[2]
+	assert.Equal(t, created.UUID, principal.User.Uuid)
+	assert.Equal(t, "jane2", created.Username)
+	assert.Equal(t, "new@corp.com", created.Email)
+	assert.True(t, created.EmailVerified)
+	assert.Empty(t, created.Password)
+	sessions, err := auth.Sessions(created.UUID)
+	assert.Nil(t, err)
+	assert.Equal(t, "sso", sessions[0].Client.Device)
+
+	// the account is matched by its subject afterwards, whatever its email becomes
+	m.SetUser(oidc.MockUser{Subject: "1", Email: "renamed@corp.com"})
+	tokens, err = ssoLogin(t, a, m)
+	assert.Nil(t, err)
+	principal, err = auth.Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, created.UUID, principal.User.Uuid)
+	assert.Len(t, repo.items, 3)
+
+	// an existing user is linked by their verified email
+	m.SetUser(oidc.MockUser{Subject: "2", Email: "jane@corp.com", EmailVerified: true})
+	tokens, err = ssoLogin(t, a, m)
+	assert.Nil(t, err)
+	principal, err = auth.Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, repo.items[0].UUID, principal.User.Uuid)
+	assert.Len(t, repo.identities, 2)
+
+	// unless the email is not verified on either side
+	m.SetUser(oidc.MockUser{Subject: "3", Email: "john@corp.com", EmailVerified: true})
+	_, err = ssoLogin(t, a, m)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	m.SetUser(oidc.MockUser{Subject: "4", Email: "other@corp.com"})
+	_, err = ssoLogin(t, a, m)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	oidcTrustEmail = config.RegisterBoolMock("auth.oidc.trustEmail", true)
+	_, err = ssoLogin(t, a, m)
+	oidcTrustEmail = config.RegisterBoolMock("auth.oidc.trustEmail", false)
+	assert.Nil(t, err)
+	assert.Len(t, repo.items, 4)
+
+	// a state is single use and bound to its code
+	start, err := a.SSOLogin(ctx, &usersProto.SSOLoginRequest{})
+	assert.Nil(t, err)
+	code, state, err := m.Authorize(start.AuthorizationUrl)
+	assert.Nil(t, err)
+	_, err = a.SSOCallback(ctx, &usersProto.SSOCallbackRequest{State: "other", Code: code})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = a.SSOCallback(ctx, &usersProto.SSOCallbackRequest{State: state, Code: "other"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = a.SSOCallback(ctx, &usersProto.SSOCallbackRequest{State: state, Code: code})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = a.SSOCallback(ctx, &usersProto.SSOCallbackRequest{Error: "access_denied"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_api_PasswordLoginDisabled(t *testing.T) {
+	hash, err := auth.HashPassword("secret")
+	assert.Nil(t, err)
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "sso-only", Password: hash, Enable: true, EmailVerified: true},
+	}}
+	a := api{service: NewService(repo)}
+
+	passwordLogin = config.RegisterBoolMock("auth.passwordLogin", false)
+	defer func() { passwordLogin = config.RegisterBoolMock("auth.passwordLogin", true) }()
+	_, err = a.Login(context.Background(), &usersProto.LoginRequest{Username: "sso-only", Password: "secret"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,291 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/log"
+	"github.com/mirzakhany/pm/pkg/session"
+	"github.com/mirzakhany/pm/pkg/totp"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+)
+
+var (
+	// totpIssuer names the service in the authenticator apps
+	totpIssuer = config.RegisterString("auth.totpIssuer", "pm")
+	// loginChallengeLife is the number of minutes a user has to give their code after their password
+	loginChallengeLife = config.RegisterInt("auth.loginChallengeLife", 5)
+	// loginChallengeAttempts is the number of codes tried for a login challenge before it is dropped
+	loginChallengeAttempts = config.RegisterInt("auth.loginChallengeAttempts", 5)
+	// totpMaxFailures is the number of wrong codes of a user, whatever the challenge, locking their two-factor checks
+	totpMaxFailures = config.RegisterInt("auth.totpMaxFailures", 10)
+	// totpLockout is the number of minutes the wrong codes are counted for, and the checks stay locked after the last one
+	totpLockout = config.RegisterInt("auth.totpLockout", 15)
+)
+
+// recoveryCodes is the number of recovery codes of a user
+const recoveryCodes = 10
+
+var (
+	errInvalidCode      = errors.New("invalid two-factor code")
+	errInvalidChallenge = errors.New("invalid or expired login challenge")
+	errTOTPEnabled      = errors.New("two-factor authentication is already enabled")
+	errTOTPNotEnabled   = errors.New("two-factor authentication is not enabled")
+	errTOTPNotEnrolled  = errors.New("no TOTP secret enrolled")
+	errTOTPLocked       = errors.New("too many invalid two-factor codes, try again later")
+)
+
+// loginChallenge is the state of a login waiting for the code of the user
+type loginChallenge struct {
+	User   string `json:"user"`
+	Device string `json:"device"`
+}
+
+// EnrollTOTP creates a pending TOTP secret for the user, replacing any previous pending one.
+func (s service) EnrollTOTP(ctx context.Context, userUUID string) (*usersProto.EnrollTOTPResponse, error) {
+	user, err := s.repo.Get(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, errTOTPEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return &usersProto.EnrollTOTPResponse{
+		Secret: secret,
+		Uri:    totp.URI(totpIssuer.String(), user.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication if the code matches the pending secret,
+// proving the authenticator app of the user has it, and returns the recovery codes.
+func (s service) ConfirmTOTP(ctx context.Context, userUUID, code string) ([]string, error) {
+	user, err := s.repo.Get(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, errTOTPEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, errTOTPNotEnrolled
+	}
+	if err := checkTOTP(user, code); err != nil {
+		return nil, err
+	}
+	codes, err := newRecoveryCodes(&user)
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPEnabled = true
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	log.Info("two-factor authentication enabled", log.String("user", user.UUID))
+	return codes, nil
+}
+
+// DisableTOTP disables two-factor authentication if the code is a valid TOTP or recovery code.
+func (s service) DisableTOTP(ctx context.Context, userUUID, code string) error {
+	user, err := s.repo.Get(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return errTOTPNotEnabled
+	}
+	if err := s.checkCode(ctx, &user, code); err != nil {
+		return err
+	}
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.RecoveryCodes = nil
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, user); err != nil {
+		return err
+	}
+	log.Info("two-factor authentication disabled", log.String("user", user.UUID))
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user if the code is a valid TOTP or recovery code.
+func (s service) RegenerateRecoveryCodes(ctx context.Context, userUUID, code string) ([]string, error) {
+	user, err := s.repo.Get(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, errTOTPNotEnabled
+	}
+	if err := s.checkCode(ctx, &user, code); err != nil {
+		return nil, err
+	}
+	codes, err := newRecoveryCodes(&user)
+	if err != nil {
+		return nil, err
+	}
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// LoginChallenge starts the second step of the login of the user, whose password was checked,
+// returning the token to give back with the code.
+func (s service) LoginChallenge(ctx context.Context, userUUID, device string) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	life := time.Minute * time.Duration(loginChallengeLife.Int())
+	c := loginChallenge{User: userUUID, Device: device}
+	if err := session.Set(tokenKey("login_challenge:", token), c, life); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// LoginTOTP completes a login challenge with a TOTP or recovery code, returning the user and the device of the login.
+// A challenge is dropped once used or after too many wrong codes, the user then starts over with their password.
+func (s service) LoginTOTP(ctx context.Context, challenge, code string) (*usersProto.User, string, error) {
+	key := tokenKey("login_challenge:", challenge)
+	var c loginChallenge
+	if err := session.Get(key, &c); err != nil {
+		return nil, "", errInvalidChallenge
+	}
+	life := time.Minute * time.Duration(loginChallengeLife.Int())
+	attempts, err := session.Incr(tokenKey("login_challenge_attempts:", challenge), life)
+	if err != nil {
+		return nil, "", err
+	}
+	if attempts > int64(loginChallengeAttempts.Int()) {
+		if err := session.Delete(key); err != nil {
+			return nil, "", err
+		}
+		return nil, "", errInvalidChallenge
+	}
+
+	user, err := s.repo.Get(ctx, c.User)
+	if err != nil || !user.Enable {
+		return nil, "", errInvalidChallenge
+	}
+	if err := s.checkCode(ctx, &user, code); err != nil {
+		return nil, "", err
+	}
+	// only one of the requests completing the challenge at once gets the login
+	if err := session.Take(key, &c); err != nil {
+		return nil, "", errInvalidChallenge
+	}
+	return user.ToProto(false /*secure*/), c.Device, nil
+}
+
+// checkCode accepts a TOTP code of the user or one of their recovery codes, which is used up.
+// The wrong codes of the user are counted across their login challenges, so starting new challenges
+// does not give more guesses: too many lock the checks of the user until the lockout ends.
+func (s service) checkCode(ctx context.Context, user *entity.User, code string) error {
+	key := "totp_failures:" + user.UUID
+	var failures int64
+	if err := session.Get(key, &failures); err == nil && failures >= int64(totpMaxFailures.Int()) {
+		return errTOTPLocked
+	}
+	err := s.matchCode(ctx, user, code)
+	if err == nil {
+		return session.Delete(key)
+	}
+	if err != errInvalidCode {
+		return err
+	}
+	lockout := time.Minute * time.Duration(totpLockout.Int())
+	failures, err = session.Incr(key, lockout)
+	if err != nil {
+		return err
+	}
+	if failures >= int64(totpMaxFailures.Int()) {
+		// the lockout starts over from the last wrong code
+		if err := session.Set(key, failures, lockout); err != nil {
+			return err
+		}
+		log.Warn("two-factor checks locked after too many invalid codes", log.String("user", user.UUID))
+	}
+	return errInvalidCode
+}
+
+// matchCode accepts a TOTP code of the user or one of their recovery codes, which is used up
+func (s service) matchCode(ctx context.Context, user *entity.User, code string) error {
+	if err := checkTOTP(*user, code); err != errInvalidCode {
+		return err
+	}
+	hash := hashRecoveryCode(code)
+	for i, c := range user.RecoveryCodes {
+		if c != hash {
+			continue
+		}
+		user.RecoveryCodes = append(user.RecoveryCodes[:i:i], user.RecoveryCodes[i+1:]...)
+		user.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, *user); err != nil {
+			return err
+		}
+		log.Info("recovery code used", log.String("user", user.UUID), log.Any("left", len(user.RecoveryCodes)))
+		return nil
+	}
+	return errInvalidCode
+}
+
+// checkTOTP accepts a TOTP code of the user once, so a code seen by someone else cannot be replayed
+func checkTOTP(user entity.User, code string) error {
+	counter, ok := totp.Validate(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return errInvalidCode
+	}
+	// a code is accepted for a period before and after its own
+	first, err := session.SetNX(fmt.Sprintf("totp_used:%s:%d", user.UUID, counter), true, 3*totp.Period)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return errInvalidCode
+	}
+	return nil
+}
+
+// newRecoveryCodes replaces the recovery codes of the user, returning the new codes
+func newRecoveryCodes(user *entity.User) ([]string, error) {
+	codes := make([]string, recoveryCodes)
+	user.RecoveryCodes = make([]string, recoveryCodes)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		c := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes[i] = c[:4] + "-" + c[4:]
+		user.RecoveryCodes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode returns the stored form of a recovery code, ignoring its case and separators
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,154 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	pkgAuth "github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/totp"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_api_TwoFactor(t *testing.T) {
+	hash, err := auth.HashPassword("secret")
+	assert.Nil(t, err)
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "2fa", Password: hash, Enable: true, EmailVerified: true},
+	}}
+	a := api{service: NewService(repo)}
+	ctx := pkgAuth.ContextWithPrincipal(context.Background(), &pkgAuth.Principal{User: &usersProto.User{Uuid: repo.items[0].UUID}})
+	login := &usersProto.LoginRequest{Username: "2fa", Password: "secret", Device: "laptop"}
+	// codes of the period the test starts in and the next one are accepted until it ends
+	start := totp.Counter(time.Now())
+	code := func(periods int64) string {
+		c, err := totp.Code(repo.items[0].TOTPSecret, start+periods)
+		assert.Nil(t, err)
+		return c
+	}
+
+	_, err = a.ConfirmTOTP(ctx, &usersProto.ConfirmTOTPRequest{Code: "123456"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	enrolled, err := a.EnrollTOTP(ctx, &usersProto.EnrollTOTPRequest{})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(enrolled.Uri, "otpauth://totp/pm:2fa?"))
+	assert.Equal(t, enrolled.Secret, repo.items[0].TOTPSecret)
+
+	// the login stays one step until the enrolment is confirmed
+	tokens, err := a.Login(ctx, login)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+
+	recovery, err := a.ConfirmTOTP(ctx, &usersProto.ConfirmTOTPRequest{Code: code(0)})
+	assert.Nil(t, err)
+	assert.Len(t, recovery.RecoveryCodes, recoveryCodes)
+	assert.True(t, repo.items[0].TOTPEnabled)
+	_, err = a.EnrollTOTP(ctx, &usersProto.EnrollTOTPRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	challenge, err := a.Login(ctx, login)
+	assert.Nil(t, err)
+	assert.Empty(t, challenge.AccessToken)
+	assert.NotEmpty(t, challenge.ChallengeToken)
+
+	_, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	// the code used to confirm the enrolment cannot be replayed
+	_, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: code(0)})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	tokens, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: code(1)})
+	assert.Nil(t, err)
+	principal, err := auth.Authenticate(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, repo.items[0].UUID, principal.User.Uuid)
+	sessions, err := auth.Sessions(repo.items[0].UUID)
+	assert.Nil(t, err)
+	assert.Equal(t, "laptop", sessions[len(sessions)-1].Client.Device)
+
+	// a challenge is single use
+	_, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: recovery.RecoveryCodes[2]})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// a recovery code replaces a TOTP code once
+	challenge, err = a.Login(ctx, login)
+	assert.Nil(t, err)
+	_, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: strings.ToUpper(recovery.RecoveryCodes[0])})
+	assert.Nil(t, err)
+	assert.Len(t, repo.items[0].RecoveryCodes, recoveryCodes-1)
+	challenge, err = a.Login(ctx, login)
+	assert.Nil(t, err)
+	_, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: recovery.RecoveryCodes[0]})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// too many wrong codes drop the challenge
+	for i := 1; i < loginChallengeAttempts.Int(); i++ {
+		_, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	_, err = a.LoginTOTP(ctx, &usersProto.LoginTOTPRequest{ChallengeToken: challenge.ChallengeToken, Code: recovery.RecoveryCodes[2]})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	regenerated, err := a.RegenerateRecoveryCodes(ctx, &usersProto.RegenerateRecoveryCodesRequest{Code: recovery.RecoveryCodes[1]})
+	assert.Nil(t, err)
+	assert.Len(t, repo.items[0].RecoveryCodes, recoveryCodes)
+	assert.NotContains(t, regenerated.RecoveryCodes, recovery.RecoveryCodes[2])
+	assert.NotContains(t, repo.items[0].RecoveryCodes, hashRecoveryCode(recovery.RecoveryCodes[2]))
+
+	_, err = a.DisableTOTP(ctx, &usersProto.DisableTOTPRequest{Code: "000000"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = a.DisableTOTP(ctx, &usersProto.DisableTOTPRequest{Code: regenerated.RecoveryCodes[0]})
+	assert.Nil(t, err)
+	assert.False(t, repo.items[0].TOTPEnabled)
+	assert.Empty(t, repo.items[0].TOTPSecret)
+	tokens, err = a.Login(ctx, login)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func Test_service_TOTPLockout(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	assert.Nil(t, err)
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "locked", Enable: true, TOTPEnabled: true, TOTPSecret: secret,
+			RecoveryCodes: []string{hashRecoveryCode("abcd-efgh")}},
+	}}
+	s := NewService(repo)
+	ctx := context.Background()
+	userUUID := repo.items[0].UUID
+
+	// every challenge stays under its own attempts, the wrong codes add up across them
+	failures := 0
+	for failures < totpMaxFailures.Int() {
+		challenge, err := s.LoginChallenge(ctx, userUUID, "")
+		assert.Nil(t, err)
+		for i := 1; i < loginChallengeAttempts.Int() && failures < totpMaxFailures.Int(); i++ {
+			// wrong recovery codes count as well
+			wrong := "000000"
+			if failures%2 == 1 {
+				wrong = "zzzz-zzzz"
+			}
+			_, _, err = s.LoginTOTP(ctx, challenge, wrong)
+			assert.Equal(t, errInvalidCode, err)
+			failures++
+		}
+	}
+
+	// the valid codes are refused until the lockout ends
+	c, err := totp.Code(secret, totp.Counter(time.Now()))
+	assert.Nil(t, err)
+	challenge, err := s.LoginChallenge(ctx, userUUID, "")
+	assert.Nil(t, err)
+	_, _, err = s.LoginTOTP(ctx, challenge, c)
+	assert.Equal(t, errTOTPLocked, err)
+	_, _, err = s.LoginTOTP(ctx, challenge, "abcd-efgh")
+	assert.Equal(t, errTOTPLocked, err)
+	assert.Equal(t, errTOTPLocked, s.DisableTOTP(ctx, userUUID, c))
+	assert.Len(t, repo.items[0].RecoveryCodes, 1)
+}
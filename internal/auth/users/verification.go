@@ -0,0 +1,151 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/log"
+	"github.com/mirzakhany/pm/pkg/mail"
+	"github.com/mirzakhany/pm/pkg/session"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+)
+
+var (
+	// emailVerificationLife is the number of hours a verification link is valid for
+	emailVerificationLife = config.RegisterInt("auth.emailVerificationLife", 48)
+	// emailVerificationURL is the page of the web app verifying the email, the token is added as a query parameter
+	emailVerificationURL = config.RegisterString("auth.emailVerificationURL", "")
+	// verificationResendInterval is the number of seconds to wait before sending another link to an email
+	verificationResendInterval = config.RegisterInt("auth.verificationResendInterval", 60)
+	// verificationMaxSends is the number of links sent to an email in a day at most
+	verificationMaxSends = config.RegisterInt("auth.verificationMaxSends", 5)
+	// unverifiedLogin lets users login before verifying their email
+	unverifiedLogin = config.RegisterBool("auth.unverifiedLogin", false)
+)
+
+var (
+	errInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	errTooManyVerificationMails = errors.New("too many verification emails, try again later")
+	errEmailNotVerified         = errors.New("email is not verified")
+)
+
+// Register creates a user who signed up and emails them a link to verify their email.
+// Failing to send the email does not fail the registration, the user can ask for it again.
+func (s service) Register(ctx context.Context, req *usersProto.CreateUserRequest) (*usersProto.User, error) {
+	res, err := s.create(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.Get(ctx, res.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	// the first email counts against the limits too, so resending right after registering waits
+	err = limitVerification(user.Email)
+	if err == nil {
+		err = s.sendVerification(ctx, user)
+	}
+	if err != nil {
+		log.Error("failed to send the verification email", log.String("user", user.UUID), log.Err(err))
+	}
+	return res, nil
+}
+
+// VerifyEmail marks the email of the user of a verification token as verified.
+// The token is bound to the email it was sent to, changing the email invalidates it.
+func (s service) VerifyEmail(ctx context.Context, token string) error {
+	userUUID, email, err := auth.ParseEmailToken(token)
+	if err != nil {
+		return errInvalidVerificationToken
+	}
+	user, err := s.repo.Get(ctx, userUUID)
+	if err == pg.ErrNoRows || (err == nil && user.Email != email) {
+		return errInvalidVerificationToken
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	user.EmailVerified = true
+	user.UpdatedAt = time.Now()
+	return s.repo.Update(ctx, user)
+}
+
+// ResendVerificationEmail sends the verification link again to the unverified user with the email.
+// Nothing tells whether the email belongs to a user, the sends are limited per email whether it does or not.
+func (s service) ResendVerificationEmail(ctx context.Context, email string) error {
+	if err := validation.Validate(email, validation.Required, is.EmailFormat); err != nil {
+		return err
+	}
+	if err := limitVerification(email); err != nil {
+		return err
+	}
+	user, err := s.repo.WhereOne(ctx, "email = ?", email)
+	if err == pg.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified || !user.Enable {
+		return nil
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// limitVerification counts a verification email to the address,
+// failing if the last one was sent too recently or too many were sent today
+func limitVerification(email string) error {
+	email = strings.ToLower(email)
+	interval := time.Second * time.Duration(verificationResendInterval.Int())
+	if interval > 0 {
+		ok, err := session.SetNX(tokenKey("verification_wait:", email), true, interval)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errTooManyVerificationMails
+		}
+	}
+	n, err := session.Incr(tokenKey("verification_sends:", email), time.Hour*24)
+	if err != nil {
+		return err
+	}
+	if n > int64(verificationMaxSends.Int()) {
+		return errTooManyVerificationMails
+	}
+	return nil
+}
+
+func (s service) sendVerification(ctx context.Context, user entity.User) error {
+	life := time.Hour * time.Duration(emailVerificationLife.Int())
+	token, err := auth.SignEmailToken(user.UUID, user.Email, life)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Hi %s,\n\nUse this token to verify your email: %s\n", user.Username, token)
+	if u := emailVerificationURL.String(); u != "" {
+		body = fmt.Sprintf("Hi %s,\n\nOpen this link to verify your email: %s?token=%s\n", user.Username, u, token)
+	}
+	body += fmt.Sprintf("\nIt expires in %d hours. If you did not sign up, ignore this email.\n", emailVerificationLife.Int())
+	return mail.Send(ctx, mail.Message{To: []string{user.Email}, Subject: "Verify your email", Body: body})
+}
+
+// canLogin tells whether the user may login, unverified users may only if the config allows it
+func canLogin(user *usersProto.User) error {
+	if !user.EmailVerified && !unverifiedLogin.Bool() {
+		return errEmailNotVerified
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package users
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/auth/users/auth"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/mail"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var verificationTokenPattern = regexp.MustCompile(`verify your email: (\S+)`)
+
+func Test_api_VerifyEmail(t *testing.T) {
+	mails := mail.InitMock()
+	hash, err := auth.HashPassword("secret")
+	assert.Nil(t, err)
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "unverified", Email: "unverified@web.com", Password: hash, Enable: true},
+	}}
+	a := api{service: NewService(repo)}
+	ctx := context.Background()
+	login := &usersProto.LoginRequest{Username: "unverified", Password: "secret"}
+
+	_, err = a.Login(ctx, login)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = a.ResendVerificationEmail(ctx, &usersProto.ResendVerificationEmailRequest{Email: "unverified@web.com"})
+	assert.Nil(t, err)
+	msg, ok := mails.Last("unverified@web.com")
+	assert.True(t, ok)
+	match := verificationTokenPattern.FindStringSubmatch(msg.Body)
+	if !assert.Len(t, match, 2) {
+		return
+	}
+
+	// a token of another user or email is rejected
+	other, err := auth.SignEmailToken(repo.items[0].UUID, "other@web.com", time.Hour)
+	assert.Nil(t, err)
+	_, err = a.VerifyEmail(ctx, &usersProto.VerifyEmailRequest{Token: other})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = a.VerifyEmail(ctx, &usersProto.VerifyEmailRequest{Token: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = a.VerifyEmail(ctx, &usersProto.VerifyEmailRequest{Token: match[1]})
+	assert.Nil(t, err)
+	assert.True(t, repo.items[0].EmailVerified)
+	_, err = a.Login(ctx, login)
+	assert.Nil(t, err)
+
+	// the config may let unverified users login
+	repo.items[0].EmailVerified = false
+	unverifiedLogin = config.RegisterBoolMock("auth.unverifiedLogin", true)
+	defer func() { unverifiedLogin = config.RegisterBoolMock("auth.unverifiedLogin", false) }()
+	_, err = a.Login(ctx, login)
+	assert.Nil(t, err)
+}
+
+func Test_service_ResendVerificationEmail(t *testing.T) {
+	mails := mail.InitMock()
+	repo := &mockRepository{items: []entity.User{
+		{ID: 1, UUID: uuid.New().String(), Username: "resend", Email: "resend@web.com", Enable: true},
+		{ID: 2, UUID: uuid.New().String(), Username: "verified", Email: "verified@web.com", Enable: true, EmailVerified: true},
+	}}
+	s := NewService(repo)
+	ctx := context.Background()
+
+	// verified and unknown emails get nothing, without an error telling so
+	assert.Nil(t, s.ResendVerificationEmail(ctx, "verified@web.com"))
+	assert.Nil(t, s.ResendVerificationEmail(ctx, "unknown@web.com"))
+	assert.Empty(t, mails.Messages())
+
+	assert.Nil(t, s.ResendVerificationEmail(ctx, "resend@web.com"))
+	assert.Len(t, mails.Messages(), 1)
+	assert.Equal(t, errTooManyVerificationMails, s.ResendVerificationEmail(ctx, "Resend@web.com"))
+	assert.Len(t, mails.Messages(), 1)
+
+	// without waiting between emails, the daily limit applies
+	verificationResendInterval = config.RegisterIntMock("auth.verificationResendInterval", 0)
+	verificationMaxSends = config.RegisterIntMock("auth.verificationMaxSends", 2)
+	defer func() {
+		verificationResendInterval = config.RegisterIntMock("auth.verificationResendInterval", 60)
+		verificationMaxSends = config.RegisterIntMock("auth.verificationMaxSends", 5)
+	}()
+	assert.Nil(t, s.ResendVerificationEmail(ctx, "resend@web.com"))
+	assert.Equal(t, errTooManyVerificationMails, s.ResendVerificationEmail(ctx, "resend@web.com"))
+	assert.Len(t, mails.Messages(), 2)
+}
@@ -2,10 +2,12 @@ package workspaces
 
 import (
 	"context"
+	"errors"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/grpcgw"
 	"github.com/mirzakhany/pm/protobuf/workspaces"
 	"google.golang.org/grpc"
@@ -41,7 +43,11 @@ func (a api) ListWorkspaces(ctx context.Context, request *workspaces.ListWorkspa
 }
 
 func (a api) GetWorkspace(ctx context.Context, request *workspaces.GetWorkspaceRequest) (*workspaces.Workspace, error) {
-	res, err := a.service.Get(ctx, request.Uuid)
+	workspaceUUID, err := currentWorkspace(ctx, request.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.service.Get(ctx, workspaceUUID)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -57,6 +63,11 @@ func (a api) CreateWorkspace(ctx context.Context, request *workspaces.CreateWork
 }
 
 func (a api) UpdateWorkspace(ctx context.Context, request *workspaces.UpdateWorkspaceRequest) (*workspaces.Workspace, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	request.Uuid = workspaceUUID
 	res, err := a.service.Update(ctx, request)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
@@ -65,15 +76,200 @@ func (a api) UpdateWorkspace(ctx context.Context, request *workspaces.UpdateWork
 }
 
 func (a api) DeleteWorkspace(ctx context.Context, request *workspaces.DeleteWorkspaceRequest) (*empty.Empty, error) {
-	_, err := a.service.Delete(ctx, request.Uuid)
+	workspaceUUID, err := currentWorkspace(ctx, request.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	_, err = a.service.Delete(ctx, workspaceUUID)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	return nil, err
 }
 
+func (a api) RestoreWorkspace(ctx context.Context, request *workspaces.RestoreWorkspaceRequest) (*workspaces.Workspace, error) {
+	res, err := a.service.Restore(ctx, request.Uuid)
+	if err != nil {
+		switch err {
+		case errPurgeStarted:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errNotRequester:
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) GetWorkspaceDeletion(ctx context.Context, request *workspaces.GetWorkspaceDeletionRequest) (*workspaces.WorkspaceDeletion, error) {
+	res, err := a.service.GetDeletion(ctx, request.WorkspaceUuid)
+	if err != nil {
+		if err == errNotRequester {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) InviteWorkspaceMember(ctx context.Context, request *workspaces.InviteWorkspaceMemberRequest) (*workspaces.InviteWorkspaceMemberResponse, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	request.WorkspaceUuid = workspaceUUID
+	res, err := a.service.Invite(ctx, request)
+	if err != nil {
+		switch err {
+		case errNoInvitationSecret:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case ErrRoleNotGrantable:
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) RevokeWorkspaceInvitation(ctx context.Context, request *workspaces.RevokeWorkspaceInvitationRequest) (*empty.Empty, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.service.RevokeInvitation(ctx, workspaceUUID, request.Uuid); err != nil {
+		if err == errInvitationClosed {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) AcceptWorkspaceInvitation(ctx context.Context, request *workspaces.AcceptWorkspaceInvitationRequest) (*workspaces.WorkspaceMember, error) {
+	res, err := a.service.AcceptInvitation(ctx, request.Token)
+	if err != nil {
+		switch err {
+		case errAlreadyMember:
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		case errInvalidInvitation:
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errNoInvitationSecret, errUnverifiedEmail:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, ErrQuotaExceeded) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) ListWorkspaceMembers(ctx context.Context, request *workspaces.ListWorkspaceMembersRequest) (*workspaces.ListWorkspaceMembersResponse, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := grpcgw.GetOffsetAndLimit(request.Offset, request.Limit)
+	res, err := a.service.QueryMembers(ctx, workspaceUUID, offset, limit)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) RemoveWorkspaceMember(ctx context.Context, request *workspaces.RemoveWorkspaceMemberRequest) (*empty.Empty, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	err = a.service.RemoveMember(ctx, workspaceUUID, request.UserUuid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) GetWorkspaceSettings(ctx context.Context, request *workspaces.GetWorkspaceSettingsRequest) (*workspaces.WorkspaceSettings, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.service.GetSettings(ctx, workspaceUUID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) UpdateWorkspaceSettings(ctx context.Context, request *workspaces.UpdateWorkspaceSettingsRequest) (*workspaces.WorkspaceSettings, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	request.WorkspaceUuid = workspaceUUID
+	res, err := a.service.UpdateSettings(ctx, request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) GetWorkspaceUsage(ctx context.Context, request *workspaces.GetWorkspaceUsageRequest) (*workspaces.WorkspaceUsage, error) {
+	workspaceUUID, err := currentWorkspace(ctx, request.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.service.GetUsage(ctx, workspaceUUID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) UpdateWorkspaceQuotas(ctx context.Context, request *workspaces.UpdateWorkspaceQuotasRequest) (*workspaces.WorkspaceQuotas, error) {
+	res, err := a.service.UpdateQuotas(ctx, request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+// currentWorkspace returns the UUID of the workspace of the request, the requests only act on the workspace they are authorized in
+// so a different workspace UUID in the request is denied.
+func currentWorkspace(ctx context.Context, workspaceUUID string) (string, error) {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return "", status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if workspaceUUID != "" && workspaceUUID != workspace.Uuid {
+		return "", status.Error(codes.PermissionDenied, errOtherWorkspace.Error())
+	}
+	return workspace.Uuid, nil
+}
+
 func New(srv Service) API {
 	s := api{service: srv}
 	grpcgw.RegisterController(s)
 	return s
 }
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/workspacesV1.WorkspaceService/ListWorkspaces":            auth.RequireGlobal("workspaces", "list"),
+		"/workspacesV1.WorkspaceService/GetWorkspace":              auth.Require("workspaces", "get"),
+		"/workspacesV1.WorkspaceService/CreateWorkspace":           auth.Authenticated,
+		"/workspacesV1.WorkspaceService/UpdateWorkspace":           auth.Require("workspaces", "update"),
+		"/workspacesV1.WorkspaceService/DeleteWorkspace":           auth.Require("workspaces", "delete"),
+		"/workspacesV1.WorkspaceService/RestoreWorkspace":          auth.Authenticated,
+		"/workspacesV1.WorkspaceService/GetWorkspaceDeletion":      auth.Authenticated,
+		"/workspacesV1.WorkspaceService/InviteWorkspaceMember":     auth.Require("members", "invite"),
+		"/workspacesV1.WorkspaceService/AcceptWorkspaceInvitation": auth.Authenticated,
+		"/workspacesV1.WorkspaceService/RevokeWorkspaceInvitation": auth.Require("members", "invite"),
+		"/workspacesV1.WorkspaceService/ListWorkspaceMembers":      auth.Require("members", "list"),
+		"/workspacesV1.WorkspaceService/RemoveWorkspaceMember":     auth.Require("members", "delete"),
+		"/workspacesV1.WorkspaceService/GetWorkspaceSettings":      auth.Require("settings", "get"),
+		"/workspacesV1.WorkspaceService/UpdateWorkspaceSettings":   auth.Require("settings", "update"),
+		"/workspacesV1.WorkspaceService/GetWorkspaceUsage":         auth.Require("quotas", "get"),
+		"/workspacesV1.WorkspaceService/UpdateWorkspaceQuotas":     auth.RequireGlobal("quotas", "update"),
+	})
+}
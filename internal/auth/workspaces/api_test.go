@@ -0,0 +1,91 @@
+package workspaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/mail"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_api_CurrentWorkspace(t *testing.T) {
+	repo := &mockRepository{
+		users: []entity.User{
+			{ID: 1, UUID: "u1", Username: "owner", Email: "owner@example.com"},
+			{ID: 2, UUID: "u2", Username: "other", Email: "other@example.com"},
+		},
+	}
+	defer MockInvitationsForTest()()
+	mails := mail.InitMock()
+	a := api{service: NewService(repo)}
+	ownerCtx := auth.ContextWithUser(context.Background(), repo.users[0].ToProto(false))
+	acme, err := a.service.Create(ownerCtx, &workspaces.CreateWorkspaceRequest{Title: "acme", Domain: "acme"})
+	assert.Nil(t, err)
+	other, err := a.service.Create(auth.ContextWithUser(context.Background(), repo.users[1].ToProto(false)),
+		&workspaces.CreateWorkspaceRequest{Title: "other", Domain: "other"})
+	assert.Nil(t, err)
+
+	// the requests need a workspace
+	_, err = a.ListWorkspaceMembers(ownerCtx, &workspaces.ListWorkspaceMembersRequest{WorkspaceUuid: acme.Uuid})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// and act on it only, not on the workspace of their body
+	ctx := auth.ContextWithWorkspace(ownerCtx, acme)
+	_, err = a.ListWorkspaceMembers(ctx, &workspaces.ListWorkspaceMembersRequest{WorkspaceUuid: other.Uuid})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.RemoveWorkspaceMember(ctx, &workspaces.RemoveWorkspaceMemberRequest{WorkspaceUuid: other.Uuid, UserUuid: "u2"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.InviteWorkspaceMember(ctx, &workspaces.InviteWorkspaceMemberRequest{WorkspaceUuid: other.Uuid, Email: "guest@example.com"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.GetWorkspaceSettings(ctx, &workspaces.GetWorkspaceSettingsRequest{WorkspaceUuid: other.Uuid})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.UpdateWorkspaceSettings(ctx, &workspaces.UpdateWorkspaceSettingsRequest{
+		WorkspaceUuid: other.Uuid, EstimateScale: "points", TimeZone: "UTC", WeekStart: "monday", IssueKeyPrefix: "OTH",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.GetWorkspace(ctx, &workspaces.GetWorkspaceRequest{Uuid: other.Uuid})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.UpdateWorkspace(ctx, &workspaces.UpdateWorkspaceRequest{Uuid: other.Uuid, Title: "mine"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.DeleteWorkspace(ctx, &workspaces.DeleteWorkspaceRequest{Uuid: other.Uuid})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.GetWorkspaceUsage(ctx, &workspaces.GetWorkspaceUsageRequest{WorkspaceUuid: other.Uuid})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	updated, err := a.UpdateWorkspace(ctx, &workspaces.UpdateWorkspaceRequest{Title: "acme inc"})
+	assert.Nil(t, err)
+	assert.Equal(t, acme.Uuid, updated.Uuid)
+	assert.Equal(t, "acme inc", updated.Title)
+
+	usage, err := a.GetWorkspaceUsage(ctx, &workspaces.GetWorkspaceUsageRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, acme.Uuid, usage.WorkspaceUuid)
+
+	members, err := a.ListWorkspaceMembers(ctx, &workspaces.ListWorkspaceMembersRequest{})
+	assert.Nil(t, err)
+	if assert.Len(t, members.Members, 1) {
+		assert.Equal(t, "u1", members.Members[0].User.Uuid)
+	}
+	invitation, err := a.InviteWorkspaceMember(ctx, &workspaces.InviteWorkspaceMemberRequest{Email: "guest@example.com"})
+	assert.Nil(t, err)
+	inv, err := parseInvitation(invitationToken(t, mails, "guest@example.com"))
+	assert.Nil(t, err)
+	assert.Equal(t, acme.Uuid, inv.WorkspaceUUID)
+	assert.Equal(t, invitation.Uuid, inv.Id)
+	_, err = a.RevokeWorkspaceInvitation(ctx, &workspaces.RevokeWorkspaceInvitationRequest{WorkspaceUuid: other.Uuid, Uuid: invitation.Uuid})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = a.RevokeWorkspaceInvitation(ctx, &workspaces.RevokeWorkspaceInvitationRequest{Uuid: invitation.Uuid})
+	assert.Nil(t, err)
+	_, err = a.RevokeWorkspaceInvitation(ctx, &workspaces.RevokeWorkspaceInvitationRequest{Uuid: invitation.Uuid})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	settings, err := a.UpdateWorkspaceSettings(ctx, &workspaces.UpdateWorkspaceSettingsRequest{
+		EstimateScale: "points", TimeZone: "UTC", WeekStart: "monday", IssueKeyPrefix: "ACME",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "ACME", settings.IssueKeyPrefix)
+}
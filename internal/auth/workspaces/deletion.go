@@ -0,0 +1,186 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/log"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+var (
+	// deletionGracePeriod is the number of hours a deleted workspace can be restored in
+	deletionGracePeriod = config.RegisterInt("workspaces.deletionGracePeriod", 168)
+	// purgeInterval is the number of seconds between two runs of the purge job
+	purgeInterval = config.RegisterInt("workspaces.purgeInterval", 60)
+	// purgeBatchSize is the number of rows removed by a single delete query
+	purgeBatchSize = config.RegisterInt("workspaces.purgeBatchSize", 500)
+)
+
+var (
+	errPurgeStarted = errors.New("workspace purge already started, it cannot be restored")
+	errNotRequester = errors.New("only the user who deleted the workspace can see or undo its deletion")
+)
+
+// Delete soft deletes the workspace and schedules its purge after the grace period.
+func (s service) Delete(ctx context.Context, UUID string) (*workspacesProto.Workspace, error) {
+	workspace, err := s.repo.Get(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.Delete(ctx, UUID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deletion := entity.WorkspaceDeletion{
+		WorkspaceID:   workspace.ID,
+		WorkspaceUUID: workspace.UUID,
+		State:         entity.DeletionStateScheduled,
+		PurgeAfter:    now.Add(time.Hour * time.Duration(deletionGracePeriod.Int())),
+		TablesTotal:   int64(len(purgeTables)),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if user, err := auth.ExtractUser(ctx); err == nil {
+		deletion.RequestedByID = user.Id
+	}
+	if err := s.repo.SaveDeletion(ctx, deletion); err != nil {
+		return nil, err
+	}
+	return workspace.ToProto(), nil
+}
+
+// Restore undoes the deletion of a workspace whose purge has not started yet, for the user who deleted it.
+func (s service) Restore(ctx context.Context, UUID string) (*workspacesProto.Workspace, error) {
+	deletion, err := s.requestedDeletion(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := s.repo.GetDeleted(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+	if deletion.State != entity.DeletionStateScheduled {
+		return nil, errPurgeStarted
+	}
+
+	deletion.State = entity.DeletionStateRestored
+	deletion.UpdatedAt = time.Now()
+	if err := s.repo.SaveDeletion(ctx, deletion); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restore(ctx, workspace.ID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, UUID)
+}
+
+// GetDeletion returns the deletion progress of the workspace with the specified UUID to the user who deleted it.
+func (s service) GetDeletion(ctx context.Context, UUID string) (*workspacesProto.WorkspaceDeletion, error) {
+	deletion, err := s.requestedDeletion(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+	return deletion.ToProto(), nil
+}
+
+// requestedDeletion returns the deletion of the workspace if the current user requested it.
+// Deleted workspaces have no members to authorize, the user who deleted one keeps access to its deletion.
+func (s service) requestedDeletion(ctx context.Context, UUID string) (entity.WorkspaceDeletion, error) {
+	user, err := auth.ExtractUser(ctx)
+	if err != nil {
+		return entity.WorkspaceDeletion{}, err
+	}
+	deletion, err := s.repo.GetDeletion(ctx, UUID)
+	if err != nil {
+		return deletion, err
+	}
+	if deletion.RequestedByID == 0 || deletion.RequestedByID != user.Id {
+		return deletion, errNotRequester
+	}
+	return deletion, nil
+}
+
+// PurgeDue purges the workspaces whose grace period ended, resuming interrupted purges.
+func (s service) PurgeDue(ctx context.Context) error {
+	deletions, err := s.repo.DueDeletions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, deletion := range deletions {
+		if err := s.purge(ctx, deletion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purge removes the rows of the workspace table by table in batches, saving the progress after each batch.
+func (s service) purge(ctx context.Context, deletion entity.WorkspaceDeletion) error {
+	if deletion.StartedAt.IsZero() {
+		deletion.StartedAt = time.Now()
+	}
+	deletion.State = entity.DeletionStatePurging
+	deletion.TablesTotal = int64(len(purgeTables))
+	batchSize := purgeBatchSize.Int()
+
+	for i, t := range purgeTables {
+		// tables before the current one are already empty
+		if int64(i) < deletion.TablesDone {
+			continue
+		}
+		deletion.CurrentTable = t.table
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			n, err := s.repo.PurgeBatch(ctx, t.table, deletion.WorkspaceID, batchSize)
+			if err != nil {
+				return err
+			}
+			deletion.DeletedRows += int64(n)
+			deletion.UpdatedAt = time.Now()
+			if n < batchSize {
+				break
+			}
+			if err := s.repo.SaveDeletion(ctx, deletion); err != nil {
+				return err
+			}
+		}
+		deletion.TablesDone = int64(i + 1)
+		if err := s.repo.SaveDeletion(ctx, deletion); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Purge(ctx, deletion.WorkspaceID); err != nil {
+		return err
+	}
+	now := time.Now()
+	deletion.State = entity.DeletionStateCompleted
+	deletion.CurrentTable = ""
+	deletion.FinishedAt = now
+	deletion.UpdatedAt = now
+	return s.repo.SaveDeletion(ctx, deletion)
+}
+
+// RunPurger purges the due workspace deletions periodically until the context is done.
+func RunPurger(ctx context.Context, srv Service) {
+	ticker := time.NewTicker(time.Second * time.Duration(purgeInterval.Int()))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := srv.PurgeDue(ctx); err != nil && ctx.Err() == nil {
+				log.Error("workspace purge failed", log.Err(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/mail"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+var (
+	// invitationSecret signs the invitations, which cannot be sent or accepted without it
+	invitationSecret = config.RegisterString("workspaces.invitationSecret", "")
+	invitationLife   = config.RegisterInt("workspaces.invitationLife", 72)
+	// invitationURL is the page of the web app accepting the invitation, the token is added as a query parameter
+	invitationURL = config.RegisterString("workspaces.invitationURL", "")
+)
+
+var (
+	errInvalidInvitation  = errors.New("invitation is invalid or expired")
+	errNoInvitationSecret = errors.New("invitations are disabled, workspaces.invitationSecret is not set")
+	errUnverifiedEmail    = errors.New("the email of the user must be verified to accept an invitation")
+	errInvitationClosed   = errors.New("the invitation was already accepted or revoked")
+	// ErrRoleNotGrantable is returned by the membership hooks when the inviter does not have every permission of the invited role
+	ErrRoleNotGrantable = errors.New("the role has permissions the inviter does not have")
+)
+
+// invitation is the content of a signed invitation token, its ID is the UUID of the invitation record
+type invitation struct {
+	WorkspaceUUID string `json:"workspace"`
+	jwt.StandardClaims
+}
+
+// NewInvitation returns the record of an invitation of the email to the workspace, with the role if any.
+func NewInvitation(workspaceID, inviterID uint64, email, roleUUID string, now time.Time) entity.WorkspaceInvitation {
+	return entity.WorkspaceInvitation{
+		UUID:        uuid.New().String(),
+		WorkspaceID: workspaceID,
+		Email:       strings.ToLower(email),
+		RoleUUID:    roleUUID,
+		InviterID:   inviterID,
+		ExpiresAt:   now.Add(time.Hour * time.Duration(invitationLife.Int())),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// signInvitation returns a token of the invitation record to the workspace.
+func signInvitation(workspaceUUID string, inv entity.WorkspaceInvitation) (string, error) {
+	if invitationSecret.String() == "" {
+		return "", errNoInvitationSecret
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, invitation{
+		WorkspaceUUID: workspaceUUID,
+		StandardClaims: jwt.StandardClaims{
+			Id:        inv.UUID,
+			Subject:   "workspace-invitation",
+			IssuedAt:  inv.CreatedAt.Unix(),
+			ExpiresAt: inv.ExpiresAt.Unix(),
+		},
+	})
+	return token.SignedString([]byte(invitationSecret.String()))
+}
+
+// parseInvitation verifies the signature and the expiry of the invitation token.
+func parseInvitation(token string) (*invitation, error) {
+	if invitationSecret.String() == "" {
+		return nil, errNoInvitationSecret
+	}
+	var inv invitation
+	_, err := jwt.ParseWithClaims(token, &inv, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(invitationSecret.String()), nil
+	})
+	if err != nil || inv.Subject != "workspace-invitation" || inv.WorkspaceUUID == "" || inv.Id == "" {
+		return nil, errInvalidInvitation
+	}
+	return &inv, nil
+}
+
+// SendInvitation signs a token of the saved invitation record and emails it to the invited email.
+// The token is only sent to the email, the invitation is accepted with it once.
+func SendInvitation(ctx context.Context, workspace *workspacesProto.Workspace, inv entity.WorkspaceInvitation) error {
+	token, err := signInvitation(workspace.Uuid, inv)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Hi,\n\nYou are invited to join %s. Use this token to accept the invitation: %s\n", workspace.Title, token)
+	if u := invitationURL.String(); u != "" {
+		body = fmt.Sprintf("Hi,\n\nYou are invited to join %s. Open this link to accept the invitation: %s?token=%s\n", workspace.Title, u, token)
+	}
+	body += fmt.Sprintf("\nIt expires in %d hours. If you do not know %s, ignore this email.\n", invitationLife.Int(), workspace.Title)
+	return mail.Send(ctx, mail.Message{To: []string{inv.Email}, Subject: "Invitation to " + workspace.Title, Body: body})
+}
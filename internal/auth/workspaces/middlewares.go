@@ -0,0 +1,148 @@
+package workspaces
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/grpcgw"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// baseDomain is the domain workspaces are served under as subdomains, e.g. acme.pm.example.com
+var baseDomain = config.RegisterString("workspaces.baseDomain", "")
+
+const (
+	// workspaceMetadataKey is the metadata key (and http header) holding the UUID or domain of the workspace
+	workspaceMetadataKey = "x-workspace"
+	// hostMetadataKey is the metadata key the http gateway forwards the request host in
+	hostMetadataKey = "x-forwarded-host"
+)
+
+// resolver puts the workspace of the request into the context. The workspace is taken from the
+// x-workspace metadata if present, otherwise from the request host, either matching a workspace
+// domain as a whole or as a subdomain of the base domain.
+// Only the methods authorized in a workspace, or declared in it, act in it, their callers must be members of the workspace,
+// the other methods are served without it. Requests without a workspace are passed through,
+// the authorizer and the workspace scoped repositories reject them.
+type resolver struct {
+	service    Service
+	baseDomain string
+}
+
+func (r resolver) resolve(ctx context.Context, method string) (context.Context, error) {
+	if policy, ok := auth.MethodPolicyOf(method); !ok || (policy.Access != auth.AccessAuthorized && !policy.InWorkspace) {
+		return ctx, nil
+	}
+	workspace, err := r.workspace(ctx)
+	if err != nil || workspace == nil {
+		return ctx, err
+	}
+	ctx = auth.ContextWithWorkspace(ctx, workspace)
+	user, err := auth.ExtractUser(ctx)
+	if err != nil {
+		// the authorizer rejects the request
+		return ctx, nil
+	}
+	member, err := r.service.IsMember(ctx, user.Uuid)
+	if err != nil {
+		return ctx, status.Error(codes.Internal, "checking the membership failed")
+	}
+	if !member {
+		return ctx, status.Error(codes.PermissionDenied, "not a member of the workspace")
+	}
+	return ctx, nil
+}
+
+// workspace returns the workspace of the request, nil if it has none.
+func (r resolver) workspace(ctx context.Context) (*workspacesProto.Workspace, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	if key := firstValue(md, workspaceMetadataKey); key != "" {
+		workspace, err := r.service.Get(ctx, key)
+		if err != nil {
+			workspace, err = r.service.GetByDomain(ctx, key)
+		}
+		if err != nil {
+			return nil, status.Error(codes.NotFound, "workspace not found")
+		}
+		return workspace, nil
+	}
+
+	for _, domain := range hostDomains(firstValue(md, hostMetadataKey), r.baseDomain) {
+		if workspace, err := r.service.GetByDomain(ctx, domain); err == nil {
+			return workspace, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r resolver) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := r.resolve(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (r resolver) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := r.resolve(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	wrapped := grpcMiddleware.WrapServerStream(ss)
+	wrapped.WrappedContext = ctx
+	return handler(srv, wrapped)
+}
+
+// firstValue returns the first value of the metadata key
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
+
+// hostDomains returns the workspace domains a request host may point to: the host itself and,
+// when the host is a direct subdomain of the base domain, the subdomain label.
+func hostDomains(host, base string) []string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return nil
+	}
+	domains := []string{host}
+
+	base = strings.TrimSuffix(strings.ToLower(base), ".")
+	if base != "" && strings.HasSuffix(host, "."+base) {
+		label := strings.TrimSuffix(host, "."+base)
+		if label != "" && !strings.Contains(label, ".") {
+			domains = append(domains, label)
+		}
+	}
+	return domains
+}
+
+// RegisterResolver registers the interceptors resolving the workspace of every request
+// and forwards the x-workspace http header through the gateway.
+func RegisterResolver(srv Service) {
+	r := resolver{service: srv, baseDomain: baseDomain.String()}
+	grpcgw.RegisterIncomingHeaders(workspaceMetadataKey)
+	grpcgw.RegisterInterceptors(grpcgw.Interceptor{
+		Unary:  r.unary,
+		Stream: r.stream,
+	})
+}
@@ -0,0 +1,83 @@
+package workspaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestResolver(t *testing.T) {
+	member := entity.User{ID: 1, UUID: "u1", Username: "member"}
+	stranger := entity.User{ID: 2, UUID: "u2", Username: "stranger"}
+	r := resolver{service: NewService(&mockRepository{
+		items: []entity.Workspace{
+			{ID: 1, UUID: "b0c1bd4e-3f5e-4b4c-9d1a-0e6c2f9b7a11", Title: "Acme", Domain: "acme"},
+			{ID: 2, UUID: "e4f2c7a0-8d5b-4a3e-b6f1-2c9d0a7e5b22", Title: "Globex", Domain: "tracker.globex.com"},
+		},
+		members: []entity.WorkspaceMember{
+			{WorkspaceID: 1, UserID: 1, User: &member},
+			{WorkspaceID: 2, UserID: 1, User: &member},
+			{WorkspaceID: 2, UserID: 2, User: &stranger},
+		},
+	}), baseDomain: "pm.example.com"}
+	info := &grpc.UnaryServerInfo{FullMethod: "/workspacesV1.WorkspaceService/ListWorkspaceMembers"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return auth.ExtractWorkspace(ctx)
+	}
+	userCtx := auth.ContextWithUser(context.Background(), member.ToProto(false))
+
+	workspaceID := func(md metadata.MD) uint64 {
+		res, err := r.unary(metadata.NewIncomingContext(userCtx, md), nil, info, handler)
+		if err != nil {
+			return 0
+		}
+		return res.(*workspacesProto.Workspace).Id
+	}
+
+	// workspace selected by metadata, by uuid or domain
+	assert.Equal(t, uint64(1), workspaceID(metadata.Pairs(workspaceMetadataKey, "b0c1bd4e-3f5e-4b4c-9d1a-0e6c2f9b7a11")))
+	assert.Equal(t, uint64(2), workspaceID(metadata.Pairs(workspaceMetadataKey, "tracker.globex.com")))
+
+	// workspace selected by host, metadata wins over the host
+	assert.Equal(t, uint64(1), workspaceID(metadata.Pairs(hostMetadataKey, "ACME.pm.example.com:8080")))
+	assert.Equal(t, uint64(2), workspaceID(metadata.Pairs(hostMetadataKey, "tracker.globex.com")))
+	assert.Equal(t, uint64(0), workspaceID(metadata.Pairs(hostMetadataKey, "pm.example.com")))
+	assert.Equal(t, uint64(0), workspaceID(metadata.Pairs(hostMetadataKey, "x.acme.pm.example.com")))
+	assert.Equal(t, uint64(2), workspaceID(metadata.Pairs(
+		hostMetadataKey, "acme.pm.example.com", workspaceMetadataKey, "tracker.globex.com",
+	)))
+
+	// unknown workspace
+	ctx := metadata.NewIncomingContext(userCtx, metadata.Pairs(workspaceMetadataKey, "none"))
+	_, err := r.unary(ctx, nil, info, handler)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// no workspace selected
+	_, err = r.unary(userCtx, nil, info, handler)
+	assert.Equal(t, auth.ErrNoWorkspace, err)
+
+	// only the members act in a workspace
+	ctx = metadata.NewIncomingContext(auth.ContextWithUser(context.Background(), stranger.ToProto(false)),
+		metadata.Pairs(workspaceMetadataKey, "acme"))
+	_, err = r.unary(ctx, nil, info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// the methods not authorized in a workspace are served without it
+	_, err = r.unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/workspacesV1.WorkspaceService/AcceptWorkspaceInvitation"}, handler)
+	assert.Equal(t, auth.ErrNoWorkspace, err)
+}
+
+func TestHostDomains(t *testing.T) {
+	assert.Equal(t, []string{"acme.pm.example.com", "acme"}, hostDomains("acme.pm.example.com", "pm.example.com"))
+	assert.Equal(t, []string{"acme.com"}, hostDomains("Acme.com.:443", "pm.example.com"))
+	assert.Equal(t, []string{"acme.pm.example.com"}, hostDomains("acme.pm.example.com", ""))
+	assert.Nil(t, hostDomains("", "pm.example.com"))
+}
@@ -0,0 +1,412 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/config"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+var errCRUD = errors.New("error crud")
+
+// NewServiceForTest creates a new workspace service for test notifying the hooks.
+func NewServiceForTest(hooks ...MembershipHook) Service {
+	return NewService(&mockRepository{}, hooks...)
+}
+
+// AddMemberForTest makes the user a member of the workspace with the specified ID in a service created by NewServiceForTest.
+func AddMemberForTest(s Service, workspaceID uint64, user *usersProto.User) {
+	repo := s.(service).repo.(*mockRepository)
+	model := entity.UserFromProto(user)
+	repo.members = append(repo.members, entity.WorkspaceMember{WorkspaceID: workspaceID, UserID: user.Id, User: &model})
+}
+
+// AddRoleForTest adds the role to the workspace with the specified ID in a service created by NewServiceForTest.
+func AddRoleForTest(s Service, workspaceID uint64, roleUUID string) {
+	repo := s.(service).repo.(*mockRepository)
+	repo.roles = append(repo.roles, entity.Role{WorkspaceID: workspaceID, UUID: roleUUID})
+}
+
+// SetEstimateScaleForTest sets the estimate scale of the workspace with the specified ID in a service created by NewServiceForTest.
+func SetEstimateScaleForTest(s Service, workspaceID uint64, scale string) {
+	repo := s.(service).repo.(*mockRepository)
+	settings := entity.DefaultWorkspaceSettings(workspaceID)
+	settings.EstimateScale = scale
+	_ = repo.SaveSettings(context.Background(), settings)
+}
+
+// MockInvitationsForTest sets a secret to sign the invitations with, the returned function unsets it.
+func MockInvitationsForTest() func() {
+	invitationSecret = config.RegisterStringMock("workspaces.invitationSecret", "test-secret")
+	return func() {
+		invitationSecret = config.RegisterStringMock("workspaces.invitationSecret", "")
+	}
+}
+
+type mockRepository struct {
+	items     []entity.Workspace
+	members   []entity.WorkspaceMember
+	roles     []entity.Role
+	users     []entity.User
+	settings  []entity.WorkspaceSettings
+	statuses  []entity.IssueStatus
+	deleted   []entity.Workspace
+	deletions []entity.WorkspaceDeletion
+	// rows is the number of rows of each workspace in each purged table
+	rows        map[uint64]map[string]int
+	quotas      []entity.WorkspaceQuota
+	issues      map[uint64]int
+	invitations []entity.WorkspaceInvitation
+	// locked are the workspaces locked by the quota checks
+	locked []uint64
+	lastID uint64
+}
+
+func (m mockRepository) Get(ctx context.Context, id string) (entity.Workspace, error) {
+	for _, item := range m.items {
+		if item.UUID == id {
+			return item, nil
+		}
+	}
+	return entity.Workspace{}, pg.ErrNoRows
+}
+
+func (m mockRepository) GetByDomain(ctx context.Context, domain string) (entity.Workspace, error) {
+	for _, item := range m.items {
+		if item.Domain == domain {
+			return item, nil
+		}
+	}
+	return entity.Workspace{}, pg.ErrNoRows
+}
+
+func (m mockRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(m.items)), nil
+}
+
+func (m mockRepository) Query(ctx context.Context, offset, limit int64) ([]entity.Workspace, int, error) {
+	return m.items, len(m.items), nil
+}
+
+func (m *mockRepository) Create(ctx context.Context, workspace entity.Workspace) error {
+	if workspace.Title == "error" {
+		return errCRUD
+	}
+	m.lastID++
+	workspace.ID = m.lastID
+	m.items = append(m.items, workspace)
+	return nil
+}
+
+func (m *mockRepository) Update(ctx context.Context, workspace entity.Workspace) error {
+	if workspace.Title == "error" {
+		return errCRUD
+	}
+	for i, item := range m.items {
+		if item.UUID == workspace.UUID {
+			m.items[i] = workspace
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) Delete(ctx context.Context, id string) error {
+	for i, item := range m.items {
+		if item.UUID == id {
+			m.deleted = append(m.deleted, item)
+			m.items[i] = m.items[len(m.items)-1]
+			m.items = m.items[:len(m.items)-1]
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	return f(ctx)
+}
+
+func (m mockRepository) GetMember(ctx context.Context, workspaceID, userID uint64) (entity.WorkspaceMember, error) {
+	for _, item := range m.members {
+		if item.WorkspaceID == workspaceID && item.UserID == userID {
+			return item, nil
+		}
+	}
+	return entity.WorkspaceMember{}, pg.ErrNoRows
+}
+
+func (m mockRepository) QueryMembers(ctx context.Context, workspaceID uint64, offset, limit int64) ([]entity.WorkspaceMember, int, error) {
+	var members []entity.WorkspaceMember
+	for _, item := range m.members {
+		if item.WorkspaceID == workspaceID {
+			members = append(members, item)
+		}
+	}
+	return members, len(members), nil
+}
+
+func (m *mockRepository) AddMember(ctx context.Context, member entity.WorkspaceMember) error {
+	for i := range m.users {
+		if m.users[i].ID == member.UserID {
+			member.User = &m.users[i]
+		}
+	}
+	for i := range m.roles {
+		if m.roles[i].ID == member.RoleID {
+			member.Role = &m.roles[i]
+		}
+	}
+	m.members = append(m.members, member)
+	return nil
+}
+
+func (m *mockRepository) RemoveMember(ctx context.Context, workspaceID uint64, userUUID string) error {
+	for i, item := range m.members {
+		if item.WorkspaceID == workspaceID && item.User != nil && item.User.UUID == userUUID {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+			return nil
+		}
+	}
+	return pg.ErrNoRows
+}
+
+func (m mockRepository) IsMember(ctx context.Context, workspaceID uint64, userUUID string) (bool, error) {
+	for _, item := range m.members {
+		if item.WorkspaceID == workspaceID && item.User != nil && item.User.UUID == userUUID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m mockRepository) GetRole(ctx context.Context, workspaceID uint64, uuid string) (entity.Role, error) {
+	for _, item := range m.roles {
+		if item.WorkspaceID == workspaceID && item.UUID == uuid {
+			return item, nil
+		}
+	}
+	return entity.Role{}, pg.ErrNoRows
+}
+
+func (m *mockRepository) CreateInvitation(ctx context.Context, invitation entity.WorkspaceInvitation) error {
+	m.lastID++
+	invitation.ID = m.lastID
+	m.invitations = append(m.invitations, invitation)
+	return nil
+}
+
+func (m mockRepository) GetInvitation(ctx context.Context, uuid string) (entity.WorkspaceInvitation, error) {
+	for _, item := range m.invitations {
+		if item.UUID == uuid {
+			return item, nil
+		}
+	}
+	return entity.WorkspaceInvitation{}, pg.ErrNoRows
+}
+
+func (m *mockRepository) AcceptInvitation(ctx context.Context, id uint64, now time.Time) (bool, error) {
+	for i, item := range m.invitations {
+		if item.ID == id && item.AcceptedAt.IsZero() && item.RevokedAt.IsZero() {
+			m.invitations[i].AcceptedAt = now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockRepository) RevokeInvitation(ctx context.Context, id uint64, now time.Time) (bool, error) {
+	for i, item := range m.invitations {
+		if item.ID == id && item.AcceptedAt.IsZero() && item.RevokedAt.IsZero() {
+			m.invitations[i].RevokedAt = now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m mockRepository) GetSettings(ctx context.Context, workspaceID uint64) (entity.WorkspaceSettings, error) {
+	for _, item := range m.settings {
+		if item.WorkspaceID == workspaceID {
+			return item, nil
+		}
+	}
+	return entity.WorkspaceSettings{}, pg.ErrNoRows
+}
+
+func (m *mockRepository) SaveSettings(ctx context.Context, settings entity.WorkspaceSettings) error {
+	for i := range m.items {
+		if m.items[i].ID == settings.WorkspaceID {
+			settings.Workspace = &m.items[i]
+		}
+	}
+	for i := range m.statuses {
+		if m.statuses[i].ID == settings.DefaultStatusID {
+			settings.DefaultStatus = &m.statuses[i]
+		}
+	}
+	for i, item := range m.settings {
+		if item.WorkspaceID == settings.WorkspaceID {
+			settings.CreatedAt = item.CreatedAt
+			m.settings[i] = settings
+			return nil
+		}
+	}
+	m.settings = append(m.settings, settings)
+	return nil
+}
+
+func (m mockRepository) GetStatus(ctx context.Context, workspaceID uint64, uuid string) (entity.IssueStatus, error) {
+	for _, item := range m.statuses {
+		if item.WorkspaceID == workspaceID && item.UUID == uuid {
+			return item, nil
+		}
+	}
+	return entity.IssueStatus{}, pg.ErrNoRows
+}
+
+func (m mockRepository) GetDeleted(ctx context.Context, uuid string) (entity.Workspace, error) {
+	for _, item := range m.deleted {
+		if item.UUID == uuid {
+			return item, nil
+		}
+	}
+	return entity.Workspace{}, pg.ErrNoRows
+}
+
+func (m *mockRepository) Restore(ctx context.Context, workspaceID uint64) error {
+	for i, item := range m.deleted {
+		if item.ID == workspaceID {
+			m.items = append(m.items, item)
+			m.deleted = append(m.deleted[:i], m.deleted[i+1:]...)
+			return nil
+		}
+	}
+	return pg.ErrNoRows
+}
+
+func (m mockRepository) GetDeletion(ctx context.Context, workspaceUUID string) (entity.WorkspaceDeletion, error) {
+	for _, item := range m.deletions {
+		if item.WorkspaceUUID == workspaceUUID {
+			return item, nil
+		}
+	}
+	return entity.WorkspaceDeletion{}, pg.ErrNoRows
+}
+
+func (m *mockRepository) SaveDeletion(ctx context.Context, deletion entity.WorkspaceDeletion) error {
+	for i, item := range m.deletions {
+		if item.WorkspaceID == deletion.WorkspaceID {
+			m.deletions[i] = deletion
+			return nil
+		}
+	}
+	m.deletions = append(m.deletions, deletion)
+	return nil
+}
+
+func (m mockRepository) DueDeletions(ctx context.Context, now time.Time) ([]entity.WorkspaceDeletion, error) {
+	var deletions []entity.WorkspaceDeletion
+	for _, item := range m.deletions {
+		if (item.State == entity.DeletionStateScheduled || item.State == entity.DeletionStatePurging) && !item.PurgeAfter.After(now) {
+			deletions = append(deletions, item)
+		}
+	}
+	return deletions, nil
+}
+
+func (m *mockRepository) PurgeBatch(ctx context.Context, table string, workspaceID uint64, size int) (int, error) {
+	n := m.rows[workspaceID][table]
+	if n > size {
+		n = size
+	}
+	if n > 0 {
+		m.rows[workspaceID][table] -= n
+	}
+	return n, nil
+}
+
+func (m *mockRepository) Purge(ctx context.Context, workspaceID uint64) error {
+	for i, item := range m.deleted {
+		if item.ID == workspaceID {
+			m.deleted = append(m.deleted[:i], m.deleted[i+1:]...)
+			return nil
+		}
+	}
+	return pg.ErrNoRows
+}
+
+func (m mockRepository) GetQuota(ctx context.Context, workspaceID uint64) (entity.WorkspaceQuota, error) {
+	for _, item := range m.quotas {
+		if item.WorkspaceID == workspaceID {
+			return item, nil
+		}
+	}
+	return entity.WorkspaceQuota{}, pg.ErrNoRows
+}
+
+func (m *mockRepository) SaveQuota(ctx context.Context, quota entity.WorkspaceQuota) error {
+	for i, item := range m.quotas {
+		if item.WorkspaceID == quota.WorkspaceID {
+			m.quotas[i] = quota
+			return nil
+		}
+	}
+	m.quotas = append(m.quotas, quota)
+	return nil
+}
+
+func (m mockRepository) CountMembers(ctx context.Context, workspaceID uint64) (int, error) {
+	count := 0
+	for _, item := range m.members {
+		if item.WorkspaceID == workspaceID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockRepository) LockWorkspace(ctx context.Context, workspaceID uint64) error {
+	m.locked = append(m.locked, workspaceID)
+	return nil
+}
+
+func (m mockRepository) CountIssues(ctx context.Context, workspaceID uint64) (int, error) {
+	return m.issues[workspaceID], nil
+}
+
+// mockHook records the membership changes it is notified of
+type mockHook struct {
+	events []string
+	// notGrantable is a role the inviters cannot grant
+	notGrantable string
+}
+
+func (h *mockHook) WorkspaceCreated(ctx context.Context, workspace *workspacesProto.Workspace, owner *usersProto.User) error {
+	h.events = append(h.events, fmt.Sprintf("created %s %s", workspace.Domain, owner.Uuid))
+	return nil
+}
+
+func (h *mockHook) MemberInvited(ctx context.Context, workspace *workspacesProto.Workspace, inviter *usersProto.User, roleUUID string) error {
+	if roleUUID != "" && roleUUID == h.notGrantable {
+		return ErrRoleNotGrantable
+	}
+	h.events = append(h.events, fmt.Sprintf("invited %s %s %s", workspace.Domain, inviter.Uuid, roleUUID))
+	return nil
+}
+
+func (h *mockHook) MemberJoined(ctx context.Context, workspace *workspacesProto.Workspace, user *usersProto.User, roleUUID string) error {
+	h.events = append(h.events, fmt.Sprintf("joined %s %s %s", workspace.Domain, user.Uuid, roleUUID))
+	return nil
+}
+
+func (h *mockHook) MemberRemoved(ctx context.Context, workspace *workspacesProto.Workspace, userUUID string) error {
+	h.events = append(h.events, fmt.Sprintf("removed %s %s", workspace.Domain, userUUID))
+	return nil
+}
@@ -0,0 +1,167 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/config"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+// default limits of the workspaces without quotas of their own, 0 means unlimited
+var (
+	defaultMaxMembers      = config.RegisterInt("quotas.maxMembers", 0)
+	defaultMaxIssues       = config.RegisterInt("quotas.maxIssues", 0)
+	defaultMaxStorageBytes = config.RegisterInt("quotas.maxStorageBytes", 0)
+)
+
+// Resources limited by the workspace quotas
+const (
+	QuotaMembers = "members"
+	QuotaIssues  = "issues"
+	QuotaStorage = "storage bytes"
+)
+
+// ErrQuotaExceeded is returned when a workspace reached one of its limits
+var ErrQuotaExceeded = errors.New("workspace quota exceeded")
+
+// ValidateUpdateQuotasRequest validates the UpdateWorkspaceQuotasRequest fields.
+func ValidateUpdateQuotasRequest(u *workspacesProto.UpdateWorkspaceQuotasRequest) error {
+	return validation.ValidateStruct(u,
+		validation.Field(&u.WorkspaceUuid, validation.Required, is.UUID),
+		validation.Field(&u.MaxMembers, validation.Min(int64(0))),
+		validation.Field(&u.MaxIssues, validation.Min(int64(0))),
+		validation.Field(&u.MaxStorageBytes, validation.Min(int64(0))),
+	)
+}
+
+// quotas returns the quotas of the workspace, or the configured defaults if it has none.
+func (s service) quotas(ctx context.Context, workspaceID uint64) (entity.WorkspaceQuota, error) {
+	quota, err := s.repo.GetQuota(ctx, workspaceID)
+	if err == pg.ErrNoRows {
+		return entity.WorkspaceQuota{
+			WorkspaceID:     workspaceID,
+			MaxMembers:      int64(defaultMaxMembers.Int()),
+			MaxIssues:       int64(defaultMaxIssues.Int()),
+			MaxStorageBytes: int64(defaultMaxStorageBytes.Int()),
+		}, nil
+	}
+	return quota, err
+}
+
+// usage returns the amount of the resource the workspace uses.
+// There are no attachments yet, so no storage is ever used.
+func (s service) usage(ctx context.Context, workspaceID uint64, resource string) (int64, error) {
+	switch resource {
+	case QuotaMembers:
+		n, err := s.repo.CountMembers(ctx, workspaceID)
+		return int64(n), err
+	case QuotaIssues:
+		n, err := s.repo.CountIssues(ctx, workspaceID)
+		return int64(n), err
+	case QuotaStorage:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unknown quota resource %q", resource)
+}
+
+// checkQuota returns ErrQuotaExceeded if adding n of the resource takes the workspace over its limit.
+// It must run in the transaction adding the resource: the workspace stays locked until it ends,
+// so concurrent requests count the resource after it was added.
+func (s service) checkQuota(ctx context.Context, workspaceID uint64, resource string, n int64) error {
+	if err := s.repo.LockWorkspace(ctx, workspaceID); err != nil {
+		return err
+	}
+	quota, err := s.quotas(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	var limit int64
+	switch resource {
+	case QuotaMembers:
+		limit = quota.MaxMembers
+	case QuotaIssues:
+		limit = quota.MaxIssues
+	case QuotaStorage:
+		limit = quota.MaxStorageBytes
+	}
+	if limit == 0 {
+		return nil
+	}
+	used, err := s.usage(ctx, workspaceID, resource)
+	if err != nil {
+		return err
+	}
+	if used+n > limit {
+		return fmt.Errorf("%w: the limit of %d %s is reached", ErrQuotaExceeded, limit, resource)
+	}
+	return nil
+}
+
+// CheckQuota returns ErrQuotaExceeded if adding n of the resource takes the workspace of the request over its limit.
+// It must run in the transaction adding the resource.
+func (s service) CheckQuota(ctx context.Context, resource string, n int64) error {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	return s.checkQuota(ctx, workspace.Id, resource, n)
+}
+
+// GetUsage returns the usage and the quotas of the workspace with the specified UUID.
+func (s service) GetUsage(ctx context.Context, workspaceUUID string) (*workspacesProto.WorkspaceUsage, error) {
+	workspace, err := s.repo.Get(ctx, workspaceUUID)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := s.quotas(ctx, workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := &workspacesProto.WorkspaceUsage{WorkspaceUuid: workspace.UUID, Quotas: quota.ToProto()}
+	if res.Members, err = s.usage(ctx, workspace.ID, QuotaMembers); err != nil {
+		return nil, err
+	}
+	if res.Issues, err = s.usage(ctx, workspace.ID, QuotaIssues); err != nil {
+		return nil, err
+	}
+	if res.StorageBytes, err = s.usage(ctx, workspace.ID, QuotaStorage); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateQuotas sets the quotas of the workspace with the specified UUID.
+func (s service) UpdateQuotas(ctx context.Context, req *workspacesProto.UpdateWorkspaceQuotasRequest) (*workspacesProto.WorkspaceQuotas, error) {
+	if err := ValidateUpdateQuotasRequest(req); err != nil {
+		return nil, err
+	}
+	workspace, err := s.repo.Get(ctx, req.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := s.quotas(ctx, workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if quota.CreatedAt.IsZero() {
+		quota.CreatedAt = now
+	}
+	quota.MaxMembers = req.MaxMembers
+	quota.MaxIssues = req.MaxIssues
+	quota.MaxStorageBytes = req.MaxStorageBytes
+	quota.UpdatedAt = now
+	if err := s.repo.SaveQuota(ctx, quota); err != nil {
+		return nil, err
+	}
+	return quota.ToProto(), nil
+}
@@ -2,7 +2,10 @@ package workspaces
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/go-pg/pg/v10"
 	"github.com/mirzakhany/pm/internal/entity"
 
 	"github.com/mirzakhany/pm/pkg/db"
@@ -12,6 +15,8 @@ import (
 type Repository interface {
 	// Get returns the workspace with the specified workspace UUID.
 	Get(ctx context.Context, uuid string) (entity.Workspace, error)
+	// GetByDomain returns the workspace with the specified domain.
+	GetByDomain(ctx context.Context, domain string) (entity.Workspace, error)
 	// Count returns the number of workspaces.
 	Count(ctx context.Context) (int64, error)
 	// Query returns the list of workspaces with the given offset and limit.
@@ -20,8 +25,97 @@ type Repository interface {
 	Create(ctx context.Context, workspace entity.Workspace) error
 	// Update updates the workspace with given UUID in the storage.
 	Update(ctx context.Context, workspace entity.Workspace) error
-	// Delete removes the workspace with given UUID from the storage.
+	// Delete soft deletes the workspace with given UUID, it can be restored until it is purged.
 	Delete(ctx context.Context, uuid string) error
+	// Transactional runs f in a transaction.
+	Transactional(ctx context.Context, f func(ctx context.Context) error) error
+
+	// WorkspaceMember
+
+	// GetMember returns the membership of the user in the workspace.
+	GetMember(ctx context.Context, workspaceID, userID uint64) (entity.WorkspaceMember, error)
+	// QueryMembers returns the members of the workspace with the given offset and limit.
+	QueryMembers(ctx context.Context, workspaceID uint64, offset, limit int64) ([]entity.WorkspaceMember, int, error)
+	// AddMember saves a new member of a workspace in the storage.
+	AddMember(ctx context.Context, member entity.WorkspaceMember) error
+	// RemoveMember removes the user with given UUID from the members of the workspace.
+	RemoveMember(ctx context.Context, workspaceID uint64, userUUID string) error
+	// IsMember returns whether the user with given UUID is a member of the workspace.
+	IsMember(ctx context.Context, workspaceID uint64, userUUID string) (bool, error)
+	// GetRole returns the role with the specified UUID in the workspace.
+	GetRole(ctx context.Context, workspaceID uint64, uuid string) (entity.Role, error)
+
+	// WorkspaceInvitation
+
+	// CreateInvitation saves a new invitation to a workspace in the storage.
+	CreateInvitation(ctx context.Context, invitation entity.WorkspaceInvitation) error
+	// GetInvitation returns the invitation with the specified UUID.
+	GetInvitation(ctx context.Context, uuid string) (entity.WorkspaceInvitation, error)
+	// AcceptInvitation marks the invitation as accepted and returns false if it was already accepted or revoked.
+	AcceptInvitation(ctx context.Context, id uint64, now time.Time) (bool, error)
+	// RevokeInvitation marks the invitation as revoked and returns false if it was already accepted or revoked.
+	RevokeInvitation(ctx context.Context, id uint64, now time.Time) (bool, error)
+
+	// WorkspaceSettings
+
+	// GetSettings returns the settings of the workspace.
+	GetSettings(ctx context.Context, workspaceID uint64) (entity.WorkspaceSettings, error)
+	// SaveSettings creates or updates the settings of a workspace.
+	SaveSettings(ctx context.Context, settings entity.WorkspaceSettings) error
+	// GetStatus returns the issue status with the specified UUID in the workspace.
+	GetStatus(ctx context.Context, workspaceID uint64, uuid string) (entity.IssueStatus, error)
+
+	// WorkspaceDeletion
+
+	// GetDeleted returns the soft deleted workspace with the specified UUID.
+	GetDeleted(ctx context.Context, uuid string) (entity.Workspace, error)
+	// Restore undoes the soft deletion of the workspace.
+	Restore(ctx context.Context, workspaceID uint64) error
+	// GetDeletion returns the deletion of the workspace with the specified UUID.
+	GetDeletion(ctx context.Context, workspaceUUID string) (entity.WorkspaceDeletion, error)
+	// SaveDeletion creates or updates the deletion of a workspace.
+	SaveDeletion(ctx context.Context, deletion entity.WorkspaceDeletion) error
+	// DueDeletions returns the deletions whose grace period ended before the given time and are not purged yet.
+	DueDeletions(ctx context.Context, now time.Time) ([]entity.WorkspaceDeletion, error)
+	// PurgeBatch removes at most size rows of the workspace from the table and returns how many were removed.
+	PurgeBatch(ctx context.Context, table string, workspaceID uint64, size int) (int, error)
+	// Purge removes the soft deleted workspace row.
+	Purge(ctx context.Context, workspaceID uint64) error
+
+	// WorkspaceQuota
+
+	// GetQuota returns the quotas of the workspace.
+	GetQuota(ctx context.Context, workspaceID uint64) (entity.WorkspaceQuota, error)
+	// SaveQuota creates or updates the quotas of a workspace.
+	SaveQuota(ctx context.Context, quota entity.WorkspaceQuota) error
+	// LockWorkspace locks the workspace until the end of the transaction, the quota checks of the other transactions wait for it.
+	LockWorkspace(ctx context.Context, workspaceID uint64) error
+	// CountMembers returns the number of members of the workspace.
+	CountMembers(ctx context.Context, workspaceID uint64) (int, error)
+	// CountIssues returns the number of issues of the workspace.
+	CountIssues(ctx context.Context, workspaceID uint64) (int, error)
+}
+
+// purgeTables are the workspace scoped tables in the order they are purged, each with the
+// condition selecting the rows of a workspace. Rows are removed before the rows they reference.
+var purgeTables = []struct {
+	table string
+	where string
+}{
+	{"object_acls", "workspace_id = ?"},
+	{"issue_transitions", "workspace_id = ?"},
+	{"issues", "workspace_id = ?"},
+	{"issue_counters", "workspace_id = ?"},
+	{"cycle_capacities", "cycle_id IN (SELECT id FROM cycles WHERE workspace_id = ?)"},
+	{"cycles", "workspace_id = ?"},
+	{"workspace_settings", "workspace_id = ?"},
+	{"workspace_quotas", "workspace_id = ?"},
+	{"issues_status", "workspace_id = ?"},
+	{"workspace_invitations", "workspace_id = ?"},
+	{"workspace_members", "workspace_id = ?"},
+	{"roles", "workspace_id = ?"},
+	{"casbin_rules", "id IN (SELECT cr.id FROM casbin_rules cr JOIN workspaces w ON " +
+		"(cr.ptype = 'p' AND cr.v1 = w.domain) OR (cr.ptype = 'g' AND cr.v2 = w.domain) WHERE w.id = ?)"},
 }
 
 // repository persists workspaces in database
@@ -41,6 +135,13 @@ func (r repository) Get(ctx context.Context, uuid string) (entity.Workspace, err
 	return workspace, err
 }
 
+// GetByDomain reads the workspace with the specified domain from the database.
+func (r repository) GetByDomain(ctx context.Context, domain string) (entity.Workspace, error) {
+	var workspace entity.Workspace
+	err := r.db.With(ctx).Model(&workspace).Where("domain = ?", domain).First()
+	return workspace, err
+}
+
 // Create saves a new workspace record in the database.
 // It returns the ID of the newly inserted workspace record.
 func (r repository) Create(ctx context.Context, workspace entity.Workspace) error {
@@ -54,7 +155,7 @@ func (r repository) Update(ctx context.Context, workspace entity.Workspace) erro
 	return err
 }
 
-// Delete deletes an workspace with the specified ID from the database.
+// Delete soft deletes an workspace with the specified ID in the database.
 func (r repository) Delete(ctx context.Context, uuid string) error {
 	workspace, err := r.Get(ctx, uuid)
 	if err != nil {
@@ -64,6 +165,11 @@ func (r repository) Delete(ctx context.Context, uuid string) error {
 	return err
 }
 
+// Transactional runs f in a database transaction.
+func (r repository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	return r.db.Transactional(ctx, f)
+}
+
 // Count returns the number of the workspace records in the database.
 func (r repository) Count(ctx context.Context) (int64, error) {
 	var count int
@@ -79,3 +185,259 @@ func (r repository) Query(ctx context.Context, offset, limit int64) ([]entity.Wo
 		Offset(int(offset)).SelectAndCount()
 	return _workspaces, count, err
 }
+
+// GetMember reads the membership of the user in the workspace from the database.
+func (r repository) GetMember(ctx context.Context, workspaceID, userID uint64) (entity.WorkspaceMember, error) {
+	var member entity.WorkspaceMember
+	err := r.db.With(ctx).Model(&member).
+		Relation("Workspace").
+		Relation("User").
+		Relation("Role").
+		Where("wm.workspace_id = ?", workspaceID).
+		Where("wm.user_id = ?", userID).
+		First()
+	return member, err
+}
+
+// QueryMembers retrieves the members of the workspace with the specified offset and limit from the database.
+func (r repository) QueryMembers(ctx context.Context, workspaceID uint64, offset, limit int64) ([]entity.WorkspaceMember, int, error) {
+	var members []entity.WorkspaceMember
+	count, err := r.db.With(ctx).Model(&members).
+		Relation("Workspace").
+		Relation("User").
+		Relation("Role").
+		Where("wm.workspace_id = ?", workspaceID).
+		Order("wm.id ASC").
+		Limit(int(limit)).
+		Offset(int(offset)).
+		SelectAndCount()
+	return members, count, err
+}
+
+// AddMember saves a new workspace member record in the database.
+func (r repository) AddMember(ctx context.Context, member entity.WorkspaceMember) error {
+	_, err := r.db.With(ctx).Model(&member).Insert()
+	return err
+}
+
+// RemoveMember deletes the membership of the user in the workspace from the database.
+func (r repository) RemoveMember(ctx context.Context, workspaceID uint64, userUUID string) error {
+	res, err := r.db.With(ctx).Model((*entity.WorkspaceMember)(nil)).
+		Where("workspace_id = ?", workspaceID).
+		Where("user_id = (SELECT id FROM users WHERE uuid = ?)", userUUID).
+		Delete()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pg.ErrNoRows
+	}
+	return nil
+}
+
+// IsMember checks the membership of the user with the specified UUID in the workspace in the database.
+func (r repository) IsMember(ctx context.Context, workspaceID uint64, userUUID string) (bool, error) {
+	return r.db.With(ctx).Model((*entity.WorkspaceMember)(nil)).
+		Where("workspace_id = ?", workspaceID).
+		Where("user_id = (SELECT id FROM users WHERE uuid = ?)", userUUID).
+		Exists()
+}
+
+// GetRole reads the role with the specified UUID in the workspace from the database.
+func (r repository) GetRole(ctx context.Context, workspaceID uint64, uuid string) (entity.Role, error) {
+	var role entity.Role
+	err := r.db.With(ctx).Model(&role).
+		Where("uuid = ?", uuid).
+		Where("workspace_id = ?", workspaceID).
+		First()
+	return role, err
+}
+
+// CreateInvitation inserts a new invitation record in the database.
+func (r repository) CreateInvitation(ctx context.Context, invitation entity.WorkspaceInvitation) error {
+	_, err := r.db.With(ctx).Model(&invitation).Insert()
+	return err
+}
+
+// GetInvitation reads the invitation with the specified UUID from the database.
+func (r repository) GetInvitation(ctx context.Context, uuid string) (entity.WorkspaceInvitation, error) {
+	var invitation entity.WorkspaceInvitation
+	err := r.db.With(ctx).Model(&invitation).Where("uuid = ?", uuid).First()
+	return invitation, err
+}
+
+// AcceptInvitation sets the acceptance time of the invitation if it is still open in the database.
+func (r repository) AcceptInvitation(ctx context.Context, id uint64, now time.Time) (bool, error) {
+	return r.closeInvitation(ctx, id, "accepted_at", now)
+}
+
+// RevokeInvitation sets the revocation time of the invitation if it is still open in the database.
+func (r repository) RevokeInvitation(ctx context.Context, id uint64, now time.Time) (bool, error) {
+	return r.closeInvitation(ctx, id, "revoked_at", now)
+}
+
+// closeInvitation sets the column to the time on the invitation not accepted or revoked yet,
+// a concurrent request closing it too updates no row.
+func (r repository) closeInvitation(ctx context.Context, id uint64, column string, now time.Time) (bool, error) {
+	res, err := r.db.With(ctx).Model((*entity.WorkspaceInvitation)(nil)).
+		Set(column+" = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Where("accepted_at IS NULL").
+		Where("revoked_at IS NULL").
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+// GetSettings reads the settings of the workspace from the database.
+func (r repository) GetSettings(ctx context.Context, workspaceID uint64) (entity.WorkspaceSettings, error) {
+	var settings entity.WorkspaceSettings
+	err := r.db.With(ctx).Model(&settings).
+		Relation("Workspace").
+		Relation("DefaultStatus").
+		Where("wst.workspace_id = ?", workspaceID).
+		First()
+	return settings, err
+}
+
+// SaveSettings inserts the settings record or updates it if the workspace already has one.
+func (r repository) SaveSettings(ctx context.Context, settings entity.WorkspaceSettings) error {
+	_, err := r.db.With(ctx).Model(&settings).
+		OnConflict("(workspace_id) DO UPDATE").
+		Set("default_status_id = EXCLUDED.default_status_id").
+		Set("estimate_scale = EXCLUDED.estimate_scale").
+		Set("time_zone = EXCLUDED.time_zone").
+		Set("week_start = EXCLUDED.week_start").
+		Set("issue_key_prefix = EXCLUDED.issue_key_prefix").
+		Set("updated_at = EXCLUDED.updated_at").
+		Insert()
+	return err
+}
+
+// GetStatus reads the issue status with the specified UUID in the workspace from the database.
+func (r repository) GetStatus(ctx context.Context, workspaceID uint64, uuid string) (entity.IssueStatus, error) {
+	var status entity.IssueStatus
+	err := r.db.With(ctx).Model(&status).
+		Where("uuid = ?", uuid).
+		Where("workspace_id = ?", workspaceID).
+		First()
+	return status, err
+}
+
+// GetDeleted reads the soft deleted workspace with the specified UUID from the database.
+func (r repository) GetDeleted(ctx context.Context, uuid string) (entity.Workspace, error) {
+	var workspace entity.Workspace
+	err := r.db.With(ctx).Model(&workspace).Deleted().Where("uuid = ?", uuid).First()
+	return workspace, err
+}
+
+// Restore clears the deletion time of the workspace in the database.
+func (r repository) Restore(ctx context.Context, workspaceID uint64) error {
+	_, err := r.db.With(ctx).Model((*entity.Workspace)(nil)).
+		Deleted().
+		Set("deleted_at = NULL").
+		Where("id = ?", workspaceID).
+		Update()
+	return err
+}
+
+// GetDeletion reads the deletion of the workspace with the specified UUID from the database.
+func (r repository) GetDeletion(ctx context.Context, workspaceUUID string) (entity.WorkspaceDeletion, error) {
+	var deletion entity.WorkspaceDeletion
+	err := r.db.With(ctx).Model(&deletion).Where("workspace_uuid = ?", workspaceUUID).First()
+	return deletion, err
+}
+
+// SaveDeletion inserts the deletion record or updates it if the workspace already has one.
+func (r repository) SaveDeletion(ctx context.Context, deletion entity.WorkspaceDeletion) error {
+	_, err := r.db.With(ctx).Model(&deletion).
+		OnConflict("(workspace_id) DO UPDATE").
+		Set("requested_by_id = EXCLUDED.requested_by_id").
+		Set("state = EXCLUDED.state").
+		Set("purge_after = EXCLUDED.purge_after").
+		Set("current_table = EXCLUDED.current_table").
+		Set("tables_done = EXCLUDED.tables_done").
+		Set("tables_total = EXCLUDED.tables_total").
+		Set("deleted_rows = EXCLUDED.deleted_rows").
+		Set("started_at = EXCLUDED.started_at").
+		Set("finished_at = EXCLUDED.finished_at").
+		Set("created_at = EXCLUDED.created_at").
+		Set("updated_at = EXCLUDED.updated_at").
+		Insert()
+	return err
+}
+
+// DueDeletions reads the deletions to purge from the database, interrupted purges first.
+func (r repository) DueDeletions(ctx context.Context, now time.Time) ([]entity.WorkspaceDeletion, error) {
+	var deletions []entity.WorkspaceDeletion
+	err := r.db.With(ctx).Model(&deletions).
+		WhereIn("state IN (?)", []string{entity.DeletionStateScheduled, entity.DeletionStatePurging}).
+		Where("purge_after <= ?", now).
+		OrderExpr("state = ? DESC, purge_after", entity.DeletionStatePurging).
+		Select()
+	return deletions, err
+}
+
+// PurgeBatch deletes a batch of the rows of the workspace from the table.
+func (r repository) PurgeBatch(ctx context.Context, table string, workspaceID uint64, size int) (int, error) {
+	for _, t := range purgeTables {
+		if t.table != table {
+			continue
+		}
+		res, err := r.db.With(ctx).Exec(
+			fmt.Sprintf("DELETE FROM %[1]s WHERE id IN (SELECT id FROM %[1]s WHERE %[2]s LIMIT ?)", t.table, t.where),
+			workspaceID, size)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected(), nil
+	}
+	return 0, fmt.Errorf("unknown workspace table %q", table)
+}
+
+// Purge deletes the soft deleted workspace row from the database.
+func (r repository) Purge(ctx context.Context, workspaceID uint64) error {
+	_, err := r.db.With(ctx).Model((*entity.Workspace)(nil)).
+		Where("id = ?", workspaceID).
+		AllWithDeleted().
+		ForceDelete()
+	return err
+}
+
+// GetQuota reads the quotas of the workspace from the database.
+func (r repository) GetQuota(ctx context.Context, workspaceID uint64) (entity.WorkspaceQuota, error) {
+	var quota entity.WorkspaceQuota
+	err := r.db.With(ctx).Model(&quota).Where("workspace_id = ?", workspaceID).First()
+	return quota, err
+}
+
+// SaveQuota inserts the quota record or updates it if the workspace already has one.
+func (r repository) SaveQuota(ctx context.Context, quota entity.WorkspaceQuota) error {
+	_, err := r.db.With(ctx).Model(&quota).
+		OnConflict("(workspace_id) DO UPDATE").
+		Set("max_members = EXCLUDED.max_members").
+		Set("max_issues = EXCLUDED.max_issues").
+		Set("max_storage_bytes = EXCLUDED.max_storage_bytes").
+		Set("updated_at = EXCLUDED.updated_at").
+		Insert()
+	return err
+}
+
+// LockWorkspace locks the workspace row for update in the transaction of the context.
+func (r repository) LockWorkspace(ctx context.Context, workspaceID uint64) error {
+	_, err := r.db.With(ctx).Exec("SELECT id FROM workspaces WHERE id = ? FOR UPDATE", workspaceID)
+	return err
+}
+
+// CountMembers returns the number of the member records of the workspace in the database.
+func (r repository) CountMembers(ctx context.Context, workspaceID uint64) (int, error) {
+	return r.db.With(ctx).Model((*entity.WorkspaceMember)(nil)).Where("workspace_id = ?", workspaceID).Count()
+}
+
+// CountIssues returns the number of the issue records of the workspace in the database.
+func (r repository) CountIssues(ctx context.Context, workspaceID uint64) (int, error) {
+	return r.db.With(ctx).Model((*entity.Issue)(nil)).Where("workspace_id = ?", workspaceID).Count()
+}
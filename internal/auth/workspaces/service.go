@@ -2,29 +2,89 @@ package workspaces
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
 	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
 	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
 )
 
 // Service encapsulates use case logic for workspaces.
 type Service interface {
 	Get(ctx context.Context, uuid string) (*workspacesProto.Workspace, error)
+	GetByDomain(ctx context.Context, domain string) (*workspacesProto.Workspace, error)
 	Query(ctx context.Context, offset, limit int64) (*workspacesProto.ListWorkspacesResponse, error)
 	Count(ctx context.Context) (int64, error)
 	Create(ctx context.Context, input *workspacesProto.CreateWorkspaceRequest) (*workspacesProto.Workspace, error)
 	Update(ctx context.Context, input *workspacesProto.UpdateWorkspaceRequest) (*workspacesProto.Workspace, error)
 	Delete(ctx context.Context, uuid string) (*workspacesProto.Workspace, error)
+
+	// Restore undoes the deletion of a workspace within the grace period
+	Restore(ctx context.Context, uuid string) (*workspacesProto.Workspace, error)
+	// GetDeletion returns the deletion progress of the workspace with the specified UUID
+	GetDeletion(ctx context.Context, uuid string) (*workspacesProto.WorkspaceDeletion, error)
+	// PurgeDue removes the rows of the deleted workspaces whose grace period ended
+	PurgeDue(ctx context.Context) error
+
+	// Invite records an invitation to the workspace and emails its token to the given email
+	Invite(ctx context.Context, input *workspacesProto.InviteWorkspaceMemberRequest) (*workspacesProto.InviteWorkspaceMemberResponse, error)
+	// AcceptInvitation adds the current user to the workspace of the invitation
+	AcceptInvitation(ctx context.Context, token string) (*workspacesProto.WorkspaceMember, error)
+	// RevokeInvitation revokes the invitation to the workspace which was not accepted yet
+	RevokeInvitation(ctx context.Context, workspaceUUID, uuid string) error
+	// QueryMembers returns the members of the workspace with the specified offset and limit
+	QueryMembers(ctx context.Context, workspaceUUID string, offset, limit int64) (*workspacesProto.ListWorkspaceMembersResponse, error)
+	// RemoveMember removes the user from the members of the workspace
+	RemoveMember(ctx context.Context, workspaceUUID, userUUID string) error
+	// IsMember returns whether the user with the specified UUID is a member of the workspace of the request
+	IsMember(ctx context.Context, userUUID string) (bool, error)
+	// HasRole returns whether the role with the specified UUID belongs to the workspace of the request
+	HasRole(ctx context.Context, roleUUID string) (bool, error)
+
+	// GetSettings returns the settings of the workspace with the specified UUID
+	GetSettings(ctx context.Context, workspaceUUID string) (*workspacesProto.WorkspaceSettings, error)
+	// UpdateSettings updates the settings of a workspace
+	UpdateSettings(ctx context.Context, input *workspacesProto.UpdateWorkspaceSettingsRequest) (*workspacesProto.WorkspaceSettings, error)
+	// CurrentSettings returns the settings of the workspace of the request
+	CurrentSettings(ctx context.Context) (*workspacesProto.WorkspaceSettings, error)
+
+	// GetUsage returns the usage and the quotas of the workspace with the specified UUID
+	GetUsage(ctx context.Context, workspaceUUID string) (*workspacesProto.WorkspaceUsage, error)
+	// UpdateQuotas sets the quotas of a workspace
+	UpdateQuotas(ctx context.Context, input *workspacesProto.UpdateWorkspaceQuotasRequest) (*workspacesProto.WorkspaceQuotas, error)
+	// CheckQuota returns ErrQuotaExceeded if adding n of the resource takes the workspace of the request over its limit,
+	// it locks the workspace until the end of the transaction adding the resource
+	CheckQuota(ctx context.Context, resource string, n int64) error
 }
 
+// MembershipHook is notified of the workspaces created and of the members joining and leaving them
+type MembershipHook interface {
+	WorkspaceCreated(ctx context.Context, workspace *workspacesProto.Workspace, owner *usersProto.User) error
+	// MemberInvited returns an error if the inviter cannot invite members with the role, or the default role if it is empty
+	MemberInvited(ctx context.Context, workspace *workspacesProto.Workspace, inviter *usersProto.User, roleUUID string) error
+	MemberJoined(ctx context.Context, workspace *workspacesProto.Workspace, user *usersProto.User, roleUUID string) error
+	MemberRemoved(ctx context.Context, workspace *workspacesProto.Workspace, userUUID string) error
+}
+
+var (
+	errAlreadyMember  = errors.New("user is already a member of the workspace")
+	errOtherWorkspace = errors.New("the request is not authorized in the workspace")
+	errDomainChange   = errors.New("the domain of a workspace cannot be changed")
+)
+
 // ValidateCreateRequest validates the CreateWorkspaceRequest fields.
 func ValidateCreateRequest(c *workspacesProto.CreateWorkspaceRequest) error {
 	return validation.ValidateStruct(c,
 		validation.Field(&c.Title, validation.Required, validation.Length(0, 128)),
-		validation.Field(&c.Domain, validation.Required, validation.Length(0, 128)),
+		validation.Field(&c.Domain, validation.Required, validation.Length(0, 128), is.DNSName),
 	)
 }
 
@@ -32,17 +92,27 @@ func ValidateCreateRequest(c *workspacesProto.CreateWorkspaceRequest) error {
 func ValidateUpdateRequest(u *workspacesProto.UpdateWorkspaceRequest) error {
 	return validation.ValidateStruct(u,
 		validation.Field(&u.Title, validation.Required, validation.Length(0, 128)),
-		validation.Field(&u.Domain, validation.Required, validation.Length(0, 128)),
+		validation.Field(&u.Domain, validation.Length(0, 128), is.DNSName),
+	)
+}
+
+// ValidateInviteRequest validates the InviteWorkspaceMemberRequest fields.
+func ValidateInviteRequest(i *workspacesProto.InviteWorkspaceMemberRequest) error {
+	return validation.ValidateStruct(i,
+		validation.Field(&i.WorkspaceUuid, validation.Required, is.UUID),
+		validation.Field(&i.Email, validation.Required, is.EmailFormat, validation.Length(0, 256)),
+		validation.Field(&i.RoleUuid, is.UUID),
 	)
 }
 
 type service struct {
-	repo Repository
+	repo  Repository
+	hooks []MembershipHook
 }
 
-// NewService creates a new workspace service.
-func NewService(repo Repository) Service {
-	return service{repo}
+// NewService creates a new workspace service notifying the hooks of the membership changes.
+func NewService(repo Repository, hooks ...MembershipHook) Service {
+	return service{repo, hooks}
 }
 
 // Get returns the workspace with the specified the workspace UUID.
@@ -54,6 +124,15 @@ func (s service) Get(ctx context.Context, UUID string) (*workspacesProto.Workspa
 	return workspace.ToProto(), nil
 }
 
+// GetByDomain returns the workspace with the specified domain.
+func (s service) GetByDomain(ctx context.Context, domain string) (*workspacesProto.Workspace, error) {
+	workspace, err := s.repo.GetByDomain(ctx, strings.ToLower(domain))
+	if err != nil {
+		return nil, err
+	}
+	return workspace.ToProto(), nil
+}
+
 // Create creates a new workspace.
 func (s service) Create(ctx context.Context, req *workspacesProto.CreateWorkspaceRequest) (*workspacesProto.Workspace, error) {
 	if err := ValidateCreateRequest(req); err != nil {
@@ -64,17 +143,41 @@ func (s service) Create(ctx context.Context, req *workspacesProto.CreateWorkspac
 	err := s.repo.Create(ctx, entity.Workspace{
 		UUID:      id,
 		Title:     req.Title,
-		Domain:    req.Domain,
+		Domain:    strings.ToLower(req.Domain),
 		CreatedAt: now,
 		UpdatedAt: now,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return s.Get(ctx, id)
+	workspace, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the user creating the workspace is its first member
+	user, err := auth.ExtractUser(ctx)
+	if err == nil {
+		err = s.repo.AddMember(ctx, entity.WorkspaceMember{
+			WorkspaceID: workspace.Id,
+			UserID:      user.Id,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, hook := range s.hooks {
+		if err := hook.WorkspaceCreated(ctx, workspace, user); err != nil {
+			return nil, err
+		}
+	}
+	return workspace, nil
 }
 
 // Update updates the workspace with the specified UUID.
+// The domain cannot be changed as the policies of the workspace are bound to it.
 func (s service) Update(ctx context.Context, req *workspacesProto.UpdateWorkspaceRequest) (*workspacesProto.Workspace, error) {
 	if err := ValidateUpdateRequest(req); err != nil {
 		return nil, err
@@ -84,16 +187,18 @@ func (s service) Update(ctx context.Context, req *workspacesProto.UpdateWorkspac
 	if err != nil {
 		return nil, err
 	}
+	if req.Domain != "" && !strings.EqualFold(req.Domain, workspace.Domain) {
+		return nil, errDomainChange
+	}
 	now := time.Now()
 	workspace.Title = req.Title
-	workspace.Domain = req.Domain
 	workspace.UpdatedAt = now
 
 	workspaceModel := entity.Workspace{
 		ID:        workspace.ID,
 		UUID:      workspace.UUID,
 		Title:     req.Title,
-		Domain:    req.Domain,
+		Domain:    workspace.Domain,
 		CreatedAt: workspace.CreatedAt,
 		UpdatedAt: now,
 	}
@@ -104,18 +209,6 @@ func (s service) Update(ctx context.Context, req *workspacesProto.UpdateWorkspac
 	return workspace.ToProto(), nil
 }
 
-// Delete deletes the workspace with the specified UUID.
-func (s service) Delete(ctx context.Context, UUID string) (*workspacesProto.Workspace, error) {
-	workspace, err := s.Get(ctx, UUID)
-	if err != nil {
-		return nil, err
-	}
-	if err = s.repo.Delete(ctx, UUID); err != nil {
-		return nil, err
-	}
-	return workspace, nil
-}
-
 // Count returns the number of workspaces.
 func (s service) Count(ctx context.Context) (int64, error) {
 	return s.repo.Count(ctx)
@@ -134,3 +227,208 @@ func (s service) Query(ctx context.Context, offset, limit int64) (*workspacesPro
 		Limit:      limit,
 	}, nil
 }
+
+// Invite records an invitation to the workspace for the given email and sends its token to the email.
+// The membership hooks reject the roles the current user cannot grant.
+func (s service) Invite(ctx context.Context, req *workspacesProto.InviteWorkspaceMemberRequest) (*workspacesProto.InviteWorkspaceMemberResponse, error) {
+	if err := ValidateInviteRequest(req); err != nil {
+		return nil, err
+	}
+	inviter, err := auth.ExtractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := s.repo.Get(ctx, req.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoleUuid != "" {
+		if _, err := s.repo.GetRole(ctx, workspace.ID, req.RoleUuid); err != nil {
+			return nil, err
+		}
+	}
+	for _, hook := range s.hooks {
+		if err := hook.MemberInvited(ctx, workspace.ToProto(), inviter, req.RoleUuid); err != nil {
+			return nil, err
+		}
+	}
+
+	inv := NewInvitation(workspace.ID, inviter.Id, req.Email, req.RoleUuid, time.Now())
+	if err := s.repo.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+	if err := SendInvitation(ctx, workspace.ToProto(), inv); err != nil {
+		return nil, err
+	}
+	e, _ := ptypes.TimestampProto(inv.ExpiresAt)
+	return &workspacesProto.InviteWorkspaceMemberResponse{
+		Uuid:      inv.UUID,
+		Email:     inv.Email,
+		ExpiresAt: e,
+	}, nil
+}
+
+// AcceptInvitation adds the current user to the workspace of the invitation and closes the invitation.
+// The invitation must have been sent to the email of the current user, who must have verified it.
+func (s service) AcceptInvitation(ctx context.Context, token string) (*workspacesProto.WorkspaceMember, error) {
+	user, err := auth.ExtractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := parseInvitation(token)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := s.repo.GetInvitation(ctx, claims.Id)
+	if err == pg.ErrNoRows {
+		return nil, errInvalidInvitation
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !inv.Pending(now) || !strings.EqualFold(inv.Email, user.Email) {
+		return nil, errInvalidInvitation
+	}
+	if !user.EmailVerified {
+		return nil, errUnverifiedEmail
+	}
+
+	workspace, err := s.repo.Get(ctx, claims.WorkspaceUUID)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.ID != inv.WorkspaceID {
+		return nil, errInvalidInvitation
+	}
+	if _, err := s.repo.GetMember(ctx, workspace.ID, user.Id); err == nil {
+		return nil, errAlreadyMember
+	}
+
+	member := entity.WorkspaceMember{
+		WorkspaceID: workspace.ID,
+		UserID:      user.Id,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if inv.RoleUUID != "" {
+		role, err := s.repo.GetRole(ctx, workspace.ID, inv.RoleUUID)
+		if err != nil {
+			return nil, err
+		}
+		member.RoleID = role.ID
+	}
+	err = s.repo.Transactional(ctx, func(ctx context.Context) error {
+		if err := s.checkQuota(ctx, workspace.ID, QuotaMembers, 1); err != nil {
+			return err
+		}
+		// the invitation is accepted once, even by concurrent requests
+		accepted, err := s.repo.AcceptInvitation(ctx, inv.ID, now)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return errInvalidInvitation
+		}
+		if err := s.repo.AddMember(ctx, member); err != nil {
+			return err
+		}
+		for _, hook := range s.hooks {
+			if err := hook.MemberJoined(ctx, workspace.ToProto(), user, inv.RoleUUID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	member, err = s.repo.GetMember(ctx, workspace.ID, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	return member.ToProto(true), nil
+}
+
+// RevokeInvitation revokes the invitation with the specified UUID to the workspace, its token cannot be accepted anymore.
+func (s service) RevokeInvitation(ctx context.Context, workspaceUUID, uuid string) error {
+	workspace, err := s.repo.Get(ctx, workspaceUUID)
+	if err != nil {
+		return err
+	}
+	inv, err := s.repo.GetInvitation(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if inv.WorkspaceID != workspace.ID {
+		return pg.ErrNoRows
+	}
+	revoked, err := s.repo.RevokeInvitation(ctx, inv.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errInvitationClosed
+	}
+	return nil
+}
+
+// QueryMembers returns the members of the workspace with the specified offset and limit.
+func (s service) QueryMembers(ctx context.Context, workspaceUUID string, offset, limit int64) (*workspacesProto.ListWorkspaceMembersResponse, error) {
+	workspace, err := s.repo.Get(ctx, workspaceUUID)
+	if err != nil {
+		return nil, err
+	}
+	items, count, err := s.repo.QueryMembers(ctx, workspace.ID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &workspacesProto.ListWorkspaceMembersResponse{
+		Members:    entity.WorkspaceMemberToProtoList(items, true),
+		TotalCount: int64(count),
+		Offset:     offset,
+		Limit:      limit,
+	}, nil
+}
+
+// RemoveMember removes the user with the specified UUID from the members of the workspace.
+func (s service) RemoveMember(ctx context.Context, workspaceUUID, userUUID string) error {
+	workspace, err := s.repo.Get(ctx, workspaceUUID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.RemoveMember(ctx, workspace.ID, userUUID); err != nil {
+		return err
+	}
+	for _, hook := range s.hooks {
+		if err := hook.MemberRemoved(ctx, workspace.ToProto(), userUUID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsMember returns whether the user with the specified UUID is a member of the workspace of the request.
+func (s service) IsMember(ctx context.Context, userUUID string) (bool, error) {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.repo.IsMember(ctx, workspace.Id, userUUID)
+}
+
+// HasRole returns whether the role with the specified UUID belongs to the workspace of the request.
+func (s service) HasRole(ctx context.Context, roleUUID string) (bool, error) {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return false, err
+	}
+	if _, err := s.repo.GetRole(ctx, workspace.Id, roleUUID); err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -3,16 +3,19 @@ package workspaces
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
 	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/mail"
 	"github.com/mirzakhany/pm/protobuf/workspaces"
 	"github.com/stretchr/testify/assert"
 )
 
-var errCRUD = errors.New("error crud")
-
 func TestCreateWorkspaceRequest_Validate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -88,6 +91,11 @@ func Test_service_CRUD(t *testing.T) {
 	assert.Equal(t, "test updated", workspace.Title)
 	_, err = s.Update(ctx, &workspaces.UpdateWorkspaceRequest{Title: "test updated", Uuid: "none", Domain: "example"})
 	assert.NotNil(t, err)
+	// the domain is optional and cannot change
+	_, err = s.Update(ctx, &workspaces.UpdateWorkspaceRequest{Title: "test updated", Uuid: id})
+	assert.Nil(t, err)
+	_, err = s.Update(ctx, &workspaces.UpdateWorkspaceRequest{Title: "test updated", Uuid: id, Domain: "other"})
+	assert.Equal(t, errDomainChange, err)
 
 	// validation error in update
 	_, err = s.Update(ctx, &workspaces.UpdateWorkspaceRequest{Title: "", Uuid: id, Domain: "example"})
@@ -123,55 +131,336 @@ func Test_service_CRUD(t *testing.T) {
 	assert.Equal(t, int64(1), count)
 }
 
-type mockRepository struct {
-	items []entity.Workspace
+// invitationToken returns the token of the last invitation emailed to the address.
+func invitationToken(t *testing.T, mails *mail.MockSender, to string) string {
+	msg, ok := mails.Last(to)
+	assert.True(t, ok)
+	const prefix = "accept the invitation: "
+	body := msg.Body[strings.Index(msg.Body, prefix)+len(prefix):]
+	return body[:strings.Index(body, "\n")]
 }
 
-func (m mockRepository) Get(ctx context.Context, id string) (entity.Workspace, error) {
-	for _, item := range m.items {
-		if item.UUID == id {
-			return item, nil
-		}
+func Test_service_Members(t *testing.T) {
+	repo := &mockRepository{
+		users: []entity.User{
+			{ID: 1, UUID: "u1", Username: "owner", Email: "owner@example.com"},
+			{ID: 2, UUID: "u2", Username: "guest", Email: "guest@example.com", EmailVerified: true},
+		},
 	}
-	return entity.Workspace{}, pg.ErrNoRows
-}
+	defer MockInvitationsForTest()()
+	mails := mail.InitMock()
+	hook := &mockHook{}
+	s := NewService(repo, hook)
+	owner := repo.users[0].ToProto(false)
+	guest := repo.users[1].ToProto(false)
+	ctx := auth.ContextWithUser(context.Background(), owner)
 
-func (m mockRepository) Count(ctx context.Context) (int64, error) {
-	return int64(len(m.items)), nil
-}
+	// the creator becomes a member
+	workspace, err := s.Create(ctx, &workspaces.CreateWorkspaceRequest{Title: "acme", Domain: "acme"})
+	assert.Nil(t, err)
+	members, err := s.QueryMembers(ctx, workspace.Uuid, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), members.TotalCount)
+	assert.Equal(t, "u1", members.Members[0].User.Uuid)
+
+	repo.roles = append(repo.roles, entity.Role{ID: 1, UUID: uuid.New().String(), Title: "member", WorkspaceID: workspace.Id})
+
+	// invitation validation
+	_, err = s.Invite(ctx, &workspaces.InviteWorkspaceMemberRequest{WorkspaceUuid: workspace.Uuid, Email: "not-an-email"})
+	assert.NotNil(t, err)
+	_, err = s.Invite(ctx, &workspaces.InviteWorkspaceMemberRequest{
+		WorkspaceUuid: workspace.Uuid, Email: "guest@example.com", RoleUuid: uuid.New().String(),
+	})
+	assert.NotNil(t, err)
+
+	// roles the inviter cannot grant
+	hook.notGrantable = repo.roles[0].UUID
+	_, err = s.Invite(ctx, &workspaces.InviteWorkspaceMemberRequest{
+		WorkspaceUuid: workspace.Uuid, Email: "guest@example.com", RoleUuid: repo.roles[0].UUID,
+	})
+	assert.Equal(t, ErrRoleNotGrantable, err)
+	hook.notGrantable = ""
 
-func (m mockRepository) Query(ctx context.Context, offset, limit int64) ([]entity.Workspace, int, error) {
-	return m.items, len(m.items), nil
+	invitation, err := s.Invite(ctx, &workspaces.InviteWorkspaceMemberRequest{
+		WorkspaceUuid: workspace.Uuid, Email: "Guest@Example.com", RoleUuid: repo.roles[0].UUID,
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, invitation.Uuid)
+	assert.Equal(t, "guest@example.com", invitation.Email)
+	// the token is only sent to the email
+	token := invitationToken(t, mails, "guest@example.com")
+
+	// only the invited user can accept
+	guestCtx := auth.ContextWithUser(context.Background(), guest)
+	_, err = s.AcceptInvitation(ctx, token)
+	assert.Equal(t, errInvalidInvitation, err)
+	_, err = s.AcceptInvitation(guestCtx, "invalid")
+	assert.Equal(t, errInvalidInvitation, err)
+
+	// the invited user must have verified their email
+	unverified := repo.users[1].ToProto(false)
+	unverified.EmailVerified = false
+	_, err = s.AcceptInvitation(auth.ContextWithUser(context.Background(), unverified), token)
+	assert.Equal(t, errUnverifiedEmail, err)
+
+	member, err := s.AcceptInvitation(guestCtx, token)
+	assert.Nil(t, err)
+	assert.Equal(t, "u2", member.User.Uuid)
+	assert.Equal(t, "member", member.Role)
+
+	// an invitation is accepted once
+	_, err = s.AcceptInvitation(guestCtx, token)
+	assert.Equal(t, errInvalidInvitation, err)
+	_, err = s.Invite(ctx, &workspaces.InviteWorkspaceMemberRequest{WorkspaceUuid: workspace.Uuid, Email: "guest@example.com"})
+	assert.Nil(t, err)
+	_, err = s.AcceptInvitation(guestCtx, invitationToken(t, mails, "guest@example.com"))
+	assert.Equal(t, errAlreadyMember, err)
+
+	// revoked invitation
+	invitation, err = s.Invite(ctx, &workspaces.InviteWorkspaceMemberRequest{WorkspaceUuid: workspace.Uuid, Email: "guest@example.com"})
+	assert.Nil(t, err)
+	token = invitationToken(t, mails, "guest@example.com")
+	assert.NotNil(t, s.RevokeInvitation(ctx, workspace.Uuid, uuid.New().String()))
+	assert.Nil(t, s.RevokeInvitation(ctx, workspace.Uuid, invitation.Uuid))
+	assert.Equal(t, errInvitationClosed, s.RevokeInvitation(ctx, workspace.Uuid, invitation.Uuid))
+	_, err = s.AcceptInvitation(guestCtx, token)
+	assert.Equal(t, errInvalidInvitation, err)
+
+	// expired invitation
+	expired := NewInvitation(workspace.Id, 1, "guest@example.com", "", time.Now().Add(-time.Hour*time.Duration(invitationLife.Int()+1)))
+	assert.Nil(t, repo.CreateInvitation(ctx, expired))
+	token, err = signInvitation(workspace.Uuid, expired)
+	assert.Nil(t, err)
+	_, err = parseInvitation(token)
+	assert.Equal(t, errInvalidInvitation, err)
+
+	// invitations cannot be signed nor accepted without a secret
+	invitationSecret = config.RegisterStringMock("workspaces.invitationSecret", "")
+	_, err = signInvitation(workspace.Uuid, expired)
+	assert.Equal(t, errNoInvitationSecret, err)
+	_, err = parseInvitation(token)
+	assert.Equal(t, errNoInvitationSecret, err)
+
+	// remove
+	err = s.RemoveMember(ctx, workspace.Uuid, "u2")
+	assert.Nil(t, err)
+	err = s.RemoveMember(ctx, workspace.Uuid, "u2")
+	assert.NotNil(t, err)
+	members, _ = s.QueryMembers(ctx, workspace.Uuid, 0, 10)
+	assert.Equal(t, int64(1), members.TotalCount)
+
+	// the hooks are notified of the membership changes
+	assert.Equal(t, []string{
+		"created acme u1",
+		"invited acme u1 " + repo.roles[0].UUID,
+		"joined acme u2 " + repo.roles[0].UUID,
+		"invited acme u1 ",
+		"invited acme u1 ",
+		"removed acme u2",
+	}, hook.events)
 }
 
-func (m *mockRepository) Create(ctx context.Context, workspace entity.Workspace) error {
-	if workspace.Title == "error" {
-		return errCRUD
+func TestUpdateWorkspaceSettingsRequest_Validate(t *testing.T) {
+	Uuid := uuid.New().String()
+	valid := func() workspaces.UpdateWorkspaceSettingsRequest {
+		return workspaces.UpdateWorkspaceSettingsRequest{
+			WorkspaceUuid:  Uuid,
+			EstimateScale:  "points",
+			TimeZone:       "Europe/Berlin",
+			WeekStart:      "monday",
+			IssueKeyPrefix: "ACME",
+		}
+	}
+	tests := []struct {
+		name      string
+		update    func(r *workspaces.UpdateWorkspaceSettingsRequest)
+		wantError bool
+	}{
+		{"success", func(r *workspaces.UpdateWorkspaceSettingsRequest) {}, false},
+		{"default status", func(r *workspaces.UpdateWorkspaceSettingsRequest) { r.DefaultStatusUuid = Uuid }, false},
+		{"invalid default status", func(r *workspaces.UpdateWorkspaceSettingsRequest) { r.DefaultStatusUuid = "todo" }, true},
+		{"invalid scale", func(r *workspaces.UpdateWorkspaceSettingsRequest) { r.EstimateScale = "days" }, true},
+		{"invalid time zone", func(r *workspaces.UpdateWorkspaceSettingsRequest) { r.TimeZone = "Mars/Olympus" }, true},
+		{"invalid week start", func(r *workspaces.UpdateWorkspaceSettingsRequest) { r.WeekStart = "Monday" }, true},
+		{"lower case prefix", func(r *workspaces.UpdateWorkspaceSettingsRequest) { r.IssueKeyPrefix = "acme" }, true},
+		{"too long prefix", func(r *workspaces.UpdateWorkspaceSettingsRequest) { r.IssueKeyPrefix = "ABCDEFGHIJK" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.update(&req)
+			err := ValidateUpdateSettingsRequest(&req)
+			assert.Equal(t, tt.wantError, err != nil)
+		})
 	}
-	m.items = append(m.items, workspace)
-	return nil
 }
 
-func (m *mockRepository) Update(ctx context.Context, workspace entity.Workspace) error {
-	if workspace.Title == "error" {
-		return errCRUD
-	}
-	for i, item := range m.items {
-		if item.UUID == workspace.UUID {
-			m.items[i] = workspace
-			break
-		}
+func Test_service_Settings(t *testing.T) {
+	repo := &mockRepository{}
+	s := NewService(repo)
+	ctx := context.Background()
+
+	workspace, err := s.Create(ctx, &workspaces.CreateWorkspaceRequest{Title: "acme", Domain: "acme"})
+	assert.Nil(t, err)
+	repo.statuses = append(repo.statuses, entity.IssueStatus{ID: 1, UUID: uuid.New().String(), Title: "todo", WorkspaceID: workspace.Id})
+
+	// defaults
+	settings, err := s.GetSettings(ctx, workspace.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, workspace.Uuid, settings.WorkspaceUuid)
+	assert.Equal(t, "points", settings.EstimateScale)
+	assert.Equal(t, "UTC", settings.TimeZone)
+	assert.Equal(t, "ISS", settings.IssueKeyPrefix)
+
+	// the current workspace is taken from the context
+	_, err = s.CurrentSettings(ctx)
+	assert.Equal(t, auth.ErrNoWorkspace, err)
+	settings, err = s.CurrentSettings(auth.ContextWithWorkspace(ctx, workspace))
+	assert.Nil(t, err)
+	assert.Equal(t, "ISS", settings.IssueKeyPrefix)
+
+	// unknown default status
+	_, err = s.UpdateSettings(ctx, &workspaces.UpdateWorkspaceSettingsRequest{
+		WorkspaceUuid: workspace.Uuid, DefaultStatusUuid: uuid.New().String(),
+		EstimateScale: "hours", TimeZone: "Asia/Tehran", WeekStart: "saturday", IssueKeyPrefix: "ACME",
+	})
+	assert.NotNil(t, err)
+
+	settings, err = s.UpdateSettings(ctx, &workspaces.UpdateWorkspaceSettingsRequest{
+		WorkspaceUuid: workspace.Uuid, DefaultStatusUuid: repo.statuses[0].UUID,
+		EstimateScale: "hours", TimeZone: "Asia/Tehran", WeekStart: "saturday", IssueKeyPrefix: "ACME",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, repo.statuses[0].UUID, settings.DefaultStatusUuid)
+	assert.Equal(t, "hours", settings.EstimateScale)
+	assert.Equal(t, "Asia/Tehran", settings.TimeZone)
+	assert.Equal(t, "saturday", settings.WeekStart)
+
+	settings, err = s.CurrentSettings(auth.ContextWithWorkspace(ctx, workspace))
+	assert.Nil(t, err)
+	assert.Equal(t, "ACME", settings.IssueKeyPrefix)
+}
+
+func Test_service_Deletion(t *testing.T) {
+	repo := &mockRepository{
+		users: []entity.User{
+			{ID: 1, UUID: "u1", Username: "owner", Email: "owner@example.com"},
+			{ID: 2, UUID: "u2", Username: "other", Email: "other@example.com"},
+		},
+		rows: make(map[uint64]map[string]int),
 	}
-	return nil
+	s := NewService(repo)
+	ctx := auth.ContextWithUser(context.Background(), repo.users[0].ToProto(false))
+	otherCtx := auth.ContextWithUser(context.Background(), repo.users[1].ToProto(false))
+
+	workspace, err := s.Create(ctx, &workspaces.CreateWorkspaceRequest{Title: "test", Domain: "test"})
+	assert.Nil(t, err)
+	other, err := s.Create(ctx, &workspaces.CreateWorkspaceRequest{Title: "other", Domain: "other"})
+	assert.Nil(t, err)
+
+	// restore a deleted workspace within the grace period
+	_, err = s.Delete(ctx, workspace.Uuid)
+	assert.Nil(t, err)
+	_, err = s.Get(ctx, workspace.Uuid)
+	assert.NotNil(t, err)
+	deletion, err := s.GetDeletion(ctx, workspace.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.DeletionStateScheduled, deletion.State)
+	assert.Equal(t, int64(len(purgeTables)), deletion.TablesTotal)
+	assert.True(t, deletion.PurgeAfter.AsTime().After(time.Now()))
+
+	// only the user who deleted the workspace sees and undoes its deletion
+	_, err = s.GetDeletion(otherCtx, workspace.Uuid)
+	assert.Equal(t, errNotRequester, err)
+	_, err = s.Restore(otherCtx, workspace.Uuid)
+	assert.Equal(t, errNotRequester, err)
+
+	// nothing is purged before the grace period ends
+	assert.Nil(t, s.PurgeDue(ctx))
+	restored, err := s.Restore(ctx, workspace.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, workspace.Uuid, restored.Uuid)
+	deletion, _ = s.GetDeletion(ctx, workspace.Uuid)
+	assert.Equal(t, entity.DeletionStateRestored, deletion.State)
+	_, err = s.Restore(ctx, workspace.Uuid)
+	assert.NotNil(t, err)
+
+	// purge after the grace period, the rows of other workspaces are kept
+	repo.rows[workspace.Id] = map[string]int{"issues": purgeBatchSize.Int()*2 + 3, "cycles": 2, "workspace_members": 1}
+	repo.rows[other.Id] = map[string]int{"issues": 5}
+	_, err = s.Delete(ctx, workspace.Uuid)
+	assert.Nil(t, err)
+	repo.deletions[0].PurgeAfter = time.Now().Add(-time.Minute)
+	assert.Nil(t, s.PurgeDue(ctx))
+
+	deletion, err = s.GetDeletion(ctx, workspace.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.DeletionStateCompleted, deletion.State)
+	assert.Equal(t, int64(purgeBatchSize.Int()*2+6), deletion.DeletedRows)
+	assert.Equal(t, deletion.TablesTotal, deletion.TablesDone)
+	assert.NotNil(t, deletion.FinishedAt)
+	assert.Equal(t, 0, repo.rows[workspace.Id]["issues"])
+	assert.Equal(t, 5, repo.rows[other.Id]["issues"])
+	_, err = repo.GetDeleted(ctx, workspace.Uuid)
+	assert.NotNil(t, err)
+
+	// a purged workspace cannot be restored
+	_, err = s.Restore(ctx, workspace.Uuid)
+	assert.NotNil(t, err)
 }
 
-func (m *mockRepository) Delete(ctx context.Context, id string) error {
-	for i, item := range m.items {
-		if item.UUID == id {
-			m.items[i] = m.items[len(m.items)-1]
-			m.items = m.items[:len(m.items)-1]
-			break
-		}
+func Test_service_Quotas(t *testing.T) {
+	repo := &mockRepository{
+		users: []entity.User{
+			{ID: 1, UUID: "u1", Username: "owner", Email: "owner@example.com"},
+			{ID: 2, UUID: "u2", Username: "guest", Email: "guest@example.com", EmailVerified: true},
+		},
+		issues: make(map[uint64]int),
 	}
-	return nil
+	defer MockInvitationsForTest()()
+	mails := mail.InitMock()
+	s := NewService(repo)
+	ctx := auth.ContextWithUser(context.Background(), repo.users[0].ToProto(false))
+
+	workspace, err := s.Create(ctx, &workspaces.CreateWorkspaceRequest{Title: "acme", Domain: "acme"})
+	assert.Nil(t, err)
+	repo.issues[workspace.Id] = 3
+	ctx = auth.ContextWithWorkspace(ctx, workspace)
+
+	// unlimited by default
+	usage, err := s.GetUsage(ctx, workspace.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), usage.Members)
+	assert.Equal(t, int64(3), usage.Issues)
+	assert.Equal(t, int64(0), usage.Quotas.MaxIssues)
+	assert.Nil(t, s.CheckQuota(ctx, QuotaIssues, 1))
+
+	// validation error
+	_, err = s.UpdateQuotas(ctx, &workspaces.UpdateWorkspaceQuotasRequest{WorkspaceUuid: workspace.Uuid, MaxIssues: -1})
+	assert.NotNil(t, err)
+
+	quotas, err := s.UpdateQuotas(ctx, &workspaces.UpdateWorkspaceQuotasRequest{WorkspaceUuid: workspace.Uuid, MaxMembers: 1, MaxIssues: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), quotas.MaxIssues)
+	usage, _ = s.GetUsage(ctx, workspace.Uuid)
+	assert.Equal(t, int64(1), usage.Quotas.MaxMembers)
+
+	assert.Nil(t, s.CheckQuota(ctx, QuotaIssues, 1))
+	assert.True(t, errors.Is(s.CheckQuota(ctx, QuotaIssues, 2), ErrQuotaExceeded))
+	// the workspace is locked until the transaction adding the issue ends
+	assert.Contains(t, repo.locked, workspace.Id)
+	assert.Nil(t, s.CheckQuota(ctx, QuotaStorage, 1<<30))
+
+	// members over the limit cannot join
+	_, err = s.Invite(ctx, &workspaces.InviteWorkspaceMemberRequest{WorkspaceUuid: workspace.Uuid, Email: "guest@example.com"})
+	assert.Nil(t, err)
+	token := invitationToken(t, mails, "guest@example.com")
+	guestCtx := auth.ContextWithUser(context.Background(), repo.users[1].ToProto(false))
+	_, err = s.AcceptInvitation(guestCtx, token)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+
+	_, err = s.UpdateQuotas(ctx, &workspaces.UpdateWorkspaceQuotasRequest{WorkspaceUuid: workspace.Uuid, MaxMembers: 2})
+	assert.Nil(t, err)
+	_, err = s.AcceptInvitation(guestCtx, token)
+	assert.Nil(t, err)
 }
@@ -0,0 +1,119 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+var (
+	issueKeyPrefixRe = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,9}$`)
+	weekDays         = []interface{}{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+	errTimeZone      = errors.New("must be a valid IANA time zone")
+)
+
+// timeZoneRule checks the time zone can be loaded
+func timeZoneRule(value interface{}) error {
+	tz, _ := value.(string)
+	if _, err := time.LoadLocation(tz); err != nil {
+		return errTimeZone
+	}
+	return nil
+}
+
+// ValidateUpdateSettingsRequest validates the UpdateWorkspaceSettingsRequest fields.
+func ValidateUpdateSettingsRequest(u *workspacesProto.UpdateWorkspaceSettingsRequest) error {
+	return validation.ValidateStruct(u,
+		validation.Field(&u.WorkspaceUuid, validation.Required, is.UUID),
+		validation.Field(&u.DefaultStatusUuid, is.UUID),
+		validation.Field(&u.EstimateScale, validation.Required,
+			validation.In(entity.EstimateScalePoints, entity.EstimateScaleHours, entity.EstimateScaleTShirt)),
+		validation.Field(&u.TimeZone, validation.Required, validation.By(timeZoneRule)),
+		validation.Field(&u.WeekStart, validation.Required, validation.In(weekDays...)),
+		validation.Field(&u.IssueKeyPrefix, validation.Required,
+			validation.Match(issueKeyPrefixRe).Error("must be 1 to 10 upper case letters or digits starting with a letter")),
+	)
+}
+
+// settings returns the settings of the workspace, or the defaults if they were never changed.
+func (s service) settings(ctx context.Context, workspace entity.Workspace) (entity.WorkspaceSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, workspace.ID)
+	if err == pg.ErrNoRows {
+		settings = entity.DefaultWorkspaceSettings(workspace.ID)
+		settings.Workspace = &workspace
+		return settings, nil
+	}
+	return settings, err
+}
+
+// GetSettings returns the settings of the workspace with the specified UUID.
+func (s service) GetSettings(ctx context.Context, workspaceUUID string) (*workspacesProto.WorkspaceSettings, error) {
+	workspace, err := s.repo.Get(ctx, workspaceUUID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settings(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	return settings.ToProto(), nil
+}
+
+// CurrentSettings returns the settings of the workspace of the request.
+func (s service) CurrentSettings(ctx context.Context) (*workspacesProto.WorkspaceSettings, error) {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settings(ctx, entity.WorkspaceFromProto(workspace))
+	if err != nil {
+		return nil, err
+	}
+	return settings.ToProto(), nil
+}
+
+// UpdateSettings updates the settings of the workspace with the specified UUID.
+func (s service) UpdateSettings(ctx context.Context, req *workspacesProto.UpdateWorkspaceSettingsRequest) (*workspacesProto.WorkspaceSettings, error) {
+	if err := ValidateUpdateSettingsRequest(req); err != nil {
+		return nil, err
+	}
+	workspace, err := s.repo.Get(ctx, req.WorkspaceUuid)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settings(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.DefaultStatusID = 0
+	if req.DefaultStatusUuid != "" {
+		status, err := s.repo.GetStatus(ctx, workspace.ID, req.DefaultStatusUuid)
+		if err != nil {
+			return nil, err
+		}
+		settings.DefaultStatusID = status.ID
+	}
+
+	now := time.Now()
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	settings.EstimateScale = req.EstimateScale
+	settings.TimeZone = req.TimeZone
+	settings.WeekStart = req.WeekStart
+	settings.IssueKeyPrefix = req.IssueKeyPrefix
+	settings.UpdatedAt = now
+	if err := s.repo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return s.GetSettings(ctx, workspace.UUID)
+}
@@ -2,10 +2,13 @@ package cycles
 
 import (
 	"context"
+	"errors"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/internal/acl"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/grpcgw"
 	"github.com/mirzakhany/pm/protobuf/cycles"
 	"google.golang.org/grpc"
@@ -59,6 +62,9 @@ func (a api) CreateCycle(ctx context.Context, request *cycles.CreateCycleRequest
 func (a api) UpdateCycle(ctx context.Context, request *cycles.UpdateCycleRequest) (*cycles.Cycle, error) {
 	res, err := a.service.Update(ctx, request)
 	if err != nil {
+		if errors.Is(err, acl.ErrReadOnly) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	return res, err
@@ -67,13 +73,68 @@ func (a api) UpdateCycle(ctx context.Context, request *cycles.UpdateCycleRequest
 func (a api) DeleteCycle(ctx context.Context, request *cycles.DeleteCycleRequest) (*empty.Empty, error) {
 	_, err := a.service.Delete(ctx, request.Uuid)
 	if err != nil {
+		if errors.Is(err, acl.ErrReadOnly) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	return nil, err
 }
 
+func (a api) SetCycleCapacity(ctx context.Context, request *cycles.SetCycleCapacityRequest) (*cycles.CycleCapacity, error) {
+	res, err := a.service.SetCapacity(ctx, request)
+	if err != nil {
+		if errors.Is(err, acl.ErrReadOnly) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) ListCycleCapacities(ctx context.Context, request *cycles.ListCycleCapacitiesRequest) (*cycles.ListCycleCapacitiesResponse, error) {
+	res, err := a.service.QueryCapacities(ctx, request.CycleUuid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) DeleteCycleCapacity(ctx context.Context, request *cycles.DeleteCycleCapacityRequest) (*empty.Empty, error) {
+	err := a.service.DeleteCapacity(ctx, request.CycleUuid, request.UserUuid)
+	if err != nil {
+		if errors.Is(err, acl.ErrReadOnly) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (a api) GetCyclePlan(ctx context.Context, request *cycles.GetCyclePlanRequest) (*cycles.CyclePlan, error) {
+	res, err := a.service.GetPlan(ctx, request.Uuid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
 func New(srv Service) API {
 	s := api{service: srv}
 	grpcgw.RegisterController(s)
 	return s
 }
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/cyclesV1.CycleService/ListCycles":          auth.Require("cycles", "list"),
+		"/cyclesV1.CycleService/GetCycle":            auth.Require("cycles", "get"),
+		"/cyclesV1.CycleService/CreateCycle":         auth.Require("cycles", "create"),
+		"/cyclesV1.CycleService/UpdateCycle":         auth.Require("cycles", "update"),
+		"/cyclesV1.CycleService/DeleteCycle":         auth.Require("cycles", "delete"),
+		"/cyclesV1.CycleService/GetCyclePlan":        auth.Require("cycles", "get"),
+		"/cyclesV1.CycleService/ListCycleCapacities": auth.Require("capacities", "list"),
+		"/cyclesV1.CycleService/SetCycleCapacity":    auth.Require("capacities", "update"),
+		"/cyclesV1.CycleService/DeleteCycleCapacity": auth.Require("capacities", "delete"),
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/mirzakhany/pm/internal/entity"
 
 	"github.com/mirzakhany/pm/internal/auth/users"
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
 
 	"github.com/go-pg/pg"
 )
@@ -15,11 +16,16 @@ var errCRUD = errors.New("error crud")
 
 // NewServiceForTest creates a new user service for test.
 func NewServiceForTest(userSrv users.Service) Service {
-	return NewService(&mockRepository{}, userSrv)
+	return NewService(&mockRepository{}, userSrv, workspaces.NewServiceForTest())
 }
 
 type mockRepository struct {
-	items []entity.Cycle
+	items      []entity.Cycle
+	capacities []entity.CycleCapacity
+	loads      map[uint64][]AssigneeLoad
+	// readOnly are the IDs of the cycles the current user cannot edit
+	readOnly map[uint64]bool
+	lastID   uint64
 }
 
 func (m mockRepository) Get(ctx context.Context, id string) (entity.Cycle, error) {
@@ -43,6 +49,8 @@ func (m *mockRepository) Create(ctx context.Context, cycle entity.Cycle) error {
 	if cycle.Title == "error" {
 		return errCRUD
 	}
+	m.lastID++
+	cycle.ID = m.lastID
 	m.items = append(m.items, cycle)
 	return nil
 }
@@ -70,3 +78,42 @@ func (m *mockRepository) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+func (m mockRepository) GetCapacities(ctx context.Context, cycleID uint64) ([]entity.CycleCapacity, error) {
+	var capacities []entity.CycleCapacity
+	for _, item := range m.capacities {
+		if item.CycleID == cycleID {
+			capacities = append(capacities, item)
+		}
+	}
+	return capacities, nil
+}
+
+func (m *mockRepository) SetCapacity(ctx context.Context, capacity entity.CycleCapacity) error {
+	for i, item := range m.capacities {
+		if item.CycleID == capacity.CycleID && item.UserID == capacity.UserID {
+			m.capacities[i] = capacity
+			return nil
+		}
+	}
+	m.capacities = append(m.capacities, capacity)
+	return nil
+}
+
+func (m *mockRepository) DeleteCapacity(ctx context.Context, cycleID, userID uint64) error {
+	for i, item := range m.capacities {
+		if item.CycleID == cycleID && item.UserID == userID {
+			m.capacities = append(m.capacities[:i], m.capacities[i+1:]...)
+			return nil
+		}
+	}
+	return pg.ErrNoRows
+}
+
+func (m mockRepository) AssigneeLoads(ctx context.Context, cycleID uint64) ([]AssigneeLoad, error) {
+	return m.loads[cycleID], nil
+}
+
+func (m mockRepository) Editable(ctx context.Context, id uint64) (bool, error) {
+	return !m.readOnly[id], nil
+}
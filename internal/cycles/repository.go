@@ -3,8 +3,12 @@ package cycles
 import (
 	"context"
 
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/mirzakhany/pm/internal/acl"
 	"github.com/mirzakhany/pm/internal/entity"
 
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/db"
 )
 
@@ -22,6 +26,27 @@ type Repository interface {
 	Update(ctx context.Context, cycle entity.Cycle) error
 	// Delete removes the cycle with given UUID from the storage.
 	Delete(ctx context.Context, uuid string) error
+	// Editable returns true if the access control list of the cycle with the given ID lets the current user edit it.
+	Editable(ctx context.Context, id uint64) (bool, error)
+
+	// CycleCapacity
+
+	// GetCapacities returns the capacities recorded for the cycle with the given ID.
+	GetCapacities(ctx context.Context, cycleID uint64) ([]entity.CycleCapacity, error)
+	// SetCapacity creates or updates the capacity of a user in a cycle.
+	SetCapacity(ctx context.Context, capacity entity.CycleCapacity) error
+	// DeleteCapacity removes the capacity of a user in a cycle.
+	DeleteCapacity(ctx context.Context, cycleID, userID uint64) error
+	// AssigneeLoads returns the sum of issue estimates per assignee in the cycle with the given ID.
+	AssigneeLoads(ctx context.Context, cycleID uint64) ([]AssigneeLoad, error)
+}
+
+// AssigneeLoad is the amount of work assigned to a user in a cycle
+type AssigneeLoad struct {
+	AssigneeID  uint64
+	Assignee    *entity.User `pg:"-"`
+	Estimate    uint64
+	IssuesCount int64
 }
 
 // repository persists cycles in database
@@ -34,23 +59,40 @@ func NewRepository(db *db.DB) Repository {
 	return repository{db}
 }
 
-// Get reads the cycle with the specified ID from the database.
+// Get reads the cycle with the specified UUID in the current workspace from the database.
 func (r repository) Get(ctx context.Context, uuid string) (entity.Cycle, error) {
 	var cycle entity.Cycle
-	err := r.db.With(ctx).Model(&cycle).Where("i.uuid = ?", uuid).First()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return cycle, err
+	}
+	err = r.db.With(ctx).Model(&cycle).
+		Where("i.uuid = ?", uuid).
+		Where("i.workspace_id = ?", workspace.Id).
+		Apply(visible(ctx)).
+		First()
 	return cycle, err
 }
 
-// Create saves a new cycle record in the database.
-// It returns the ID of the newly inserted cycle record.
+// Create saves a new cycle record in the current workspace.
 func (r repository) Create(ctx context.Context, cycle entity.Cycle) error {
-	_, err := r.db.With(ctx).Model(cycle).Insert()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	cycle.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&cycle).Insert()
 	return err
 }
 
 // Update saves the changes to an cycle in the database.
 func (r repository) Update(ctx context.Context, cycle entity.Cycle) error {
-	_, err := r.db.With(ctx).Model(&cycle).WherePK().Update()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	cycle.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&cycle).WherePK().Where("workspace_id = ?", workspace.Id).Update()
 	return err
 }
 
@@ -60,24 +102,140 @@ func (r repository) Delete(ctx context.Context, uuid string) error {
 	if err != nil {
 		return err
 	}
+	if err := acl.Remove(r.db.With(ctx), acl.ObjectCycles, cycle.ID); err != nil {
+		return err
+	}
 	_, err = r.db.With(ctx).Model(&cycle).WherePK().Delete()
 	return err
 }
 
-// Count returns the number of the cycle records in the database.
+// Editable checks the access control list of the cycle with the specified ID for the current user.
+func (r repository) Editable(ctx context.Context, id uint64) (bool, error) {
+	return acl.Editable(ctx, r.db.With(ctx), acl.ObjectCycles, id)
+}
+
+// visible filters the cycles of a query to the ones the current user can view.
+func visible(ctx context.Context) func(q *orm.Query) (*orm.Query, error) {
+	return func(q *orm.Query) (*orm.Query, error) {
+		return acl.Visible(ctx, q, acl.ObjectCycles, "i.id"), nil
+	}
+}
+
+// Count returns the number of the cycle records in the current workspace.
 func (r repository) Count(ctx context.Context) (int64, error) {
-	var count int
-	count, err := r.db.With(ctx).Model((*entity.Cycle)(nil)).Count()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count, err := r.db.With(ctx).Model((*entity.Cycle)(nil)).
+		Where("i.workspace_id = ?", workspace.Id).
+		Apply(visible(ctx)).
+		Count()
 	return int64(count), err
 }
 
-// Query retrieves the cycle records with the specified offset and limit from the database.
+// Query retrieves the cycle records of the current workspace with the specified offset and limit from the database.
 func (r repository) Query(ctx context.Context, offset, limit int64) ([]entity.Cycle, int, error) {
 	var _cycles []entity.Cycle
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
 	count, err := r.db.With(ctx).Model(&_cycles).
+		Where("i.workspace_id = ?", workspace.Id).
+		Apply(visible(ctx)).
 		Order("id ASC").
 		Limit(int(limit)).
 		Offset(int(offset)).
 		SelectAndCount()
 	return _cycles, count, err
 }
+
+// GetCapacities reads the capacities of the cycle with the specified ID from the database.
+func (r repository) GetCapacities(ctx context.Context, cycleID uint64) ([]entity.CycleCapacity, error) {
+	var capacities []entity.CycleCapacity
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.With(ctx).Model(&capacities).
+		Relation("Cycle").
+		Relation("User").
+		Where("cc.cycle_id = ?", cycleID).
+		Where("cycle.workspace_id = ?", workspace.Id).
+		Order("cc.id ASC").
+		Select()
+	return capacities, err
+}
+
+// SetCapacity inserts the capacity record or updates it if the user already has one in the cycle.
+func (r repository) SetCapacity(ctx context.Context, capacity entity.CycleCapacity) error {
+	_, err := r.db.With(ctx).Model(&capacity).
+		OnConflict("(cycle_id, user_id) DO UPDATE").
+		Set("capacity = EXCLUDED.capacity").
+		Set("unit = EXCLUDED.unit").
+		Set("updated_at = EXCLUDED.updated_at").
+		Insert()
+	return err
+}
+
+// DeleteCapacity deletes the capacity of the user in the cycle from the database.
+func (r repository) DeleteCapacity(ctx context.Context, cycleID, userID uint64) error {
+	res, err := r.db.With(ctx).Model((*entity.CycleCapacity)(nil)).
+		Where("cycle_id = ?", cycleID).
+		Where("user_id = ?", userID).
+		Delete()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pg.ErrNoRows
+	}
+	return nil
+}
+
+// AssigneeLoads sums the estimates of the issues in the cycle grouped by their assignee.
+func (r repository) AssigneeLoads(ctx context.Context, cycleID uint64) ([]AssigneeLoad, error) {
+	var loads []AssigneeLoad
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.With(ctx).Model((*entity.Issue)(nil)).
+		Column("assignee_id").
+		ColumnExpr("coalesce(sum(estimate), 0) AS estimate").
+		ColumnExpr("count(*) AS issues_count").
+		Where("cycle_id = ?", cycleID).
+		Where("workspace_id = ?", workspace.Id).
+		Group("assignee_id").
+		Order("assignee_id ASC").
+		Select(&loads)
+	if err != nil || len(loads) == 0 {
+		return loads, err
+	}
+
+	var ids []uint64
+	for _, load := range loads {
+		if load.AssigneeID != 0 {
+			ids = append(ids, load.AssigneeID)
+		}
+	}
+	if len(ids) == 0 {
+		return loads, nil
+	}
+
+	var assignees []entity.User
+	err = r.db.With(ctx).Model(&assignees).Where("id IN (?)", pg.In(ids)).Select()
+	if err != nil {
+		return nil, err
+	}
+	for i := range loads {
+		for j := range assignees {
+			if assignees[j].ID == loads[i].AssigneeID {
+				loads[i].Assignee = &assignees[j]
+				break
+			}
+		}
+	}
+	return loads, nil
+}
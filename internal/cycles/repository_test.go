@@ -9,7 +9,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/db"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,7 +20,8 @@ func TestRepository(t *testing.T) {
 	db.ResetTables(t, database, "users", "cycles")
 	repo := NewRepository(database)
 
-	ctx := context.Background()
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 1})
+	otherCtx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 2})
 	// initial count
 	count, err := repo.Count(ctx)
 	assert.Nil(t, err)
@@ -47,6 +50,8 @@ func TestRepository(t *testing.T) {
 	_, err = repo.Get(ctx, "test0")
 	assert.NotNil(t, err)
 	assert.EqualError(t, gorm.ErrRecordNotFound, err.Error())
+	_, err = repo.Get(otherCtx, testUuid)
+	assert.EqualError(t, gorm.ErrRecordNotFound, err.Error())
 
 	// update
 	err = repo.Update(ctx, entity.Cycle{
@@ -2,13 +2,17 @@ package cycles
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/mirzakhany/pm/internal/acl"
 	"github.com/mirzakhany/pm/internal/entity"
 
 	"github.com/golang/protobuf/ptypes"
 
 	"github.com/mirzakhany/pm/internal/auth/users"
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
@@ -23,12 +27,36 @@ type Service interface {
 	Create(ctx context.Context, input *cyclesProto.CreateCycleRequest) (*cyclesProto.Cycle, error)
 	Update(ctx context.Context, input *cyclesProto.UpdateCycleRequest) (*cyclesProto.Cycle, error)
 	Delete(ctx context.Context, uuid string) (*cyclesProto.Cycle, error)
+
+	SetCapacity(ctx context.Context, input *cyclesProto.SetCycleCapacityRequest) (*cyclesProto.CycleCapacity, error)
+	QueryCapacities(ctx context.Context, cycleUUID string) (*cyclesProto.ListCycleCapacitiesResponse, error)
+	DeleteCapacity(ctx context.Context, cycleUUID, userUUID string) error
+	// GetPlan compares the estimate assigned to each user in the cycle against their capacity
+	GetPlan(ctx context.Context, cycleUUID string) (*cyclesProto.CyclePlan, error)
 }
 
+const (
+	// CapacityUnitPoints is the capacity unit when estimates are in story points
+	CapacityUnitPoints = "points"
+	// CapacityUnitHours is the capacity unit when estimates are in hours
+	CapacityUnitHours = "hours"
+)
+
+var (
+	// errMixedUnits is returned when a capacity is not in the unit of the other capacities of the cycle
+	errMixedUnits = errors.New("the capacities of a cycle must all be in the same unit")
+	// errScaleUnit is returned when a capacity is not in the unit of the estimates of the workspace
+	errScaleUnit = errors.New("the capacity must be in the unit of the estimates of the workspace")
+	// errTShirtCapacity is returned for capacities in workspaces estimating in t-shirt sizes, which cannot be summed up
+	errTShirtCapacity = errors.New("t-shirt size estimates cannot be compared to a capacity")
+	errNotMember      = errors.New("capacities can only be set for the members of the workspace")
+)
+
 // ValidateCreateRequest validates the CreateCycleRequest fields.
 func ValidateCreateRequest(c *cyclesProto.CreateCycleRequest) error {
 	return validation.ValidateStruct(c,
 		validation.Field(&c.Title, validation.Required, validation.Length(0, 128)),
+		validation.Field(&c.Goals, validation.Each(validation.Required, validation.Length(0, 256))),
 	)
 }
 
@@ -36,17 +64,28 @@ func ValidateCreateRequest(c *cyclesProto.CreateCycleRequest) error {
 func ValidateUpdateRequest(u *cyclesProto.UpdateCycleRequest) error {
 	return validation.ValidateStruct(u,
 		validation.Field(&u.Title, validation.Required, validation.Length(0, 128)),
+		validation.Field(&u.Goals, validation.Each(validation.Required, validation.Length(0, 256))),
+	)
+}
+
+// ValidateSetCapacityRequest validates the SetCycleCapacityRequest fields.
+func ValidateSetCapacityRequest(c *cyclesProto.SetCycleCapacityRequest) error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.CycleUuid, validation.Required),
+		validation.Field(&c.UserUuid, validation.Required),
+		validation.Field(&c.Unit, validation.In(CapacityUnitPoints, CapacityUnitHours)),
 	)
 }
 
 type service struct {
-	repo    Repository
-	userSrv users.Service
+	repo          Repository
+	userSrv       users.Service
+	workspacesSrv workspaces.Service
 }
 
 // NewService creates a new cycle service.
-func NewService(repo Repository, userSrv users.Service) Service {
-	return service{repo, userSrv}
+func NewService(repo Repository, userSrv users.Service, workspacesSrv workspaces.Service) Service {
+	return service{repo, userSrv, workspacesSrv}
 }
 
 // Get returns the cycle with the specified the cycle UUID.
@@ -87,6 +126,7 @@ func (s service) Create(ctx context.Context, req *cyclesProto.CreateCycleRequest
 		Title:       req.Title,
 		Description: req.Description,
 		Active:      req.Active,
+		Goals:       req.Goals,
 		StartAt:     startAt,
 		EndAt:       endAt,
 		CreatedAt:   now,
@@ -113,6 +153,9 @@ func (s service) Update(ctx context.Context, req *cyclesProto.UpdateCycleRequest
 	if err != nil {
 		return nil, err
 	}
+	if err := s.editable(ctx, cycle.ID); err != nil {
+		return nil, err
+	}
 	startAt, err := ptypes.Timestamp(req.StartAt)
 	if err != nil {
 		return nil, err
@@ -124,15 +167,13 @@ func (s service) Update(ctx context.Context, req *cyclesProto.UpdateCycleRequest
 	}
 
 	now := time.Now()
-	cycle.Title = req.Title
-	cycle.UpdatedAt = now
-
 	cycleModel := entity.Cycle{
 		ID:          cycle.ID,
 		UUID:        cycle.UUID,
 		Title:       req.Title,
 		Description: req.Description,
 		Active:      req.Active,
+		Goals:       req.Goals,
 		StartAt:     startAt,
 		EndAt:       endAt,
 		CreatedAt:   cycle.CreatedAt,
@@ -142,7 +183,7 @@ func (s service) Update(ctx context.Context, req *cyclesProto.UpdateCycleRequest
 	if err := s.repo.Update(ctx, cycleModel); err != nil {
 		return nil, err
 	}
-	return cycle.ToProto(true), nil
+	return cycleModel.ToProto(true), nil
 }
 
 // Delete deletes the cycle with the specified UUID.
@@ -151,12 +192,28 @@ func (s service) Delete(ctx context.Context, UUID string) (*cyclesProto.Cycle, e
 	if err != nil {
 		return nil, err
 	}
+	if err := s.editable(ctx, cycle.Id); err != nil {
+		return nil, err
+	}
 	if err = s.repo.Delete(ctx, UUID); err != nil {
 		return nil, err
 	}
 	return cycle, nil
 }
 
+// editable returns acl.ErrReadOnly if the access control list of the cycle with the specified ID
+// does not let the current user edit it.
+func (s service) editable(ctx context.Context, id uint64) error {
+	ok, err := s.repo.Editable(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return acl.ErrReadOnly
+	}
+	return nil
+}
+
 // Count returns the number of cycles.
 func (s service) Count(ctx context.Context) (int64, error) {
 	return s.repo.Count(ctx)
@@ -175,3 +232,183 @@ func (s service) Query(ctx context.Context, offset, limit int64) (*cyclesProto.L
 		Limit:      limit,
 	}, nil
 }
+
+// SetCapacity records the availability of a user in the cycle.
+func (s service) SetCapacity(ctx context.Context, req *cyclesProto.SetCycleCapacityRequest) (*cyclesProto.CycleCapacity, error) {
+	if err := ValidateSetCapacityRequest(req); err != nil {
+		return nil, err
+	}
+
+	cycle, err := s.repo.Get(ctx, req.CycleUuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.editable(ctx, cycle.ID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.workspacesSrv.IsMember(ctx, req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, errNotMember
+	}
+	user, err := s.userSrv.GetByUUID(ctx, req.UserUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	unit, err := s.capacityUnit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Unit != "" && req.Unit != unit {
+		return nil, fmt.Errorf("%w, the estimates are in %s", errScaleUnit, unit)
+	}
+	// the capacities of the cycle are summed up in its plan
+	capacities, err := s.repo.GetCapacities(ctx, cycle.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range capacities {
+		if c.UserID != user.Id && c.Unit != unit {
+			return nil, fmt.Errorf("%w, the cycle has capacities in %s", errMixedUnits, c.Unit)
+		}
+	}
+
+	now := time.Now()
+	userModel := entity.UserFromProto(user)
+	capacity := entity.CycleCapacity{
+		CycleID:   cycle.ID,
+		Cycle:     &cycle,
+		UserID:    userModel.ID,
+		User:      &userModel,
+		Capacity:  req.Capacity,
+		Unit:      unit,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.SetCapacity(ctx, capacity); err != nil {
+		return nil, err
+	}
+	return capacity.ToProto(true), nil
+}
+
+// capacityUnit returns the unit of the estimates of the workspace of the request, the capacities are compared to them in it.
+func (s service) capacityUnit(ctx context.Context) (string, error) {
+	settings, err := s.workspacesSrv.CurrentSettings(ctx)
+	if err != nil {
+		return "", err
+	}
+	switch settings.EstimateScale {
+	case entity.EstimateScaleHours:
+		return CapacityUnitHours, nil
+	case entity.EstimateScaleTShirt:
+		return "", errTShirtCapacity
+	}
+	return CapacityUnitPoints, nil
+}
+
+// QueryCapacities returns the capacities recorded for the cycle.
+func (s service) QueryCapacities(ctx context.Context, cycleUUID string) (*cyclesProto.ListCycleCapacitiesResponse, error) {
+	cycle, err := s.repo.Get(ctx, cycleUUID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetCapacities(ctx, cycle.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &cyclesProto.ListCycleCapacitiesResponse{
+		Capacities: entity.CycleCapacityToProtoList(items, true),
+	}, nil
+}
+
+// DeleteCapacity removes the availability of a user from the cycle.
+func (s service) DeleteCapacity(ctx context.Context, cycleUUID, userUUID string) error {
+	cycle, err := s.repo.Get(ctx, cycleUUID)
+	if err != nil {
+		return err
+	}
+	if err := s.editable(ctx, cycle.ID); err != nil {
+		return err
+	}
+	user, err := s.userSrv.GetByUUID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteCapacity(ctx, cycle.ID, user.Id)
+}
+
+// GetPlan returns the capacity plan of the cycle with the specified UUID.
+// The capacities must be in the unit of the estimates, which changes with the estimate scale of the workspace.
+func (s service) GetPlan(ctx context.Context, cycleUUID string) (*cyclesProto.CyclePlan, error) {
+	cycle, err := s.repo.Get(ctx, cycleUUID)
+	if err != nil {
+		return nil, err
+	}
+	unit, err := s.capacityUnit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	capacities, err := s.repo.GetCapacities(ctx, cycle.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range capacities {
+		if c.Unit != unit {
+			return nil, fmt.Errorf("%w, the capacities are in %s and the estimates in %s", errScaleUnit, c.Unit, unit)
+		}
+	}
+	loads, err := s.repo.AssigneeLoads(ctx, cycle.ID)
+	if err != nil {
+		return nil, err
+	}
+	return buildPlan(cycle, unit, capacities, loads), nil
+}
+
+// buildPlan merges the capacities and the assigned work of a cycle into a plan in the unit.
+// Users with assigned work but no recorded capacity have a capacity of zero.
+func buildPlan(cycle entity.Cycle, unit string, capacities []entity.CycleCapacity, loads []AssigneeLoad) *cyclesProto.CyclePlan {
+	plan := &cyclesProto.CyclePlan{CycleUuid: cycle.UUID, Unit: unit}
+	index := make(map[uint64]*cyclesProto.AssigneePlan)
+
+	for _, c := range capacities {
+		item := &cyclesProto.AssigneePlan{Capacity: c.Capacity, Unit: c.Unit}
+		if c.User != nil {
+			item.User = c.User.ToProto(true)
+		}
+		index[c.UserID] = item
+		plan.Assignees = append(plan.Assignees, item)
+		plan.TotalCapacity += c.Capacity
+	}
+
+	for _, l := range loads {
+		plan.TotalAssigned += l.Estimate
+		if l.AssigneeID == 0 {
+			plan.Unassigned += l.Estimate
+			continue
+		}
+		item, ok := index[l.AssigneeID]
+		if !ok {
+			item = &cyclesProto.AssigneePlan{}
+			if l.Assignee != nil {
+				item.User = l.Assignee.ToProto(true)
+			}
+			index[l.AssigneeID] = item
+			plan.Assignees = append(plan.Assignees, item)
+		}
+		item.Assigned += l.Estimate
+		item.IssuesCount += l.IssuesCount
+	}
+
+	for _, item := range plan.Assignees {
+		item.Remaining = int64(item.Capacity) - int64(item.Assigned)
+		item.Overcommitted = item.Assigned > item.Capacity
+		if item.Overcommitted {
+			plan.Overcommitted = true
+		}
+	}
+	return plan
+}
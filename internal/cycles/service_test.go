@@ -2,6 +2,7 @@ package cycles
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -10,7 +11,11 @@ import (
 
 	usersProto "github.com/mirzakhany/pm/protobuf/users"
 
+	"github.com/mirzakhany/pm/internal/acl"
 	userSrv "github.com/mirzakhany/pm/internal/auth/users"
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/internal/entity"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
 
 	cycles "github.com/mirzakhany/pm/protobuf/cycles"
 	"github.com/stretchr/testify/assert"
@@ -56,7 +61,7 @@ func TestUpdateCycleRequest_Validate(t *testing.T) {
 
 func Test_service_CRUD(t *testing.T) {
 	userServices := userSrv.NewServiceForTest()
-	s := NewService(&mockRepository{}, userServices)
+	s := NewService(&mockRepository{}, userServices, workspaces.NewServiceForTest())
 	ctx := context.Background()
 
 	// initial count
@@ -135,3 +140,166 @@ func Test_service_CRUD(t *testing.T) {
 	count, _ = s.Count(ctx)
 	assert.Equal(t, int64(1), count)
 }
+
+func Test_service_Plan(t *testing.T) {
+	userServices := userSrv.NewServiceForTest()
+	repo := &mockRepository{loads: make(map[uint64][]AssigneeLoad)}
+	workspaceServices := workspaces.NewServiceForTest()
+	s := NewService(repo, userServices, workspaceServices)
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspacesProto.Workspace{Id: 1})
+	now := timestamppb.Now()
+
+	user1, err := userServices.Create(ctx, &usersProto.CreateUserRequest{
+		Username: "test1", Password: "test", Email: "test1@example.com", Enable: true,
+	})
+	assert.Nil(t, err)
+	user2, err := userServices.Create(ctx, &usersProto.CreateUserRequest{
+		Username: "test2", Password: "test", Email: "test2@example.com", Enable: true,
+	})
+	assert.Nil(t, err)
+
+	cycle, err := s.Create(ctx, &cycles.CreateCycleRequest{Title: "test", Description: "test", StartAt: now, EndAt: now, Active: true})
+	assert.Nil(t, err)
+
+	// capacities can only be set for the members of the workspace
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 5})
+	assert.Equal(t, errNotMember, err)
+	workspaces.AddMemberForTest(workspaceServices, 1, user1)
+	workspaces.AddMemberForTest(workspaceServices, 1, user2)
+
+	// validation error in set capacity
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 8, Unit: "days"})
+	assert.NotNil(t, err)
+
+	// set capacity
+	capacity, err := s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), capacity.Capacity)
+	assert.Equal(t, CapacityUnitPoints, capacity.Unit)
+	assert.Equal(t, user1.Uuid, capacity.User.Uuid)
+
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 8})
+	assert.Nil(t, err)
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user2.Uuid, Capacity: 10})
+	assert.Nil(t, err)
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: "none", UserUuid: user2.Uuid, Capacity: 10})
+	assert.NotNil(t, err)
+	// capacities in another unit than the estimates cannot be compared to them
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user2.Uuid, Capacity: 10, Unit: CapacityUnitHours})
+	assert.True(t, errors.Is(err, errScaleUnit))
+
+	capacities, err := s.QueryCapacities(ctx, cycle.Uuid)
+	assert.Nil(t, err)
+	assert.Len(t, capacities.Capacities, 2)
+
+	// plan
+	cycleID := repo.items[0].ID
+	repo.loads[cycleID] = []AssigneeLoad{
+		{AssigneeID: 0, Estimate: 3, IssuesCount: 1},
+		{AssigneeID: repo.capacities[0].UserID, Estimate: 13, IssuesCount: 3},
+		{AssigneeID: repo.capacities[1].UserID, Estimate: 4, IssuesCount: 2},
+	}
+	plan, err := s.GetPlan(ctx, cycle.Uuid)
+	assert.Nil(t, err)
+	assert.True(t, plan.Overcommitted)
+	assert.Equal(t, uint64(18), plan.TotalCapacity)
+	assert.Equal(t, CapacityUnitPoints, plan.Unit)
+	assert.Equal(t, uint64(20), plan.TotalAssigned)
+	assert.Equal(t, uint64(3), plan.Unassigned)
+	assert.Len(t, plan.Assignees, 2)
+	assert.Equal(t, user1.Uuid, plan.Assignees[0].User.Uuid)
+	assert.True(t, plan.Assignees[0].Overcommitted)
+	assert.Equal(t, int64(-5), plan.Assignees[0].Remaining)
+	assert.False(t, plan.Assignees[1].Overcommitted)
+	assert.Equal(t, int64(6), plan.Assignees[1].Remaining)
+
+	// delete capacity
+	err = s.DeleteCapacity(ctx, cycle.Uuid, user2.Uuid)
+	assert.Nil(t, err)
+	err = s.DeleteCapacity(ctx, cycle.Uuid, user2.Uuid)
+	assert.NotNil(t, err)
+
+	// assignees without capacity are overcommitted as soon as they have work
+	plan, err = s.GetPlan(ctx, cycle.Uuid)
+	assert.Nil(t, err)
+	assert.Len(t, plan.Assignees, 2)
+	assert.Equal(t, uint64(0), plan.Assignees[1].Capacity)
+	assert.True(t, plan.Assignees[1].Overcommitted)
+}
+
+func Test_service_CapacityScales(t *testing.T) {
+	userServices := userSrv.NewServiceForTest()
+	repo := &mockRepository{loads: make(map[uint64][]AssigneeLoad)}
+	workspaceServices := workspaces.NewServiceForTest()
+	s := NewService(repo, userServices, workspaceServices)
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspacesProto.Workspace{Id: 1})
+	now := timestamppb.Now()
+
+	user1, err := userServices.Create(ctx, &usersProto.CreateUserRequest{
+		Username: "test1", Password: "test", Email: "test1@example.com", Enable: true,
+	})
+	assert.Nil(t, err)
+	workspaces.AddMemberForTest(workspaceServices, 1, user1)
+	cycle, err := s.Create(ctx, &cycles.CreateCycleRequest{Title: "test", Description: "test", StartAt: now, EndAt: now, Active: true})
+	assert.Nil(t, err)
+
+	// points
+	workspaces.SetEstimateScaleForTest(workspaceServices, 1, entity.EstimateScalePoints)
+	capacity, err := s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, CapacityUnitPoints, capacity.Unit)
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 5, Unit: CapacityUnitHours})
+	assert.True(t, errors.Is(err, errScaleUnit))
+	plan, err := s.GetPlan(ctx, cycle.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, CapacityUnitPoints, plan.Unit)
+
+	// hours, the capacities in points are not compared to estimates in hours
+	workspaces.SetEstimateScaleForTest(workspaceServices, 1, entity.EstimateScaleHours)
+	_, err = s.GetPlan(ctx, cycle.Uuid)
+	assert.True(t, errors.Is(err, errScaleUnit))
+	capacity, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 40, Unit: CapacityUnitHours})
+	assert.Nil(t, err)
+	assert.Equal(t, CapacityUnitHours, capacity.Unit)
+	plan, err = s.GetPlan(ctx, cycle.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, CapacityUnitHours, plan.Unit)
+	assert.Equal(t, uint64(40), plan.TotalCapacity)
+
+	// t-shirt sizes
+	workspaces.SetEstimateScaleForTest(workspaceServices, 1, entity.EstimateScaleTShirt)
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 5})
+	assert.Equal(t, errTShirtCapacity, err)
+	_, err = s.GetPlan(ctx, cycle.Uuid)
+	assert.Equal(t, errTShirtCapacity, err)
+}
+
+func Test_service_ReadOnly(t *testing.T) {
+	userServices := userSrv.NewServiceForTest()
+	repo := &mockRepository{readOnly: make(map[uint64]bool)}
+	s := NewService(repo, userServices, workspaces.NewServiceForTest())
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspacesProto.Workspace{Id: 1})
+	now := timestamppb.Now()
+
+	user1, err := userServices.Create(ctx, &usersProto.CreateUserRequest{
+		Username: "test1", Password: "test", Email: "test1@example.com", Enable: true,
+	})
+	assert.Nil(t, err)
+	cycle, err := s.Create(ctx, &cycles.CreateCycleRequest{Title: "test", Description: "test", StartAt: now, EndAt: now, Active: true})
+	assert.Nil(t, err)
+	repo.readOnly[cycle.Id] = true
+
+	// the access control list of the cycle does not let the user edit it
+	_, err = s.Update(ctx, &cycles.UpdateCycleRequest{Uuid: cycle.Uuid, Title: "updated", Description: "test", StartAt: now, EndAt: now})
+	assert.Equal(t, acl.ErrReadOnly, err)
+	_, err = s.SetCapacity(ctx, &cycles.SetCycleCapacityRequest{CycleUuid: cycle.Uuid, UserUuid: user1.Uuid, Capacity: 5})
+	assert.Equal(t, acl.ErrReadOnly, err)
+	assert.Equal(t, acl.ErrReadOnly, s.DeleteCapacity(ctx, cycle.Uuid, user1.Uuid))
+	_, err = s.Delete(ctx, cycle.Uuid)
+	assert.Equal(t, acl.ErrReadOnly, err)
+
+	// it can still be read
+	res, err := s.Get(ctx, cycle.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", res.Title)
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "github.com/mirzakhany/pm/protobuf/authz"
+
+// CasbinRule is a policy (p) or role grouping (g, g2) rule of the RBAC model
+type CasbinRule struct {
+	tableName struct{} `pg:"casbin_rules,alias:cr"` //nolint
+	ID        uint64   `pg:",pk"`
+	PType     string   `pg:"ptype"`
+	V0        string   `pg:"v0,use_zero"`
+	V1        string   `pg:"v1,use_zero"`
+	V2        string   `pg:"v2,use_zero"`
+	V3        string   `pg:"v3,use_zero"`
+	V4        string   `pg:"v4,use_zero"`
+	V5        string   `pg:"v5,use_zero"`
+}
+
+// CasbinRuleFromValues returns the rule of the ptype with the given values, extra values are dropped
+func CasbinRuleFromValues(ptype string, values []string) CasbinRule {
+	rule := CasbinRule{PType: ptype}
+	fields := []*string{&rule.V0, &rule.V1, &rule.V2, &rule.V3, &rule.V4, &rule.V5}
+	for i := range values {
+		if i < len(fields) {
+			*fields[i] = values[i]
+		}
+	}
+	return rule
+}
+
+// Values returns the values of the rule without the trailing empty ones
+func (cr CasbinRule) Values() []string {
+	values := []string{cr.V0, cr.V1, cr.V2, cr.V3, cr.V4, cr.V5}
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+func (cr CasbinRule) ToProto() *authz.PolicyRule {
+	return &authz.PolicyRule{
+		Ptype:  cr.PType,
+		Values: cr.Values(),
+	}
+}
+
+func CasbinRuleToProtoList(crl []CasbinRule) []*authz.PolicyRule {
+	var r []*authz.PolicyRule
+	for _, i := range crl {
+		r = append(r, i.ToProto())
+	}
+	return r
+}
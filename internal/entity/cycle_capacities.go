@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mirzakhany/pm/protobuf/cycles"
+)
+
+// CycleCapacity is the availability of a user in a cycle, in points or hours
+type CycleCapacity struct {
+	tableName struct{} `pg:"cycle_capacities,alias:cc"` //nolint
+	ID        uint64   `pg:",pk"`
+	CycleID   uint64   `pg:"unique:cycle_user"`
+	Cycle     *Cycle   `pg:"rel:has-one, fk:cycle"`
+	UserID    uint64   `pg:"unique:cycle_user"`
+	User      *User    `pg:"rel:has-one, fk:user"`
+	Capacity  uint64   `pg:",use_zero"`
+	Unit      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (cc CycleCapacity) ToProto(secure bool) *cycles.CycleCapacity {
+	c, _ := ptypes.TimestampProto(cc.CreatedAt)
+	u, _ := ptypes.TimestampProto(cc.UpdatedAt)
+
+	capacity := &cycles.CycleCapacity{
+		Capacity:  cc.Capacity,
+		Unit:      cc.Unit,
+		CreatedAt: c,
+		UpdatedAt: u,
+	}
+	if cc.Cycle != nil {
+		capacity.CycleUuid = cc.Cycle.UUID
+	}
+	if cc.User != nil {
+		capacity.User = cc.User.ToProto(secure)
+	}
+	return capacity
+}
+
+func CycleCapacityToProtoList(ccl []CycleCapacity, secure bool) []*cycles.CycleCapacity {
+	var c []*cycles.CycleCapacity
+	for _, i := range ccl {
+		c = append(c, i.ToProto(secure))
+	}
+	return c
+}
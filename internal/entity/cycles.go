@@ -14,6 +14,8 @@ type Cycle struct {
 	Title       string
 	Description string
 	Active      bool
+	Goals       []string `pg:",array"`
+	WorkspaceID uint64
 	StartAt     time.Time
 	EndAt       time.Time
 	CreatedAt   time.Time
@@ -33,6 +35,7 @@ func (cm Cycle) ToProto(secure bool) *cycles.Cycle {
 		Title:       cm.Title,
 		Description: cm.Description,
 		Active:      cm.Active,
+		Goals:       cm.Goals,
 		StartAt:     s,
 		EndAt:       e,
 		CreatedAt:   c,
@@ -62,6 +65,7 @@ func CycleFromProto(cycle *cycles.Cycle) Cycle {
 		Title:       cycle.Title,
 		Description: cycle.Description,
 		Active:      cycle.Active,
+		Goals:       cycle.Goals,
 		StartAt:     s,
 		EndAt:       e,
 		CreatedAt:   c,
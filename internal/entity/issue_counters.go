@@ -0,0 +1,9 @@
+package entity
+
+// IssueCounter holds the last number given to an issue of a workspace, its row is locked while a number is taken
+type IssueCounter struct {
+	tableName   struct{} `pg:"issue_counters,alias:ic"` //nolint
+	ID          uint64   `pg:",pk"`
+	WorkspaceID uint64   `pg:",unique"`
+	LastNumber  uint64   `pg:",use_zero"`
+}
@@ -7,13 +7,33 @@ import (
 	"github.com/mirzakhany/pm/protobuf/issues"
 )
 
+// Issue status categories, every status belongs to one of them
+const (
+	StatusCategoryBacklog   = "backlog"
+	StatusCategoryUnstarted = "unstarted"
+	StatusCategoryStarted   = "started"
+	StatusCategoryCompleted = "completed"
+	StatusCategoryCanceled  = "canceled"
+)
+
+// StatusCategories is the list of issue status categories in board order
+var StatusCategories = []string{
+	StatusCategoryBacklog,
+	StatusCategoryUnstarted,
+	StatusCategoryStarted,
+	StatusCategoryCompleted,
+	StatusCategoryCanceled,
+}
+
 type IssueStatus struct {
-	tableName struct{} `pg:"issues_status,alias:ss"` //nolint
-	ID        uint64   `pg:",pk"`
-	UUID      string   `pg:"default:gen_random_uuid()"`
-	Title     string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	tableName   struct{} `pg:"issues_status,alias:ss"` //nolint
+	ID          uint64   `pg:",pk"`
+	UUID        string   `pg:"default:gen_random_uuid()"`
+	Title       string
+	Category    string
+	WorkspaceID uint64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 func (ss IssueStatus) ToProto(secure bool) *issues.IssueStatus {
@@ -24,6 +44,7 @@ func (ss IssueStatus) ToProto(secure bool) *issues.IssueStatus {
 		Id:        ss.ID,
 		Uuid:      ss.UUID,
 		Title:     ss.Title,
+		Category:  ss.Category,
 		CreatedAt: c,
 		UpdatedAt: u,
 	}
@@ -46,6 +67,7 @@ func IssueStatusFromProto(issueStatus *issues.IssueStatus) IssueStatus {
 		ID:        issueStatus.Id,
 		UUID:      issueStatus.Uuid,
 		Title:     issueStatus.Title,
+		Category:  issueStatus.Category,
 		CreatedAt: c,
 		UpdatedAt: u,
 	}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// IssueTransition records a change of the status or the cycle of an issue.
+// A transition to status 0 means the issue was deleted.
+type IssueTransition struct {
+	tableName    struct{} `pg:"issue_transitions,alias:it"` //nolint
+	ID           uint64   `pg:",pk"`
+	IssueID      uint64
+	WorkspaceID  uint64
+	CycleID      uint64
+	FromStatusID uint64
+	ToStatusID   uint64
+	CreatedAt    time.Time
+}
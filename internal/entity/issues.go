@@ -11,19 +11,21 @@ type Issue struct {
 	tableName   struct{} `pg:"issues,alias:i"` //nolint
 	ID          uint64   `pg:",pk"`
 	UUID        string   `pg:"default:gen_random_uuid()"`
+	Number      uint64   `pg:"unique:workspace_number"`
+	Key         string
 	Title       string
 	Description string
-	StatusID    uint64       `pg:"unique:status_id"`
+	StatusID    uint64
 	Status      *IssueStatus `pg:"rel:has-one, fk:status"`
-	CycleID     uint64       `pg:"unique:cycle_id"`
-	Cycle       *Cycle       `pg:"rel:has-one, fk:cycle"`
+	CycleID     uint64
+	Cycle       *Cycle `pg:"rel:has-one, fk:cycle"`
 	Estimate    uint64
-	WorkspaceID uint64    `pg:"unique:workspace_id"`
+	WorkspaceID uint64    `pg:"unique:workspace_number"`
 	Workspace   Workspace `pg:"rel:has-one, fk:workspace"`
-	AssigneeID  uint64    `pg:"unique:assignee_id"`
-	Assignee    *User     `pg:"rel:has-one, fk:assignee"`
-	CreatorID   uint64    `pg:"unique:creator_id"`
-	Creator     *User     `pg:"rel:has-one, fk:creator"`
+	AssigneeID  uint64
+	Assignee    *User `pg:"rel:has-one, fk:assignee"`
+	CreatorID   uint64
+	Creator     *User `pg:"rel:has-one, fk:creator"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -32,21 +34,30 @@ func (im Issue) ToProto(secure bool) *issues.Issue {
 	c, _ := ptypes.TimestampProto(im.CreatedAt)
 	u, _ := ptypes.TimestampProto(im.UpdatedAt)
 
-	status := im.Status.ToProto(false)
-	cycle := &issues.Issue{
+	issue := &issues.Issue{
 		Id:          im.ID,
 		Uuid:        im.UUID,
+		Number:      im.Number,
+		Key:         im.Key,
 		Title:       im.Title,
 		Description: im.Description,
-		Status:      status,
-		Cycle:       im.Cycle.ToProto(secure),
 		Estimate:    im.Estimate,
-		Creator:     im.Creator.ToProto(secure),
-		Assignee:    im.Assignee.ToProto(secure),
 		CreatedAt:   c,
 		UpdatedAt:   u,
 	}
-	return cycle
+	if im.Status != nil {
+		issue.Status = im.Status.ToProto(false)
+	}
+	if im.Cycle != nil {
+		issue.Cycle = im.Cycle.ToProto(secure)
+	}
+	if im.Creator != nil {
+		issue.Creator = im.Creator.ToProto(secure)
+	}
+	if im.Assignee != nil {
+		issue.Assignee = im.Assignee.ToProto(secure)
+	}
+	return issue
 }
 
 func IssueToProtoList(iml []Issue, secure bool) []*issues.Issue {
@@ -68,6 +79,8 @@ func IssueFromProto(issue *issues.Issue) Issue {
 	return Issue{
 		ID:          issue.Id,
 		UUID:        issue.Uuid,
+		Number:      issue.Number,
+		Key:         issue.Key,
 		Title:       issue.Title,
 		Description: issue.Description,
 		CycleID:     cycle.ID,
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/mirzakhany/pm/protobuf/acl"
+)
+
+// Subjects an access control entry can be given to
+const (
+	ACLSubjectUser = "user"
+	ACLSubjectRole = "role"
+)
+
+// Accesses an access control entry can give
+const (
+	ACLAccessView = "view"
+	ACLAccessEdit = "edit"
+)
+
+// ObjectACL is an access control entry of an issue or a cycle.
+// Objects with view entries are visible only to their subjects, and objects with edit entries
+// are editable only by the subjects of these, in addition to the permissions of their roles.
+type ObjectACL struct {
+	tableName   struct{} `pg:"object_acls,alias:oa"` //nolint
+	ID          uint64   `pg:",pk"`
+	WorkspaceID uint64
+	ObjectType  string `pg:"unique:object_subject"`
+	ObjectID    uint64 `pg:"unique:object_subject"`
+	SubjectType string `pg:"unique:object_subject"`
+	SubjectUUID string `pg:"unique:object_subject"`
+	Access      string
+	CreatedAt   time.Time
+}
+
+func (oa ObjectACL) ToProto() *acl.ACLEntry {
+	return &acl.ACLEntry{
+		SubjectType: oa.SubjectType,
+		SubjectUuid: oa.SubjectUUID,
+		Access:      oa.Access,
+	}
+}
+
+func ObjectACLToProtoList(oal []ObjectACL) []*acl.ACLEntry {
+	var r []*acl.ACLEntry
+	for _, i := range oal {
+		r = append(r, i.ToProto())
+	}
+	return r
+}
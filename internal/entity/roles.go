@@ -8,12 +8,14 @@ import (
 )
 
 type Role struct {
-	tableName struct{} `pg:"roles,alias:r"` //nolint
-	ID        uint64   `pg:",pk"`
-	UUID      string   `pg:"default:gen_random_uuid()"`
-	Title     string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	tableName   struct{} `pg:"roles,alias:r"` //nolint
+	ID          uint64   `pg:",pk"`
+	UUID        string   `pg:"default:gen_random_uuid()"`
+	Title       string
+	WorkspaceID uint64
+	Builtin     bool `pg:",use_zero"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 func (rm Role) ToProto() *roles.Role {
@@ -24,6 +26,7 @@ func (rm Role) ToProto() *roles.Role {
 		Id:        rm.ID,
 		Uuid:      rm.UUID,
 		Title:     rm.Title,
+		Builtin:   rm.Builtin,
 		CreatedAt: c,
 		UpdatedAt: u,
 	}
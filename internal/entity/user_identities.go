@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// UserIdentity links a user to their account at an external identity provider,
+// the issuer and the subject identifying the account
+type UserIdentity struct {
+	tableName struct{} `pg:"user_identities,alias:ui"` //nolint
+	ID        uint64   `pg:",pk"`
+	UserID    uint64
+	User      *User  `pg:"rel:has-one, fk:user"`
+	Issuer    string `pg:"unique:issuer_subject"`
+	Subject   string `pg:"unique:issuer_subject"`
+	CreatedAt time.Time
+}
@@ -15,8 +15,15 @@ type User struct {
 	Password  string
 	Email     string `pg:",unique"`
 	Enable    bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	// EmailVerified is set once the user proves they own the email
+	EmailVerified bool
+	// TOTPSecret is the secret of the authenticator app of the user, pending until TOTPEnabled is set
+	TOTPSecret  string
+	TOTPEnabled bool
+	// RecoveryCodes are the hashes of the unused recovery codes
+	RecoveryCodes []string `pg:",array"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 func (um User) ToProto(secure bool) *users.User {
@@ -24,14 +31,16 @@ func (um User) ToProto(secure bool) *users.User {
 	u, _ := ptypes.TimestampProto(um.UpdatedAt)
 
 	user := &users.User{
-		Id:        um.ID,
-		Uuid:      um.UUID,
-		Username:  um.Username,
-		Password:  um.Password,
-		Email:     um.Email,
-		Enable:    um.Enable,
-		CreatedAt: c,
-		UpdatedAt: u,
+		Id:               um.ID,
+		Uuid:             um.UUID,
+		Username:         um.Username,
+		Password:         um.Password,
+		Email:            um.Email,
+		Enable:           um.Enable,
+		EmailVerified:    um.EmailVerified,
+		TwoFactorEnabled: um.TOTPEnabled,
+		CreatedAt:        c,
+		UpdatedAt:        u,
 	}
 	if secure {
 		user.Id = 0
@@ -52,13 +61,15 @@ func UserFromProto(user *users.User) User {
 	c, _ := ptypes.Timestamp(user.CreatedAt)
 	u, _ := ptypes.Timestamp(user.UpdatedAt)
 	return User{
-		ID:        user.Id,
-		UUID:      user.Uuid,
-		Username:  user.Username,
-		Password:  user.Password,
-		Email:     user.Email,
-		Enable:    user.Enable,
-		CreatedAt: c,
-		UpdatedAt: u,
+		ID:            user.Id,
+		UUID:          user.Uuid,
+		Username:      user.Username,
+		Password:      user.Password,
+		Email:         user.Email,
+		Enable:        user.Enable,
+		EmailVerified: user.EmailVerified,
+		TOTPEnabled:   user.TwoFactorEnabled,
+		CreatedAt:     c,
+		UpdatedAt:     u,
 	}
 }
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+// Workspace deletion states
+const (
+	DeletionStateScheduled = "scheduled"
+	DeletionStatePurging   = "purging"
+	DeletionStateCompleted = "completed"
+	DeletionStateRestored  = "restored"
+)
+
+// WorkspaceDeletion tracks a deleted workspace from the soft deletion until its rows are purged
+type WorkspaceDeletion struct {
+	tableName     struct{} `pg:"workspace_deletions,alias:wd"` //nolint
+	ID            uint64   `pg:",pk"`
+	WorkspaceID   uint64   `pg:",unique"`
+	WorkspaceUUID string
+	RequestedByID uint64
+	State         string
+	PurgeAfter    time.Time
+	CurrentTable  string
+	TablesDone    int64 `pg:",use_zero"`
+	TablesTotal   int64 `pg:",use_zero"`
+	DeletedRows   int64 `pg:",use_zero"`
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (wd WorkspaceDeletion) ToProto() *workspaces.WorkspaceDeletion {
+	p, _ := ptypes.TimestampProto(wd.PurgeAfter)
+	c, _ := ptypes.TimestampProto(wd.CreatedAt)
+	u, _ := ptypes.TimestampProto(wd.UpdatedAt)
+
+	deletion := &workspaces.WorkspaceDeletion{
+		WorkspaceUuid: wd.WorkspaceUUID,
+		State:         wd.State,
+		PurgeAfter:    p,
+		CurrentTable:  wd.CurrentTable,
+		TablesDone:    wd.TablesDone,
+		TablesTotal:   wd.TablesTotal,
+		DeletedRows:   wd.DeletedRows,
+		CreatedAt:     c,
+		UpdatedAt:     u,
+	}
+	if !wd.StartedAt.IsZero() {
+		deletion.StartedAt, _ = ptypes.TimestampProto(wd.StartedAt)
+	}
+	if !wd.FinishedAt.IsZero() {
+		deletion.FinishedAt, _ = ptypes.TimestampProto(wd.FinishedAt)
+	}
+	return deletion
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// WorkspaceInvitation records an invitation sent to an email, its token can be accepted once until it expires or is revoked
+type WorkspaceInvitation struct {
+	tableName   struct{} `pg:"workspace_invitations,alias:wi"` //nolint
+	ID          uint64   `pg:",pk"`
+	UUID        string   `pg:",unique"`
+	WorkspaceID uint64
+	Email       string
+	RoleUUID    string
+	InviterID   uint64
+	ExpiresAt   time.Time
+	AcceptedAt  time.Time
+	RevokedAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Pending returns true if the invitation can still be accepted at the time.
+func (wi WorkspaceInvitation) Pending(now time.Time) bool {
+	return wi.AcceptedAt.IsZero() && wi.RevokedAt.IsZero() && now.Before(wi.ExpiresAt)
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+// WorkspaceMember links a user to a workspace with a role
+type WorkspaceMember struct {
+	tableName   struct{}   `pg:"workspace_members,alias:wm"` //nolint
+	ID          uint64     `pg:",pk"`
+	WorkspaceID uint64     `pg:"unique:workspace_user"`
+	Workspace   *Workspace `pg:"rel:has-one, fk:workspace"`
+	UserID      uint64     `pg:"unique:workspace_user"`
+	User        *User      `pg:"rel:has-one, fk:user"`
+	RoleID      uint64
+	Role        *Role `pg:"rel:has-one, fk:role"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (wm WorkspaceMember) ToProto(secure bool) *workspaces.WorkspaceMember {
+	c, _ := ptypes.TimestampProto(wm.CreatedAt)
+	u, _ := ptypes.TimestampProto(wm.UpdatedAt)
+
+	member := &workspaces.WorkspaceMember{
+		CreatedAt: c,
+		UpdatedAt: u,
+	}
+	if wm.Workspace != nil {
+		member.WorkspaceUuid = wm.Workspace.UUID
+	}
+	if wm.User != nil {
+		member.User = wm.User.ToProto(secure)
+	}
+	if wm.Role != nil {
+		member.RoleUuid = wm.Role.UUID
+		member.Role = wm.Role.Title
+	}
+	return member
+}
+
+func WorkspaceMemberToProtoList(wml []WorkspaceMember, secure bool) []*workspaces.WorkspaceMember {
+	var m []*workspaces.WorkspaceMember
+	for _, i := range wml {
+		m = append(m, i.ToProto(secure))
+	}
+	return m
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+// WorkspaceQuota overrides the default limits of a workspace, a limit of 0 means unlimited
+type WorkspaceQuota struct {
+	tableName       struct{} `pg:"workspace_quotas,alias:wq"` //nolint
+	ID              uint64   `pg:",pk"`
+	WorkspaceID     uint64   `pg:",unique"`
+	MaxMembers      int64    `pg:",use_zero"`
+	MaxIssues       int64    `pg:",use_zero"`
+	MaxStorageBytes int64    `pg:",use_zero"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (wq WorkspaceQuota) ToProto() *workspaces.WorkspaceQuotas {
+	return &workspaces.WorkspaceQuotas{
+		MaxMembers:      wq.MaxMembers,
+		MaxIssues:       wq.MaxIssues,
+		MaxStorageBytes: wq.MaxStorageBytes,
+	}
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
+)
+
+// Estimate scales a workspace can estimate issues in
+const (
+	EstimateScalePoints = "points"
+	EstimateScaleHours  = "hours"
+	EstimateScaleTShirt = "tshirt"
+)
+
+// WorkspaceSettings are the preferences shared by all the members of a workspace
+type WorkspaceSettings struct {
+	tableName       struct{}     `pg:"workspace_settings,alias:wst"` //nolint
+	ID              uint64       `pg:",pk"`
+	WorkspaceID     uint64       `pg:",unique"`
+	Workspace       *Workspace   `pg:"rel:has-one, fk:workspace"`
+	DefaultStatusID uint64       `pg:",use_zero"`
+	DefaultStatus   *IssueStatus `pg:"rel:has-one, fk:default_status"`
+	EstimateScale   string
+	TimeZone        string
+	WeekStart       string
+	IssueKeyPrefix  string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// DefaultWorkspaceSettings returns the settings of a workspace that never changed them
+func DefaultWorkspaceSettings(workspaceID uint64) WorkspaceSettings {
+	return WorkspaceSettings{
+		WorkspaceID:    workspaceID,
+		EstimateScale:  EstimateScalePoints,
+		TimeZone:       "UTC",
+		WeekStart:      "monday",
+		IssueKeyPrefix: "ISS",
+	}
+}
+
+// Location returns the time zone of the workspace, UTC if it cannot be loaded
+func (ws WorkspaceSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(ws.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func (ws WorkspaceSettings) ToProto() *workspaces.WorkspaceSettings {
+	c, _ := ptypes.TimestampProto(ws.CreatedAt)
+	u, _ := ptypes.TimestampProto(ws.UpdatedAt)
+
+	settings := &workspaces.WorkspaceSettings{
+		EstimateScale:  ws.EstimateScale,
+		TimeZone:       ws.TimeZone,
+		WeekStart:      ws.WeekStart,
+		IssueKeyPrefix: ws.IssueKeyPrefix,
+		CreatedAt:      c,
+		UpdatedAt:      u,
+	}
+	if ws.Workspace != nil {
+		settings.WorkspaceUuid = ws.Workspace.UUID
+	}
+	if ws.DefaultStatus != nil {
+		settings.DefaultStatusUuid = ws.DefaultStatus.UUID
+	}
+	return settings
+}
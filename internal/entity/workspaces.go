@@ -15,6 +15,7 @@ type Workspace struct {
 	Domain    string `pg:",unique"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt time.Time `pg:",soft_delete"`
 }
 
 func (rm Workspace) ToProto() *workspaces.Workspace {
@@ -25,7 +26,7 @@ func (rm Workspace) ToProto() *workspaces.Workspace {
 		Id:        rm.ID,
 		Uuid:      rm.UUID,
 		Title:     rm.Title,
-		Domain:    rm.Title,
+		Domain:    rm.Domain,
 		CreatedAt: c,
 		UpdatedAt: u,
 	}
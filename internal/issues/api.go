@@ -2,12 +2,17 @@ package issues
 
 import (
 	"context"
+	"errors"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/internal/acl"
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/grpcgw"
 	"github.com/mirzakhany/pm/protobuf/issues"
+	"google.golang.org/genproto/googleapis/api/httpbody"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -51,6 +56,9 @@ func (a api) GetIssue(ctx context.Context, request *issues.GetIssueRequest) (*is
 func (a api) CreateIssue(ctx context.Context, request *issues.CreateIssueRequest) (*issues.Issue, error) {
 	res, err := a.service.Create(ctx, request)
 	if err != nil {
+		if errors.Is(err, workspaces.ErrQuotaExceeded) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	return res, err
@@ -59,6 +67,9 @@ func (a api) CreateIssue(ctx context.Context, request *issues.CreateIssueRequest
 func (a api) UpdateIssue(ctx context.Context, request *issues.UpdateIssueRequest) (*issues.Issue, error) {
 	res, err := a.service.Update(ctx, request)
 	if err != nil {
+		if errors.Is(err, acl.ErrReadOnly) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	return res, err
@@ -67,6 +78,9 @@ func (a api) UpdateIssue(ctx context.Context, request *issues.UpdateIssueRequest
 func (a api) DeleteIssue(ctx context.Context, request *issues.DeleteIssueRequest) (*empty.Empty, error) {
 	_, err := a.service.Delete(ctx, request.Uuid)
 	if err != nil {
+		if errors.Is(err, acl.ErrReadOnly) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	return nil, err
@@ -114,7 +128,40 @@ func (a api) DeleteIssueStatus(ctx context.Context, request *issues.DeleteIssueS
 }
 
 func (a api) SetIssueStatus(ctx context.Context, request *issues.SetIssueStatusRequest) (*issues.Issue, error) {
-	panic("implement me")
+	if request.Status == nil {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+	res, err := a.service.SetStatus(ctx, request.Uuid, request.Status.Uuid)
+	if err != nil {
+		if errors.Is(err, acl.ErrReadOnly) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) GetCumulativeFlow(ctx context.Context, request *issues.GetCumulativeFlowRequest) (*issues.GetCumulativeFlowResponse, error) {
+	res, err := a.service.CumulativeFlow(ctx, request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, err
+}
+
+func (a api) ExportCycleReport(ctx context.Context, request *issues.ExportCycleReportRequest) (*httpbody.HttpBody, error) {
+	report, err := a.service.CycleReport(ctx, request.CycleUuid)
+	if err != nil {
+		if err == errCycleNotCompleted {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	contentType, data, err := RenderCycleReport(report, request.Format)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &httpbody.HttpBody{ContentType: contentType, Data: data}, nil
 }
 
 func New(srv Service) API {
@@ -122,3 +169,21 @@ func New(srv Service) API {
 	grpcgw.RegisterController(s)
 	return s
 }
+
+func init() {
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/issuesV1.IssueService/ListIssues":        auth.Require("issues", "list"),
+		"/issuesV1.IssueService/GetIssue":          auth.Require("issues", "get"),
+		"/issuesV1.IssueService/CreateIssue":       auth.Require("issues", "create"),
+		"/issuesV1.IssueService/UpdateIssue":       auth.Require("issues", "update"),
+		"/issuesV1.IssueService/DeleteIssue":       auth.Require("issues", "delete"),
+		"/issuesV1.IssueService/SetIssueStatus":    auth.Require("issues", "update"),
+		"/issuesV1.IssueService/ListIssueStatus":   auth.Require("statuses", "list"),
+		"/issuesV1.IssueService/GetIssueStatus":    auth.Require("statuses", "get"),
+		"/issuesV1.IssueService/CreateIssueStatus": auth.Require("statuses", "create"),
+		"/issuesV1.IssueService/UpdateIssueStatus": auth.Require("statuses", "update"),
+		"/issuesV1.IssueService/DeleteIssueStatus": auth.Require("statuses", "delete"),
+		"/issuesV1.IssueService/GetCumulativeFlow": auth.Require("reports", "get"),
+		"/issuesV1.IssueService/ExportCycleReport": auth.Require("reports", "export"),
+	})
+}
@@ -0,0 +1,171 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mirzakhany/pm/internal/entity"
+	issuesProto "github.com/mirzakhany/pm/protobuf/issues"
+)
+
+const (
+	// defaultFlowDays is the number of days returned when the request has no range
+	defaultFlowDays = 14
+	// maxFlowDays is the longest range the cumulative flow can be computed for
+	maxFlowDays = 366
+)
+
+var errInvalidFlowRange = errors.New("invalid date range, from must be before to and at most 366 days apart")
+
+// CumulativeFlow returns the number of issues in each status category at the end of every day in the
+// requested range. The counts are computed by replaying the status transitions of the issues.
+func (s service) CumulativeFlow(ctx context.Context, req *issuesProto.GetCumulativeFlowRequest) (*issuesProto.GetCumulativeFlowResponse, error) {
+	loc, err := s.location(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := flowRange(req, time.Now(), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var cycleID uint64
+	if req.CycleUuid != "" {
+		cycle, err := s.cyclesSrv.Get(ctx, req.CycleUuid)
+		if err != nil {
+			return nil, err
+		}
+		cycleID = cycle.Id
+	}
+
+	categories, err := s.statusCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.repo.Transitions(ctx, cycleID, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuesProto.GetCumulativeFlowResponse{
+		Categories: entity.StatusCategories,
+		Days:       cumulativeFlow(transitions, categories, cycleID, from, to),
+	}, nil
+}
+
+// statusCategories returns the category of every status by the status ID
+func (s service) statusCategories(ctx context.Context) (map[uint64]string, error) {
+	statuses, err := s.repo.AllStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[uint64]string, len(statuses))
+	for _, st := range statuses {
+		categories[st.ID] = st.Category
+	}
+	return categories, nil
+}
+
+// location returns the time zone days are counted in, as set in the workspace settings
+func (s service) location(ctx context.Context) (*time.Location, error) {
+	settings, err := s.workspacesSrv.CurrentSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// flowRange returns the first and the last day of the requested range, truncated to the start of the day in loc.
+func flowRange(req *issuesProto.GetCumulativeFlowRequest, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	to := now
+	if req.To != nil {
+		t, err := ptypes.Timestamp(req.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = t
+	}
+	to = startOfDay(to, loc)
+
+	from := to.AddDate(0, 0, -(defaultFlowDays - 1))
+	if req.From != nil {
+		f, err := ptypes.Timestamp(req.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = startOfDay(f, loc)
+	}
+
+	if from.After(to) || to.Sub(from) > maxFlowDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errInvalidFlowRange
+	}
+	return from, to, nil
+}
+
+// startOfDay returns the midnight of the day of t in loc
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// issueState is the status and the cycle of an issue at a point in time
+type issueState struct {
+	statusID uint64
+	cycleID  uint64
+}
+
+// replay applies the transitions, which must be ordered by creation time, day by day from the first
+// to the last day and calls fn with the state of the issues at the end of every day.
+func replay(transitions []entity.IssueTransition, from, to time.Time, fn func(day time.Time, states map[uint64]issueState)) {
+	states := make(map[uint64]issueState)
+	next := 0
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		for ; next < len(transitions) && transitions[next].CreatedAt.Before(end); next++ {
+			t := transitions[next]
+			if t.ToStatusID == 0 {
+				delete(states, t.IssueID)
+				continue
+			}
+			states[t.IssueID] = issueState{statusID: t.ToStatusID, cycleID: t.CycleID}
+		}
+		fn(day, states)
+	}
+}
+
+// cumulativeFlow counts the issues in each status category at the end of every day from the first
+// to the last day. If cycleID is not zero only the issues that are in that cycle at the end of the
+// day are counted.
+func cumulativeFlow(transitions []entity.IssueTransition, categories map[uint64]string, cycleID uint64, from, to time.Time) []*issuesProto.CumulativeFlowDay {
+	var days []*issuesProto.CumulativeFlowDay
+	replay(transitions, from, to, func(day time.Time, states map[uint64]issueState) {
+		counts := make(map[string]int64, len(entity.StatusCategories))
+		for _, state := range states {
+			if cycleID != 0 && state.cycleID != cycleID {
+				continue
+			}
+			if category, ok := categories[state.statusID]; ok {
+				counts[category]++
+			}
+		}
+
+		date, _ := ptypes.TimestampProto(day)
+		item := &issuesProto.CumulativeFlowDay{Date: date}
+		for _, category := range entity.StatusCategories {
+			item.Counts = append(item.Counts, &issuesProto.StatusCategoryCount{
+				Category: category,
+				Count:    counts[category],
+			})
+		}
+		days = append(days, item)
+	})
+	return days
+}
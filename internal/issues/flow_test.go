@@ -0,0 +1,91 @@
+package issues
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mirzakhany/pm/internal/entity"
+	issues "github.com/mirzakhany/pm/protobuf/issues"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestFlowRange(t *testing.T) {
+	now := time.Date(2020, 10, 20, 15, 4, 5, 0, time.UTC)
+
+	from, to, err := flowRange(&issues.GetCumulativeFlowRequest{}, now, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 10, 20, 0, 0, 0, 0, time.UTC), to)
+	assert.Equal(t, time.Date(2020, 10, 7, 0, 0, 0, 0, time.UTC), from)
+
+	from, to, err = flowRange(&issues.GetCumulativeFlowRequest{
+		From: timestamppb.New(time.Date(2020, 10, 1, 23, 0, 0, 0, time.UTC)),
+		To:   timestamppb.New(time.Date(2020, 10, 3, 1, 0, 0, 0, time.UTC)),
+	}, now, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2020, 10, 3, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = flowRange(&issues.GetCumulativeFlowRequest{
+		From: timestamppb.New(time.Date(2020, 10, 4, 0, 0, 0, 0, time.UTC)),
+		To:   timestamppb.New(time.Date(2020, 10, 3, 0, 0, 0, 0, time.UTC)),
+	}, now, time.UTC)
+	assert.Equal(t, errInvalidFlowRange, err)
+
+	_, _, err = flowRange(&issues.GetCumulativeFlowRequest{
+		From: timestamppb.New(time.Date(2018, 10, 4, 0, 0, 0, 0, time.UTC)),
+	}, now, time.UTC)
+	assert.Equal(t, errInvalidFlowRange, err)
+}
+
+func TestCumulativeFlow(t *testing.T) {
+	day := func(d, h int) time.Time {
+		return time.Date(2020, 10, d, h, 0, 0, 0, time.UTC)
+	}
+	categories := map[uint64]string{
+		1: entity.StatusCategoryUnstarted,
+		2: entity.StatusCategoryStarted,
+		3: entity.StatusCategoryCompleted,
+	}
+	transitions := []entity.IssueTransition{
+		{IssueID: 1, CycleID: 1, FromStatusID: 0, ToStatusID: 1, CreatedAt: day(1, 9)},
+		{IssueID: 2, CycleID: 1, FromStatusID: 0, ToStatusID: 1, CreatedAt: day(1, 10)},
+		{IssueID: 3, CycleID: 2, FromStatusID: 0, ToStatusID: 1, CreatedAt: day(1, 11)},
+		{IssueID: 1, CycleID: 1, FromStatusID: 1, ToStatusID: 2, CreatedAt: day(2, 9)},
+		{IssueID: 1, CycleID: 1, FromStatusID: 2, ToStatusID: 3, CreatedAt: day(3, 9)},
+		{IssueID: 2, CycleID: 1, FromStatusID: 1, ToStatusID: 0, CreatedAt: day(3, 10)},
+		{IssueID: 3, CycleID: 1, FromStatusID: 1, ToStatusID: 1, CreatedAt: day(3, 11)},
+	}
+
+	count := func(d *issues.CumulativeFlowDay, category string) int64 {
+		for _, c := range d.Counts {
+			if c.Category == category {
+				return c.Count
+			}
+		}
+		return -1
+	}
+
+	// the whole workspace
+	days := cumulativeFlow(transitions, categories, 0, day(1, 0), day(3, 0))
+	assert.Len(t, days, 3)
+	assert.Len(t, days[0].Counts, len(entity.StatusCategories))
+	assert.Equal(t, int64(3), count(days[0], entity.StatusCategoryUnstarted))
+	assert.Equal(t, int64(2), count(days[1], entity.StatusCategoryUnstarted))
+	assert.Equal(t, int64(1), count(days[1], entity.StatusCategoryStarted))
+	assert.Equal(t, int64(1), count(days[2], entity.StatusCategoryUnstarted))
+	assert.Equal(t, int64(0), count(days[2], entity.StatusCategoryStarted))
+	assert.Equal(t, int64(1), count(days[2], entity.StatusCategoryCompleted))
+
+	// a single cycle, issue 3 moves into it on the last day
+	days = cumulativeFlow(transitions, categories, 1, day(1, 0), day(3, 0))
+	assert.Equal(t, int64(2), count(days[0], entity.StatusCategoryUnstarted))
+	assert.Equal(t, int64(1), count(days[2], entity.StatusCategoryUnstarted))
+	assert.Equal(t, int64(1), count(days[2], entity.StatusCategoryCompleted))
+
+	// days before the first transition are empty
+	days = cumulativeFlow(transitions, categories, 0, day(30, 0).AddDate(0, -1, 0), day(1, 0))
+	assert.Len(t, days, 2)
+	assert.Equal(t, int64(0), count(days[0], entity.StatusCategoryUnstarted))
+	assert.Equal(t, int64(3), count(days[1], entity.StatusCategoryUnstarted))
+}
@@ -0,0 +1,325 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mirzakhany/pm/internal/entity"
+)
+
+const (
+	// ReportFormatCSV renders the cycle report as comma separated values
+	ReportFormatCSV = "csv"
+	// ReportFormatHTML renders the cycle report as a standalone html page
+	ReportFormatHTML = "html"
+)
+
+var (
+	errCycleNotCompleted = errors.New("cycle is not completed yet")
+	errReportFormat      = errors.New("report format must be csv or html")
+)
+
+// CycleReport is the summary of a completed cycle
+type CycleReport struct {
+	Cycle       entity.Cycle
+	Completed   []entity.Issue
+	CarriedOver []entity.Issue
+	Burndown    []BurndownDay
+	Assignees   []AssigneeTotal
+	GeneratedAt time.Time
+}
+
+// BurndownDay is the estimate left in the cycle at the end of a day
+type BurndownDay struct {
+	Date      time.Time
+	Remaining uint64
+	Ideal     float64
+}
+
+// AssigneeTotal is the work assigned to and completed by a user in the cycle
+type AssigneeTotal struct {
+	Assignee          string
+	Issues            int
+	CompletedIssues   int
+	Estimate          uint64
+	CompletedEstimate uint64
+}
+
+// CycleReport builds the report of the completed cycle with the specified UUID.
+func (s service) CycleReport(ctx context.Context, cycleUUID string) (*CycleReport, error) {
+	cycle, err := s.cyclesSrv.Get(ctx, cycleUUID)
+	if err != nil {
+		return nil, err
+	}
+	cycleModel := entity.CycleFromProto(cycle)
+	now := time.Now()
+	if cycleModel.Active && cycleModel.EndAt.After(now) {
+		return nil, errCycleNotCompleted
+	}
+
+	items, err := s.repo.CycleIssues(ctx, cycleModel.ID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.statusCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.location(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from := startOfDay(cycleModel.StartAt, loc)
+	to := startOfDay(cycleModel.EndAt, loc)
+	if to.Sub(from) > maxFlowDays*24*time.Hour {
+		return nil, errInvalidFlowRange
+	}
+	transitions, err := s.repo.Transitions(ctx, cycleModel.ID, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildCycleReport(cycleModel, items, categories)
+	report.Burndown = burndown(transitions, items, categories, cycleModel.ID, from, to)
+	report.GeneratedAt = now
+	return report, nil
+}
+
+// buildCycleReport splits the issues of the cycle into completed and carried-over ones and sums
+// them per assignee. Canceled issues are neither completed nor carried over.
+func buildCycleReport(cycle entity.Cycle, items []entity.Issue, categories map[uint64]string) *CycleReport {
+	report := &CycleReport{Cycle: cycle}
+	totals := make(map[string]*AssigneeTotal)
+
+	for _, item := range items {
+		category := categories[item.StatusID]
+		if category == entity.StatusCategoryCanceled {
+			continue
+		}
+		done := category == entity.StatusCategoryCompleted
+		if done {
+			report.Completed = append(report.Completed, item)
+		} else {
+			report.CarriedOver = append(report.CarriedOver, item)
+		}
+
+		name := assigneeName(item)
+		total, ok := totals[name]
+		if !ok {
+			total = &AssigneeTotal{Assignee: name}
+			totals[name] = total
+		}
+		total.Issues++
+		total.Estimate += item.Estimate
+		if done {
+			total.CompletedIssues++
+			total.CompletedEstimate += item.Estimate
+		}
+	}
+
+	for _, total := range totals {
+		report.Assignees = append(report.Assignees, *total)
+	}
+	sort.Slice(report.Assignees, func(i, j int) bool {
+		return report.Assignees[i].Assignee < report.Assignees[j].Assignee
+	})
+	return report
+}
+
+// burndown returns the estimate of the open issues in the cycle at the end of every day of the cycle
+// next to the ideal line going from the estimate of the first day down to zero.
+func burndown(transitions []entity.IssueTransition, items []entity.Issue, categories map[uint64]string, cycleID uint64, from, to time.Time) []BurndownDay {
+	estimates := make(map[uint64]uint64, len(items))
+	for _, item := range items {
+		estimates[item.ID] = item.Estimate
+	}
+
+	var days []BurndownDay
+	replay(transitions, from, to, func(day time.Time, states map[uint64]issueState) {
+		var remaining uint64
+		for issueID, state := range states {
+			if state.cycleID != cycleID {
+				continue
+			}
+			switch categories[state.statusID] {
+			case entity.StatusCategoryCompleted, entity.StatusCategoryCanceled:
+			default:
+				remaining += estimates[issueID]
+			}
+		}
+		days = append(days, BurndownDay{Date: day, Remaining: remaining})
+	})
+
+	if len(days) == 0 {
+		return days
+	}
+	start := float64(days[0].Remaining)
+	last := len(days) - 1
+	for i := range days {
+		if last == 0 {
+			days[i].Ideal = 0
+			continue
+		}
+		days[i].Ideal = start * float64(last-i) / float64(last)
+	}
+	return days
+}
+
+// assigneeName returns the username of the issue assignee
+func assigneeName(issue entity.Issue) string {
+	if issue.Assignee == nil || issue.Assignee.Username == "" {
+		return "unassigned"
+	}
+	return issue.Assignee.Username
+}
+
+// statusTitle returns the title of the issue status
+func statusTitle(issue entity.Issue) string {
+	if issue.Status == nil {
+		return ""
+	}
+	return issue.Status.Title
+}
+
+// RenderCycleReport writes the report in the given format and returns its content type.
+func RenderCycleReport(report *CycleReport, format string) (string, []byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case ReportFormatCSV:
+		if err := renderReportCSV(&buf, report); err != nil {
+			return "", nil, err
+		}
+		return "text/csv; charset=utf-8", buf.Bytes(), nil
+	case ReportFormatHTML, "":
+		if err := reportTmpl.Execute(&buf, report); err != nil {
+			return "", nil, err
+		}
+		return "text/html; charset=utf-8", buf.Bytes(), nil
+	default:
+		return "", nil, errReportFormat
+	}
+}
+
+// renderReportCSV writes the report as csv, one section after another separated by an empty line
+func renderReportCSV(w io.Writer, report *CycleReport) error {
+	const dateLayout = "2006-01-02"
+	cw := csv.NewWriter(w)
+	cycle := report.Cycle
+
+	records := [][]string{
+		{"cycle", cycle.Title},
+		{"description", cycle.Description},
+		{"start", cycle.StartAt.Format(dateLayout)},
+		{"end", cycle.EndAt.Format(dateLayout)},
+		{},
+		{"goals"},
+	}
+	for _, goal := range cycle.Goals {
+		records = append(records, []string{goal})
+	}
+
+	issueSection := func(title string, items []entity.Issue) {
+		records = append(records, []string{}, []string{title}, []string{"uuid", "title", "status", "assignee", "estimate"})
+		for _, item := range items {
+			records = append(records, []string{
+				item.UUID, item.Title, statusTitle(item), assigneeName(item), strconv.FormatUint(item.Estimate, 10),
+			})
+		}
+	}
+	issueSection("completed issues", report.Completed)
+	issueSection("carried-over issues", report.CarriedOver)
+
+	records = append(records, []string{}, []string{"burndown"}, []string{"date", "remaining", "ideal"})
+	for _, day := range report.Burndown {
+		records = append(records, []string{
+			day.Date.Format(dateLayout), strconv.FormatUint(day.Remaining, 10), fmt.Sprintf("%.1f", day.Ideal),
+		})
+	}
+
+	records = append(records, []string{}, []string{"assignees"},
+		[]string{"assignee", "issues", "completed issues", "estimate", "completed estimate"})
+	for _, total := range report.Assignees {
+		records = append(records, []string{
+			total.Assignee,
+			strconv.Itoa(total.Issues),
+			strconv.Itoa(total.CompletedIssues),
+			strconv.FormatUint(total.Estimate, 10),
+			strconv.FormatUint(total.CompletedEstimate, 10),
+		})
+	}
+	for _, record := range records {
+		for i := range record {
+			record[i] = csvSafe(record[i])
+		}
+	}
+	return cw.WriteAll(records)
+}
+
+// csvSafe prefixes the values spreadsheets would read as a formula with a quote, so they are shown as text.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+var reportTmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"status":   statusTitle,
+	"assignee": assigneeName,
+	"ideal":    func(f float64) string { return fmt.Sprintf("%.1f", f) },
+}).Parse(reportHTML))
+
+const reportHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Cycle.Title }} report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f3f3f3; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>{{ .Cycle.Title }}</h1>
+<p class="muted">{{ date .Cycle.StartAt }} &ndash; {{ date .Cycle.EndAt }}, generated at {{ date .GeneratedAt }}</p>
+{{ with .Cycle.Description }}<p>{{ . }}</p>{{ end }}
+
+<h2>Goals</h2>
+{{ if .Cycle.Goals }}<ul>{{ range .Cycle.Goals }}<li>{{ . }}</li>{{ end }}</ul>{{ else }}<p class="muted">No goals</p>{{ end }}
+
+<h2>Completed issues</h2>
+{{ template "issues" .Completed }}
+
+<h2>Carried-over issues</h2>
+{{ template "issues" .CarriedOver }}
+
+<h2>Burndown</h2>
+<table>
+<tr><th>Date</th><th>Remaining</th><th>Ideal</th></tr>
+{{ range .Burndown }}<tr><td>{{ date .Date }}</td><td>{{ .Remaining }}</td><td>{{ ideal .Ideal }}</td></tr>
+{{ end }}</table>
+
+<h2>Assignees</h2>
+<table>
+<tr><th>Assignee</th><th>Issues</th><th>Completed issues</th><th>Estimate</th><th>Completed estimate</th></tr>
+{{ range .Assignees }}<tr><td>{{ .Assignee }}</td><td>{{ .Issues }}</td><td>{{ .CompletedIssues }}</td><td>{{ .Estimate }}</td><td>{{ .CompletedEstimate }}</td></tr>
+{{ end }}</table>
+</body>
+</html>
+{{ define "issues" }}{{ if . }}<table>
+<tr><th>Title</th><th>Status</th><th>Assignee</th><th>Estimate</th></tr>
+{{ range . }}<tr><td>{{ .Title }}</td><td>{{ status . }}</td><td>{{ assignee . }}</td><td>{{ .Estimate }}</td></tr>
+{{ end }}</table>{{ else }}<p class="muted">No issues</p>{{ end }}{{ end }}`
@@ -0,0 +1,92 @@
+package issues
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mirzakhany/pm/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() *CycleReport {
+	day := func(d, h int) time.Time {
+		return time.Date(2020, 10, d, h, 0, 0, 0, time.UTC)
+	}
+	categories := map[uint64]string{
+		1: entity.StatusCategoryUnstarted,
+		2: entity.StatusCategoryCompleted,
+		3: entity.StatusCategoryCanceled,
+	}
+	alice := &entity.User{Username: "alice"}
+	bob := &entity.User{Username: "bob"}
+	cycle := entity.Cycle{
+		ID: 1, Title: "cycle <1>", StartAt: day(1, 0), EndAt: day(3, 0), Goals: []string{"ship reports"},
+	}
+	items := []entity.Issue{
+		{ID: 1, UUID: "i1", Title: "done", StatusID: 2, CycleID: 1, Estimate: 3, Assignee: alice},
+		{ID: 2, UUID: "i2", Title: "open", StatusID: 1, CycleID: 1, Estimate: 2, Assignee: bob},
+		{ID: 3, UUID: "i3", Title: "dropped", StatusID: 3, CycleID: 1, Estimate: 5, Assignee: alice},
+		{ID: 4, UUID: "i4", Title: "nobody", StatusID: 1, CycleID: 1, Estimate: 1},
+	}
+	transitions := []entity.IssueTransition{
+		{IssueID: 1, CycleID: 1, ToStatusID: 1, CreatedAt: day(1, 9)},
+		{IssueID: 2, CycleID: 1, ToStatusID: 1, CreatedAt: day(1, 9)},
+		{IssueID: 3, CycleID: 1, ToStatusID: 1, CreatedAt: day(1, 9)},
+		{IssueID: 4, CycleID: 1, ToStatusID: 1, CreatedAt: day(1, 9)},
+		{IssueID: 3, CycleID: 1, FromStatusID: 1, ToStatusID: 3, CreatedAt: day(2, 9)},
+		{IssueID: 1, CycleID: 1, FromStatusID: 1, ToStatusID: 2, CreatedAt: day(3, 9)},
+	}
+
+	report := buildCycleReport(cycle, items, categories)
+	report.Burndown = burndown(transitions, items, categories, cycle.ID, day(1, 0), day(3, 0))
+	report.GeneratedAt = day(4, 0)
+	return report
+}
+
+func TestCycleReport(t *testing.T) {
+	report := testReport()
+
+	assert.Len(t, report.Completed, 1)
+	assert.Equal(t, "i1", report.Completed[0].UUID)
+	assert.Len(t, report.CarriedOver, 2)
+
+	assert.Equal(t, []AssigneeTotal{
+		{Assignee: "alice", Issues: 1, CompletedIssues: 1, Estimate: 3, CompletedEstimate: 3},
+		{Assignee: "bob", Issues: 1, Estimate: 2},
+		{Assignee: "unassigned", Issues: 1, Estimate: 1},
+	}, report.Assignees)
+
+	assert.Len(t, report.Burndown, 3)
+	assert.Equal(t, uint64(11), report.Burndown[0].Remaining)
+	assert.Equal(t, uint64(6), report.Burndown[1].Remaining)
+	assert.Equal(t, uint64(3), report.Burndown[2].Remaining)
+	assert.Equal(t, 11.0, report.Burndown[0].Ideal)
+	assert.Equal(t, 5.5, report.Burndown[1].Ideal)
+	assert.Equal(t, 0.0, report.Burndown[2].Ideal)
+}
+
+func TestRenderCycleReport(t *testing.T) {
+	report := testReport()
+	report.Cycle.Goals = append(report.Cycle.Goals, "=HYPERLINK(\"http://example.com\")")
+
+	contentType, data, err := RenderCycleReport(report, ReportFormatCSV)
+	assert.Nil(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", contentType)
+	assert.Contains(t, string(data), "i1,done,,alice,3\n")
+	assert.Contains(t, string(data), "2020-10-02,6,5.5\n")
+	assert.Contains(t, string(data), "unassigned,1,0,1,0\n")
+	// values read as formulas by spreadsheets are prefixed
+	assert.Contains(t, string(data), "\"'=HYPERLINK(\"\"http://example.com\"\")\"\n")
+
+	contentType, data, err = RenderCycleReport(report, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", contentType)
+	assert.True(t, strings.HasPrefix(string(data), "<!DOCTYPE html>"))
+	assert.Contains(t, string(data), "<h1>cycle &lt;1&gt;</h1>")
+	assert.Contains(t, string(data), "<li>ship reports</li>")
+	assert.Contains(t, string(data), "<li>=HYPERLINK(&#34;http://example.com&#34;)</li>")
+
+	_, _, err = RenderCycleReport(report, "pdf")
+	assert.Equal(t, errReportFormat, err)
+}
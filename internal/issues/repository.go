@@ -2,9 +2,14 @@ package issues
 
 import (
 	"context"
+	"time"
 
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/mirzakhany/pm/internal/acl"
 	"github.com/mirzakhany/pm/internal/entity"
 
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/db"
 )
 
@@ -22,6 +27,15 @@ type Repository interface {
 	Update(ctx context.Context, issue entity.Issue) error
 	// Delete removes the issue with given UUID from the storage.
 	Delete(ctx context.Context, uuid string) error
+	// Editable returns true if the access control list of the issue with the given ID lets the current user edit it.
+	Editable(ctx context.Context, id uint64) (bool, error)
+	// NextNumber takes the number of the next issue created in the current workspace,
+	// concurrent transactions wait for each other and get different numbers.
+	NextNumber(ctx context.Context) (uint64, error)
+	// Transactional runs f in a transaction.
+	Transactional(ctx context.Context, f func(ctx context.Context) error) error
+	// CycleIssues returns the issues of the cycle with the given ID.
+	CycleIssues(ctx context.Context, cycleID uint64) ([]entity.Issue, error)
 
 	//IssueStatus
 
@@ -37,6 +51,16 @@ type Repository interface {
 	UpdateStatus(ctx context.Context, issueStatus entity.IssueStatus) error
 	// DeleteStatus removes the status with given UUID from the storage.
 	DeleteStatus(ctx context.Context, uuid string) error
+	// AllStatus returns all the status.
+	AllStatus(ctx context.Context) ([]entity.IssueStatus, error)
+
+	// IssueTransition
+
+	// AddTransition saves a new status or cycle transition of an issue in the storage.
+	AddTransition(ctx context.Context, transition entity.IssueTransition) error
+	// Transitions returns the transitions created before until, ordered by creation time.
+	// If cycleID is not zero only the transitions of issues that have been in that cycle are returned.
+	Transitions(ctx context.Context, cycleID uint64, until time.Time) ([]entity.IssueTransition, error)
 }
 
 // repository persists issues in database
@@ -49,27 +73,45 @@ func NewRepository(db *db.DB) Repository {
 	return repository{db}
 }
 
-// Get reads the issue with the specified ID from the database.
+// Get reads the issue with the specified UUID in the current workspace from the database.
 func (r repository) Get(ctx context.Context, uuid string) (entity.Issue, error) {
 	var issue entity.Issue
-	err := r.db.With(ctx).Model(&issue).
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return issue, err
+	}
+	err = r.db.With(ctx).Model(&issue).
+		Relation("Status").
 		Relation("Assignee").
 		Relation("Creator").
 		Relation("Cycle").
-		Where("i.uuid = ?", uuid).First()
+		Where("i.uuid = ?", uuid).
+		Where("i.workspace_id = ?", workspace.Id).
+		Apply(visible(ctx)).
+		First()
 
 	return issue, err
 }
 
-// Create saves a new issue record in the database.
+// Create saves a new issue record in the current workspace.
 func (r repository) Create(ctx context.Context, issue entity.Issue) error {
-	_, err := r.db.With(ctx).Model(&issue).Insert()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	issue.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&issue).Insert()
 	return err
 }
 
 // Update saves the changes to an issue in the database.
 func (r repository) Update(ctx context.Context, issue entity.Issue) error {
-	_, err := r.db.With(ctx).Model(&issue).WherePK().Update()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	issue.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&issue).WherePK().Where("workspace_id = ?", workspace.Id).Update()
 	return err
 }
 
@@ -79,24 +121,52 @@ func (r repository) Delete(ctx context.Context, uuid string) error {
 	if err != nil {
 		return err
 	}
+	if err := acl.Remove(r.db.With(ctx), acl.ObjectIssues, issue.ID); err != nil {
+		return err
+	}
 	_, err = r.db.With(ctx).Model(&issue).WherePK().Delete()
 	return err
 }
 
-// Count returns the number of the issue records in the database.
+// Editable checks the access control list of the issue with the specified ID for the current user.
+func (r repository) Editable(ctx context.Context, id uint64) (bool, error) {
+	return acl.Editable(ctx, r.db.With(ctx), acl.ObjectIssues, id)
+}
+
+// visible filters the issues of a query to the ones the current user can view.
+func visible(ctx context.Context) func(q *orm.Query) (*orm.Query, error) {
+	return func(q *orm.Query) (*orm.Query, error) {
+		return acl.Visible(ctx, q, acl.ObjectIssues, "i.id"), nil
+	}
+}
+
+// Count returns the number of the issue records in the current workspace.
 func (r repository) Count(ctx context.Context) (int64, error) {
-	var count int
-	count, err := r.db.With(ctx).Model((*entity.Issue)(nil)).Count()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count, err := r.db.With(ctx).Model((*entity.Issue)(nil)).
+		Where("i.workspace_id = ?", workspace.Id).
+		Apply(visible(ctx)).
+		Count()
 	return int64(count), err
 }
 
-// Query retrieves the issue records with the specified offset and limit from the database.
+// Query retrieves the issue records of the current workspace with the specified offset and limit from the database.
 func (r repository) Query(ctx context.Context, offset, limit int64) ([]entity.Issue, int, error) {
 	var _issues []entity.Issue
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
 	count, err := r.db.With(ctx).Model(&_issues).
+		Relation("Status").
 		Relation("Assignee").
 		Relation("Creator").
 		Relation("Cycle").
+		Where("i.workspace_id = ?", workspace.Id).
+		Apply(visible(ctx)).
 		Order("id ASC").
 		Limit(int(limit)).
 		Offset(int(offset)).
@@ -104,43 +174,149 @@ func (r repository) Query(ctx context.Context, offset, limit int64) ([]entity.Is
 	return _issues, count, err
 }
 
+// NextNumber increments the issue counter of the current workspace and returns it.
+// The counter row stays locked until the transaction of the request ends, the first one starts after the highest issue number.
+func (r repository) NextNumber(ctx context.Context) (uint64, error) {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var next uint64
+	_, err = r.db.With(ctx).QueryOne(pg.Scan(&next), `INSERT INTO issue_counters (workspace_id, last_number)
+		VALUES (?0, (SELECT coalesce(max(number), 0) + 1 FROM issues WHERE workspace_id = ?0))
+		ON CONFLICT (workspace_id) DO UPDATE SET last_number = issue_counters.last_number + 1
+		RETURNING last_number`, workspace.Id)
+	return next, err
+}
+
+// Transactional runs f in a database transaction.
+func (r repository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	return r.db.Transactional(ctx, f)
+}
+
+// CycleIssues retrieves the issue records of the given cycle from the database.
+func (r repository) CycleIssues(ctx context.Context, cycleID uint64) ([]entity.Issue, error) {
+	var _issues []entity.Issue
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.With(ctx).Model(&_issues).
+		Relation("Status").
+		Relation("Assignee").
+		Where("i.cycle_id = ?", cycleID).
+		Where("i.workspace_id = ?", workspace.Id).
+		Apply(visible(ctx)).
+		Order("id ASC").
+		Select()
+	return _issues, err
+}
+
 func (r repository) GetStatus(ctx context.Context, uuid string) (entity.IssueStatus, error) {
 	var issueStatus entity.IssueStatus
-	err := r.db.With(ctx).Model(&issueStatus).Where("i.uuid = ?", uuid).First()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return issueStatus, err
+	}
+	err = r.db.With(ctx).Model(&issueStatus).
+		Where("ss.uuid = ?", uuid).
+		Where("ss.workspace_id = ?", workspace.Id).
+		First()
 	return issueStatus, err
 }
 
 func (r repository) CountStatus(ctx context.Context) (int64, error) {
-	var count int
-	count, err := r.db.With(ctx).Model((*entity.IssueStatus)(nil)).Count()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count, err := r.db.With(ctx).Model((*entity.IssueStatus)(nil)).Where("workspace_id = ?", workspace.Id).Count()
 	return int64(count), err
 }
 
 func (r repository) QueryStatus(ctx context.Context, offset, limit int64) ([]entity.IssueStatus, int, error) {
 	var _issueStatus []entity.IssueStatus
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
 	count, err := r.db.With(ctx).Model(&_issueStatus).
+		Where("ss.workspace_id = ?", workspace.Id).
+		Order("id ASC").
 		Limit(int(limit)).
 		Offset(int(offset)).
 		SelectAndCount()
 	return _issueStatus, count, err
 }
 
-// CreateStatus saves a new status record in the database.
+// CreateStatus saves a new status record in the current workspace.
 func (r repository) CreateStatus(ctx context.Context, issueStatus entity.IssueStatus) error {
-	_, err := r.db.With(ctx).Model(&issueStatus).Insert()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	issueStatus.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&issueStatus).Insert()
 	return err
 }
 
 func (r repository) UpdateStatus(ctx context.Context, issueStatus entity.IssueStatus) error {
-	_, err := r.db.With(ctx).Model(&issueStatus).WherePK().Update()
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	issueStatus.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&issueStatus).WherePK().Where("workspace_id = ?", workspace.Id).Update()
 	return err
 }
 
 func (r repository) DeleteStatus(ctx context.Context, uuid string) error {
-	issueStatus, err := r.Get(ctx, uuid)
+	issueStatus, err := r.GetStatus(ctx, uuid)
 	if err != nil {
 		return err
 	}
 	_, err = r.db.With(ctx).Model(&issueStatus).WherePK().Delete()
 	return err
 }
+
+// AllStatus reads all the status records from the database.
+func (r repository) AllStatus(ctx context.Context) ([]entity.IssueStatus, error) {
+	var _issueStatus []entity.IssueStatus
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.With(ctx).Model(&_issueStatus).Where("ss.workspace_id = ?", workspace.Id).Order("id ASC").Select()
+	return _issueStatus, err
+}
+
+// AddTransition saves a new transition record in the database.
+func (r repository) AddTransition(ctx context.Context, transition entity.IssueTransition) error {
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	transition.WorkspaceID = workspace.Id
+	_, err = r.db.With(ctx).Model(&transition).Insert()
+	return err
+}
+
+// Transitions reads the transition records of the issues the current user can view created before until from the database.
+func (r repository) Transitions(ctx context.Context, cycleID uint64, until time.Time) ([]entity.IssueTransition, error) {
+	var transitions []entity.IssueTransition
+	workspace, err := auth.ExtractWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q := r.db.With(ctx).Model(&transitions).
+		Where("it.workspace_id = ?", workspace.Id).
+		Where("it.created_at < ?", until).
+		Apply(func(q *orm.Query) (*orm.Query, error) {
+			return acl.Visible(ctx, q, acl.ObjectIssues, "it.issue_id"), nil
+		})
+	if cycleID != 0 {
+		q = q.Where("it.issue_id IN (SELECT issue_id FROM issue_transitions WHERE cycle_id = ?)", cycleID)
+	}
+	err = q.Order("it.created_at ASC", "it.id ASC").Select()
+	return transitions, err
+}
@@ -2,6 +2,7 @@ package issues
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,16 +10,19 @@ import (
 
 	"github.com/go-pg/pg/v10"
 	"github.com/google/uuid"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/db"
+	"github.com/mirzakhany/pm/protobuf/workspaces"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestRepository(t *testing.T) {
-	database := db.NewForTest(t, []interface{}{(*entity.User)(nil), (*entity.Cycle)(nil), (*entity.Issue)(nil), (*entity.IssueStatus)(nil)})
+	database := db.NewForTest(t, []interface{}{(*entity.User)(nil), (*entity.Cycle)(nil), (*entity.Issue)(nil), (*entity.IssueStatus)(nil), (*entity.IssueCounter)(nil)})
 	db.ResetTables(t, database, "issues", "users", "cycles")
 	repo := NewRepository(database)
 
-	ctx := context.Background()
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 1})
+	otherCtx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 2})
 	// initial count
 	count, err := repo.Count(ctx)
 	assert.Nil(t, err)
@@ -50,6 +54,8 @@ func TestRepository(t *testing.T) {
 	_, err = repo.Get(ctx, "test0")
 	assert.NotNil(t, err)
 	assert.EqualError(t, pg.ErrNoRows, err.Error())
+	_, err = repo.Get(otherCtx, testUuid)
+	assert.EqualError(t, pg.ErrNoRows, err.Error())
 
 	// update
 	updatedIssue := entity.Issue{
@@ -85,3 +91,45 @@ func TestRepository(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.EqualError(t, pg.ErrNoRows, err.Error())
 }
+
+func TestRepository_NextNumber(t *testing.T) {
+	database := db.NewForTest(t, []interface{}{(*entity.User)(nil), (*entity.Cycle)(nil), (*entity.Issue)(nil), (*entity.IssueStatus)(nil), (*entity.IssueCounter)(nil)})
+	db.ResetTables(t, database, "issues", "issue_counters")
+	repo := NewRepository(database)
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspaces.Workspace{Id: 1})
+
+	// the issues created at the same time get different numbers
+	const n = 10
+	numbers := make(chan uint64, n)
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.Transactional(ctx, func(ctx context.Context) error {
+				number, err := repo.NextNumber(ctx)
+				if err != nil {
+					return err
+				}
+				numbers <- number
+				now := time.Now()
+				return repo.Create(ctx, entity.Issue{UUID: uuid.New().String(), Number: number, Title: "concurrent", CreatedAt: now, UpdatedAt: now})
+			})
+		}()
+	}
+	wg.Wait()
+	close(numbers)
+	close(errs)
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+	seen := make(map[uint64]bool)
+	for number := range numbers {
+		assert.False(t, seen[number])
+		seen[number] = true
+	}
+	for i := uint64(1); i <= n; i++ {
+		assert.True(t, seen[i])
+	}
+}
@@ -2,15 +2,21 @@ package issues
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/mirzakhany/pm/internal/acl"
 	"github.com/mirzakhany/pm/internal/entity"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/mirzakhany/pm/internal/auth/users"
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
 	"github.com/mirzakhany/pm/internal/cycles"
+	"github.com/mirzakhany/pm/pkg/auth"
 	issuesProto "github.com/mirzakhany/pm/protobuf/issues"
+	usersProto "github.com/mirzakhany/pm/protobuf/users"
 )
 
 // Service encapsulates use case logic for issues.
@@ -28,6 +34,13 @@ type Service interface {
 	CreateStatus(ctx context.Context, input *issuesProto.CreateIssueStatusRequest) (*issuesProto.IssueStatus, error)
 	UpdateStatus(ctx context.Context, input *issuesProto.UpdateIssueStatusRequest) (*issuesProto.IssueStatus, error)
 	DeleteStatus(ctx context.Context, Uuid string) (*issuesProto.IssueStatus, error)
+	// SetStatus moves the issue with the specified UUID to the status with the specified UUID
+	SetStatus(ctx context.Context, uuid, statusUuid string) (*issuesProto.Issue, error)
+
+	// CumulativeFlow returns the number of issues in each status category for every day in the range
+	CumulativeFlow(ctx context.Context, input *issuesProto.GetCumulativeFlowRequest) (*issuesProto.GetCumulativeFlowResponse, error)
+	// CycleReport returns the report of the completed cycle with the specified UUID
+	CycleReport(ctx context.Context, cycleUUID string) (*CycleReport, error)
 }
 
 // ValidateCreateRequest validates the CreateIssueRequest fields.
@@ -46,10 +59,20 @@ func ValidateUpdateRequest(u *issuesProto.UpdateIssueRequest) error {
 	)
 }
 
+// statusCategoryRule checks the status category is one of the known categories
+var statusCategoryRule = validation.In(
+	entity.StatusCategoryBacklog,
+	entity.StatusCategoryUnstarted,
+	entity.StatusCategoryStarted,
+	entity.StatusCategoryCompleted,
+	entity.StatusCategoryCanceled,
+)
+
 // ValidateStatusCreateRequest validates the CreateIssueStatusRequest fields.
 func ValidateStatusCreateRequest(c *issuesProto.CreateIssueStatusRequest) error {
 	return validation.ValidateStruct(c,
 		validation.Field(&c.Title, validation.Required, validation.Length(0, 128)),
+		validation.Field(&c.Category, statusCategoryRule),
 	)
 }
 
@@ -57,18 +80,37 @@ func ValidateStatusCreateRequest(c *issuesProto.CreateIssueStatusRequest) error
 func ValidateStatusUpdateRequest(u *issuesProto.UpdateIssueStatusRequest) error {
 	return validation.ValidateStruct(u,
 		validation.Field(&u.Title, validation.Required, validation.Length(0, 128)),
+		validation.Field(&u.Category, statusCategoryRule),
 	)
 }
 
+var (
+	errNoStatus          = errors.New("status is required, the workspace has no default status")
+	errAssigneeNotMember = errors.New("the assignee is not a member of the workspace")
+)
+
 type service struct {
-	repo      Repository
-	usersSrv  users.Service
-	cyclesSrv cycles.Service
+	repo          Repository
+	usersSrv      users.Service
+	cyclesSrv     cycles.Service
+	workspacesSrv workspaces.Service
 }
 
 // NewService creates a new issue service.
-func NewService(repo Repository, userSrv users.Service, cyclesSrv cycles.Service) Service {
-	return service{repo, userSrv, cyclesSrv}
+func NewService(repo Repository, userSrv users.Service, cyclesSrv cycles.Service, workspacesSrv workspaces.Service) Service {
+	return service{repo, userSrv, cyclesSrv, workspacesSrv}
+}
+
+// assignee returns the user with the specified UUID if they are a member of the workspace of the request.
+func (s service) assignee(ctx context.Context, uuid string) (*usersProto.User, error) {
+	member, err := s.workspacesSrv.IsMember(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, errAssigneeNotMember
+	}
+	return s.usersSrv.GetByUUID(ctx, uuid)
 }
 
 // Get returns the issue with the specified the issue UUID.
@@ -86,12 +128,16 @@ func (s service) Create(ctx context.Context, req *issuesProto.CreateIssueRequest
 		return nil, err
 	}
 
-	creator, err := s.usersSrv.Get(ctx, req.CreatorUuid)
+	principal, err := auth.ExtractUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	creator, err := s.usersSrv.GetByUUID(ctx, principal.Uuid)
 	if err != nil {
 		return nil, err
 	}
 
-	assignee, err := s.usersSrv.Get(ctx, req.AssigneeUuid)
+	assignee, err := s.assignee(ctx, req.AssigneeUuid)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +147,19 @@ func (s service) Create(ctx context.Context, req *issuesProto.CreateIssueRequest
 		return nil, err
 	}
 
-	status, err := s.repo.GetStatus(ctx, req.StatusUuid)
+	settings, err := s.workspacesSrv.CurrentSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statusUUID := req.StatusUuid
+	if statusUUID == "" {
+		statusUUID = settings.DefaultStatusUuid
+	}
+	if statusUUID == "" {
+		return nil, errNoStatus
+	}
+	status, err := s.repo.GetStatus(ctx, statusUUID)
 	if err != nil {
 		return nil, err
 	}
@@ -113,24 +171,47 @@ func (s service) Create(ctx context.Context, req *issuesProto.CreateIssueRequest
 	creatorModel := entity.UserFromProto(creator)
 	cycleModel := entity.CycleFromProto(cycle)
 
-	err = s.repo.Create(ctx, entity.Issue{
-		UUID:        id,
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      &status,
-		Cycle:       &cycleModel,
-		Estimate:    req.Estimate,
-		AssigneeID:  assignee.Id,
-		CreatorID:   creator.Id,
-		Assignee:    &assigneeModel,
-		Creator:     &creatorModel,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+	// the quota is checked and the number is taken in the transaction of the insert, concurrent requests wait for it
+	err = s.repo.Transactional(ctx, func(ctx context.Context) error {
+		if err := s.workspacesSrv.CheckQuota(ctx, workspaces.QuotaIssues, 1); err != nil {
+			return err
+		}
+		number, err := s.repo.NextNumber(ctx)
+		if err != nil {
+			return err
+		}
+		return s.repo.Create(ctx, entity.Issue{
+			UUID:        id,
+			Number:      number,
+			Key:         fmt.Sprintf("%s-%d", settings.IssueKeyPrefix, number),
+			Title:       req.Title,
+			Description: req.Description,
+			Status:      &status,
+			StatusID:    status.ID,
+			Cycle:       &cycleModel,
+			CycleID:     cycle.Id,
+			Estimate:    req.Estimate,
+			AssigneeID:  assignee.Id,
+			CreatorID:   creator.Id,
+			Assignee:    &assigneeModel,
+			Creator:     &creatorModel,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
-	return s.Get(ctx, id)
+
+	issue, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.addTransition(ctx, issue.Id, cycle.Id, 0, status.ID)
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
 }
 
 // Update updates the issue with the specified UUID.
@@ -143,14 +224,12 @@ func (s service) Update(ctx context.Context, req *issuesProto.UpdateIssueRequest
 	if err != nil {
 		return nil, err
 	}
-	now := time.Now()
-
-	creator, err := s.usersSrv.Get(ctx, req.CreatorUuid)
-	if err != nil {
+	if err := s.editable(ctx, issue.ID); err != nil {
 		return nil, err
 	}
+	now := time.Now()
 
-	assignee, err := s.usersSrv.Get(ctx, req.AssigneeUuid)
+	assignee, err := s.assignee(ctx, req.AssigneeUuid)
 	if err != nil {
 		return nil, err
 	}
@@ -166,22 +245,25 @@ func (s service) Update(ctx context.Context, req *issuesProto.UpdateIssueRequest
 	}
 
 	assigneeModel := entity.UserFromProto(assignee)
-	creatorModel := entity.UserFromProto(creator)
 	cycleModel := entity.CycleFromProto(cycle)
 
+	// the creator of an issue does not change
 	issueModel := entity.Issue{
 		ID:          issue.ID,
 		UUID:        issue.UUID,
+		Number:      issue.Number,
+		Key:         issue.Key,
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      &status,
+		StatusID:    status.ID,
 		Cycle:       &cycleModel,
 		CycleID:     cycle.Id,
 		Estimate:    req.Estimate,
 		AssigneeID:  assignee.Id,
-		CreatorID:   creator.Id,
+		CreatorID:   issue.CreatorID,
 		Assignee:    &assigneeModel,
-		Creator:     &creatorModel,
+		Creator:     issue.Creator,
 		CreatedAt:   issue.CreatedAt,
 		UpdatedAt:   now,
 	}
@@ -189,19 +271,87 @@ func (s service) Update(ctx context.Context, req *issuesProto.UpdateIssueRequest
 	if err := s.repo.Update(ctx, issueModel); err != nil {
 		return nil, err
 	}
+
+	if issue.StatusID != status.ID || issue.CycleID != cycle.Id {
+		err = s.addTransition(ctx, issue.ID, cycle.Id, issue.StatusID, status.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return s.Get(ctx, req.Uuid)
 }
 
 // Delete deletes the issue with the specified UUID.
 func (s service) Delete(ctx context.Context, UUID string) (*issuesProto.Issue, error) {
-	issue, err := s.Get(ctx, UUID)
+	issue, err := s.repo.Get(ctx, UUID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.editable(ctx, issue.ID); err != nil {
+		return nil, err
+	}
 	if err = s.repo.Delete(ctx, UUID); err != nil {
 		return nil, err
 	}
-	return issue, nil
+	if err = s.addTransition(ctx, issue.ID, issue.CycleID, issue.StatusID, 0); err != nil {
+		return nil, err
+	}
+	return issue.ToProto(true), nil
+}
+
+// SetStatus moves the issue with the specified UUID to another status.
+func (s service) SetStatus(ctx context.Context, UUID, statusUUID string) (*issuesProto.Issue, error) {
+	issue, err := s.repo.Get(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.editable(ctx, issue.ID); err != nil {
+		return nil, err
+	}
+
+	status, err := s.repo.GetStatus(ctx, statusUUID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.StatusID == status.ID {
+		return issue.ToProto(true), nil
+	}
+
+	from := issue.StatusID
+	issue.StatusID = status.ID
+	issue.Status = &status
+	issue.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, issue); err != nil {
+		return nil, err
+	}
+	if err := s.addTransition(ctx, issue.ID, issue.CycleID, from, status.ID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, UUID)
+}
+
+// editable returns acl.ErrReadOnly if the access control list of the issue with the specified ID
+// does not let the current user edit it.
+func (s service) editable(ctx context.Context, id uint64) error {
+	ok, err := s.repo.Editable(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return acl.ErrReadOnly
+	}
+	return nil
+}
+
+// addTransition records the move of an issue between two status or into a new cycle.
+func (s service) addTransition(ctx context.Context, issueID, cycleID, from, to uint64) error {
+	return s.repo.AddTransition(ctx, entity.IssueTransition{
+		IssueID:      issueID,
+		CycleID:      cycleID,
+		FromStatusID: from,
+		ToStatusID:   to,
+		CreatedAt:    time.Now(),
+	})
 }
 
 // Count returns the number of issues.
@@ -255,9 +405,14 @@ func (s service) CreateStatus(ctx context.Context, req *issuesProto.CreateIssueS
 
 	id := uuid.New().String()
 	now := time.Now()
+	category := req.Category
+	if category == "" {
+		category = entity.StatusCategoryUnstarted
+	}
 	err := s.repo.CreateStatus(ctx, entity.IssueStatus{
 		UUID:      id,
 		Title:     req.Title,
+		Category:  category,
 		CreatedAt: now,
 		UpdatedAt: now,
 	})
@@ -278,10 +433,15 @@ func (s service) UpdateStatus(ctx context.Context, req *issuesProto.UpdateIssueS
 	}
 	now := time.Now()
 
+	category := req.Category
+	if category == "" {
+		category = issueStatus.Category
+	}
 	issueStatusModel := entity.IssueStatus{
 		ID:        issueStatus.ID,
 		UUID:      issueStatus.UUID,
 		Title:     req.Title,
+		Category:  category,
 		CreatedAt: issueStatus.CreatedAt,
 		UpdatedAt: now,
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/mirzakhany/pm/internal/entity"
 
@@ -12,10 +13,16 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/mirzakhany/pm/internal/acl"
 	userSrv "github.com/mirzakhany/pm/internal/auth/users"
+	"github.com/mirzakhany/pm/internal/auth/workspaces"
+	"github.com/mirzakhany/pm/pkg/auth"
+	cyclesProto "github.com/mirzakhany/pm/protobuf/cycles"
 	issues "github.com/mirzakhany/pm/protobuf/issues"
 	usersProto "github.com/mirzakhany/pm/protobuf/users"
+	workspacesProto "github.com/mirzakhany/pm/protobuf/workspaces"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var errCRUD = errors.New("error crud")
@@ -98,8 +105,9 @@ func Test_service_CRUD(t *testing.T) {
 	Uuid := uuid.New().String()
 	userServices := userSrv.NewServiceForTest()
 	cycleService := cycles.NewServiceForTest(userServices)
-	s := NewService(&mockRepository{}, userServices, cycleService)
-	ctx := context.Background()
+	workspacesSrv := workspaces.NewServiceForTest()
+	s := NewService(&mockRepository{}, userServices, cycleService, workspacesSrv)
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspacesProto.Workspace{Id: 1})
 
 	// initial count
 	count, _ := s.Count(ctx)
@@ -114,11 +122,23 @@ func Test_service_CRUD(t *testing.T) {
 		Username: "test", Password: "test", Email: "test@test.com", Enable: true,
 	})
 	assert.Nil(t, err)
-	// successful creation
+	ctx = auth.ContextWithUser(ctx, user1)
+
+	// the assignee must be a member of the workspace
+	_, err = s.Create(ctx, &issues.CreateIssueRequest{
+		Title:        "test",
+		Description:  "this is a test",
+		AssigneeUuid: user2.Uuid,
+		StatusUuid:   Uuid,
+		CycleUuid:    Uuid,
+	})
+	assert.Equal(t, errAssigneeNotMember, err)
+	workspaces.AddMemberForTest(workspacesSrv, 1, user2)
+
+	// successful creation, by the user of the request
 	issue, err := s.Create(ctx, &issues.CreateIssueRequest{
 		Title:        "test",
 		Description:  "this is a test",
-		CreatorUuid:  user1.Uuid,
 		AssigneeUuid: user2.Uuid,
 		StatusUuid:   Uuid,
 		CycleUuid:    Uuid,
@@ -129,6 +149,7 @@ func Test_service_CRUD(t *testing.T) {
 	assert.NotEmpty(t, issue.Uuid)
 	id := issue.Uuid
 	assert.Equal(t, "test", issue.Title)
+	assert.Equal(t, user1.Uuid, issue.Creator.Uuid)
 	assert.NotEmpty(t, issue.CreatedAt)
 	assert.NotEmpty(t, issue.UpdatedAt)
 	count, _ = s.Count(ctx)
@@ -150,7 +171,6 @@ func Test_service_CRUD(t *testing.T) {
 	_, err = s.Create(ctx, &issues.CreateIssueRequest{
 		Title:        "error",
 		Description:  "this is a test",
-		CreatorUuid:  user1.Uuid,
 		AssigneeUuid: user2.Uuid,
 		StatusUuid:   Uuid,
 		CycleUuid:    Uuid,
@@ -163,7 +183,6 @@ func Test_service_CRUD(t *testing.T) {
 	_, _ = s.Create(ctx, &issues.CreateIssueRequest{
 		Title:        "test2",
 		Description:  "this is a test",
-		CreatorUuid:  user1.Uuid,
 		AssigneeUuid: user2.Uuid,
 		StatusUuid:   Uuid,
 		CycleUuid:    Uuid,
@@ -175,7 +194,6 @@ func Test_service_CRUD(t *testing.T) {
 		Uuid:         id,
 		Title:        "test-updated",
 		Description:  "this is a test",
-		CreatorUuid:  user1.Uuid,
 		AssigneeUuid: user2.Uuid,
 		StatusUuid:   Uuid,
 		CycleUuid:    Uuid,
@@ -187,7 +205,6 @@ func Test_service_CRUD(t *testing.T) {
 		Uuid:         "none",
 		Title:        "test-updated",
 		Description:  "this is a test",
-		CreatorUuid:  user1.Uuid,
 		AssigneeUuid: user2.Uuid,
 		StatusUuid:   Uuid,
 		CycleUuid:    Uuid,
@@ -200,7 +217,6 @@ func Test_service_CRUD(t *testing.T) {
 		Uuid:         id,
 		Title:        "",
 		Description:  "this is a test",
-		CreatorUuid:  user1.Uuid,
 		AssigneeUuid: user2.Uuid,
 		StatusUuid:   Uuid,
 		CycleUuid:    Uuid,
@@ -215,7 +231,6 @@ func Test_service_CRUD(t *testing.T) {
 		Uuid:         id,
 		Title:        "error",
 		Description:  "this is a test",
-		CreatorUuid:  user1.Uuid,
 		AssigneeUuid: user2.Uuid,
 		StatusUuid:   Uuid,
 		CycleUuid:    Uuid,
@@ -247,9 +262,98 @@ func Test_service_CRUD(t *testing.T) {
 	assert.Equal(t, int64(1), count)
 }
 
+func Test_service_CreateDefaults(t *testing.T) {
+	userServices := userSrv.NewServiceForTest()
+	cycleService := cycles.NewServiceForTest(userServices)
+	statusUUID := uuid.New().String()
+	repo := &mockRepository{statusItems: []entity.IssueStatus{{ID: 1, UUID: statusUUID, Title: "todo"}}}
+	workspacesSrv := workspaces.NewServiceForTest()
+	s := NewService(repo, userServices, cycleService, workspacesSrv)
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspacesProto.Workspace{Id: 1})
+
+	user, err := userServices.Create(ctx, &usersProto.CreateUserRequest{
+		Username: "test", Password: "test", Email: "test@example.com", Enable: true,
+	})
+	assert.Nil(t, err)
+	ctx = auth.ContextWithUser(ctx, user)
+	workspaces.AddMemberForTest(workspacesSrv, 1, user)
+	cycle, err := cycleService.Create(ctx, &cyclesProto.CreateCycleRequest{
+		Title: "test", Description: "test", StartAt: timestamppb.Now(), EndAt: timestamppb.Now(),
+	})
+	assert.Nil(t, err)
+
+	req := &issues.CreateIssueRequest{
+		Title:        "test",
+		Description:  "this is a test",
+		AssigneeUuid: user.Uuid,
+		CycleUuid:    cycle.Uuid,
+	}
+
+	// the workspace has no default status
+	_, err = s.Create(ctx, req)
+	assert.Equal(t, errNoStatus, err)
+
+	// issues are numbered with the workspace key prefix
+	req.StatusUuid = statusUUID
+	issue, err := s.Create(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), issue.Number)
+	assert.Equal(t, "ISS-1", issue.Key)
+	issue, err = s.Create(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "ISS-2", issue.Key)
+}
+
+func Test_service_ReadOnly(t *testing.T) {
+	userServices := userSrv.NewServiceForTest()
+	cycleService := cycles.NewServiceForTest(userServices)
+	statusUUID := uuid.New().String()
+	repo := &mockRepository{
+		statusItems: []entity.IssueStatus{{ID: 1, UUID: statusUUID, Title: "todo"}, {ID: 2, UUID: uuid.New().String(), Title: "done"}},
+		readOnly:    make(map[uint64]bool),
+	}
+	workspacesSrv := workspaces.NewServiceForTest()
+	s := NewService(repo, userServices, cycleService, workspacesSrv)
+	ctx := auth.ContextWithWorkspace(context.Background(), &workspacesProto.Workspace{Id: 1})
+
+	user, err := userServices.Create(ctx, &usersProto.CreateUserRequest{
+		Username: "test", Password: "test", Email: "test@example.com", Enable: true,
+	})
+	assert.Nil(t, err)
+	ctx = auth.ContextWithUser(ctx, user)
+	workspaces.AddMemberForTest(workspacesSrv, 1, user)
+	cycle, err := cycleService.Create(ctx, &cyclesProto.CreateCycleRequest{
+		Title: "test", Description: "test", StartAt: timestamppb.Now(), EndAt: timestamppb.Now(),
+	})
+	assert.Nil(t, err)
+	issue, err := s.Create(ctx, &issues.CreateIssueRequest{
+		Title: "test", Description: "test", AssigneeUuid: user.Uuid, CycleUuid: cycle.Uuid, StatusUuid: statusUUID,
+	})
+	assert.Nil(t, err)
+	repo.readOnly[issue.Id] = true
+
+	// the access control list of the issue does not let the user edit it
+	_, err = s.Update(ctx, &issues.UpdateIssueRequest{
+		Uuid: issue.Uuid, Title: "updated", Description: "test", AssigneeUuid: user.Uuid, CycleUuid: cycle.Uuid, StatusUuid: statusUUID,
+	})
+	assert.Equal(t, acl.ErrReadOnly, err)
+	_, err = s.SetStatus(ctx, issue.Uuid, repo.statusItems[1].UUID)
+	assert.Equal(t, acl.ErrReadOnly, err)
+	_, err = s.Delete(ctx, issue.Uuid)
+	assert.Equal(t, acl.ErrReadOnly, err)
+
+	// it can still be read
+	res, err := s.Get(ctx, issue.Uuid)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", res.Title)
+}
+
 type mockRepository struct {
 	items       []entity.Issue
 	statusItems []entity.IssueStatus
+	transitions []entity.IssueTransition
+	// readOnly are the IDs of the issues the current user cannot edit
+	readOnly map[uint64]bool
 }
 
 func (m mockRepository) GetStatus(ctx context.Context, id string) (entity.IssueStatus, error) {
@@ -349,3 +453,50 @@ func (m *mockRepository) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+func (m mockRepository) NextNumber(ctx context.Context) (uint64, error) {
+	var next uint64
+	for _, item := range m.items {
+		if item.Number > next {
+			next = item.Number
+		}
+	}
+	return next + 1, nil
+}
+
+func (m *mockRepository) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	return f(ctx)
+}
+
+func (m mockRepository) CycleIssues(ctx context.Context, cycleID uint64) ([]entity.Issue, error) {
+	var items []entity.Issue
+	for _, item := range m.items {
+		if item.CycleID == cycleID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (m mockRepository) AllStatus(ctx context.Context) ([]entity.IssueStatus, error) {
+	return m.statusItems, nil
+}
+
+func (m *mockRepository) AddTransition(ctx context.Context, transition entity.IssueTransition) error {
+	m.transitions = append(m.transitions, transition)
+	return nil
+}
+
+func (m mockRepository) Transitions(ctx context.Context, cycleID uint64, until time.Time) ([]entity.IssueTransition, error) {
+	var transitions []entity.IssueTransition
+	for _, item := range m.transitions {
+		if item.CreatedAt.Before(until) {
+			transitions = append(transitions, item)
+		}
+	}
+	return transitions, nil
+}
+
+func (m mockRepository) Editable(ctx context.Context, id uint64) (bool, error) {
+	return !m.readOnly[id], nil
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// migration brings the tables of databases created by older versions up to the current entities,
+// CreateTable leaves the existing tables as they are.
+type migration struct {
+	name       string
+	statements []string
+}
+
+// migrations run in order after the missing tables are created, each once per database.
+// New databases already have the tables of the current entities, so the statements must leave them unchanged.
+var migrations = []migration{
+	{"issues_drop_single_column_uniques", []string{
+		// an issue shared its status, cycle, workspace, assignee or creator with no other issue
+		`ALTER TABLE issues DROP CONSTRAINT IF EXISTS issues_status_id_key`,
+		`ALTER TABLE issues DROP CONSTRAINT IF EXISTS issues_cycle_id_key`,
+		`ALTER TABLE issues DROP CONSTRAINT IF EXISTS issues_workspace_id_key`,
+		`ALTER TABLE issues DROP CONSTRAINT IF EXISTS issues_assignee_id_key`,
+		`ALTER TABLE issues DROP CONSTRAINT IF EXISTS issues_creator_id_key`,
+	}},
+	{"issues_status_add_category", []string{
+		`ALTER TABLE issues_status ADD COLUMN IF NOT EXISTS category text`,
+		`UPDATE issues_status SET category = 'unstarted' WHERE category IS NULL OR category = ''`,
+	}},
+	{"issue_transitions_backfill", []string{
+		// the issues created before the transitions were recorded entered their current status when they were created
+		`INSERT INTO issue_transitions (issue_id, workspace_id, cycle_id, from_status_id, to_status_id, created_at)
+			SELECT i.id, i.workspace_id, i.cycle_id, 0, i.status_id, i.created_at FROM issues i
+			WHERE NOT EXISTS (SELECT 1 FROM issue_transitions it WHERE it.issue_id = i.id)`,
+	}},
+	{"cycles_add_goals", []string{
+		`ALTER TABLE cycles ADD COLUMN IF NOT EXISTS goals text[]`,
+	}},
+	{"scope_to_workspaces", []string{
+		`ALTER TABLE cycles ADD COLUMN IF NOT EXISTS workspace_id bigint`,
+		`ALTER TABLE roles ADD COLUMN IF NOT EXISTS workspace_id bigint`,
+		`ALTER TABLE issues_status ADD COLUMN IF NOT EXISTS workspace_id bigint`,
+		// the rows created before workspaces were enforced belong to the first workspace,
+		// cycles and statuses to the workspace of their issues when they have any
+		`UPDATE issues SET workspace_id = (SELECT min(id) FROM workspaces) WHERE workspace_id IS NULL`,
+		`UPDATE cycles SET workspace_id = (SELECT min(i.workspace_id) FROM issues i WHERE i.cycle_id = cycles.id) WHERE workspace_id IS NULL`,
+		`UPDATE cycles SET workspace_id = (SELECT min(id) FROM workspaces) WHERE workspace_id IS NULL`,
+		`UPDATE issues_status SET workspace_id = (SELECT min(i.workspace_id) FROM issues i WHERE i.status_id = issues_status.id) WHERE workspace_id IS NULL`,
+		`UPDATE issues_status SET workspace_id = (SELECT min(id) FROM workspaces) WHERE workspace_id IS NULL`,
+		`UPDATE roles SET workspace_id = (SELECT min(id) FROM workspaces) WHERE workspace_id IS NULL`,
+		`UPDATE issue_transitions SET workspace_id = (SELECT i.workspace_id FROM issues i WHERE i.id = issue_transitions.issue_id) WHERE workspace_id IS NULL`,
+	}},
+	{"issues_add_number_and_key", []string{
+		`ALTER TABLE issues ADD COLUMN IF NOT EXISTS number bigint`,
+		`ALTER TABLE issues ADD COLUMN IF NOT EXISTS key text`,
+		// the existing issues are numbered in the order they were created
+		`UPDATE issues SET number = numbered.number
+			FROM (SELECT id, row_number() OVER (PARTITION BY workspace_id ORDER BY id) AS number FROM issues) AS numbered
+			WHERE issues.id = numbered.id AND issues.number IS NULL`,
+		`UPDATE issues SET key = coalesce(
+			(SELECT s.issue_key_prefix FROM workspace_settings s WHERE s.workspace_id = issues.workspace_id), 'ISS'
+		) || '-' || number WHERE key IS NULL`,
+		// named as the constraint of the new databases
+		`CREATE UNIQUE INDEX IF NOT EXISTS issues_number_workspace_id_key ON issues (number, workspace_id)`,
+	}},
+	{"workspaces_add_deleted_at", []string{
+		`ALTER TABLE workspaces ADD COLUMN IF NOT EXISTS deleted_at timestamptz`,
+	}},
+	{"roles_add_builtin", []string{
+		// the roles created before the builtin ones are custom roles
+		`ALTER TABLE roles ADD COLUMN IF NOT EXISTS builtin boolean DEFAULT false`,
+		`UPDATE roles SET builtin = false WHERE builtin IS NULL`,
+	}},
+	{"users_add_email_verified", []string{
+		// the users created before the verification could login, their emails are taken as verified.
+		// Unverified users have a NULL email_verified as false is not saved, only a new column is filled.
+		`DO $$ BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'email_verified') THEN
+				ALTER TABLE users ADD COLUMN email_verified boolean;
+				UPDATE users SET email_verified = true;
+			END IF;
+		END $$`,
+	}},
+	{"users_add_two_factor", []string{
+		// the existing users have no second factor, NULL reads as disabled
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret text`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled boolean`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS recovery_codes text[]`,
+	}},
+}
+
+// migrate runs the migrations the database has not applied yet, each in a transaction recording it.
+// Servers starting together wait for the one applying a migration and skip it.
+func migrate(ctx context.Context, db *pg.DB) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		name text PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		m := m
+		err := db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+			res, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (name) VALUES (?) ON CONFLICT DO NOTHING`, m.name)
+			if err != nil {
+				return err
+			}
+			if res.RowsAffected() == 0 {
+				return nil
+			}
+			for _, statement := range m.statements {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.name, err)
+		}
+	}
+	return nil
+}
@@ -1,31 +1,67 @@
 package internal
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
+	aclSrv "github.com/mirzakhany/pm/internal/acl"
+	archiveSrv "github.com/mirzakhany/pm/internal/archive"
+	"github.com/mirzakhany/pm/internal/auth/authz"
 	rolesSrv "github.com/mirzakhany/pm/internal/auth/roles"
 	usersSrv "github.com/mirzakhany/pm/internal/auth/users"
+	usersAuth "github.com/mirzakhany/pm/internal/auth/users/auth"
 	workspacesSrv "github.com/mirzakhany/pm/internal/auth/workspaces"
 	cyclesSrv "github.com/mirzakhany/pm/internal/cycles"
 	"github.com/mirzakhany/pm/internal/entity"
 	issuesSrv "github.com/mirzakhany/pm/internal/issues"
 	"github.com/mirzakhany/pm/pkg/db"
+	"github.com/mirzakhany/pm/pkg/grpcgw"
 )
 
-func Setup(db *db.DB) error {
+// Setup creates and migrates the database schema, registers the services and starts their background jobs,
+// which run until the context is done.
+func Setup(ctx context.Context, db *db.DB) error {
 
 	err := createSchema(db.DB())
 	if err != nil {
 		return err
 	}
+	err = migrate(ctx, db.DB())
+	if err != nil {
+		return err
+	}
+
+	err = usersAuth.InitKeys(ctx)
+	if err != nil {
+		return err
+	}
+	grpcgw.RegisterHandler(usersAuth.JWKSPath, http.HandlerFunc(usersAuth.JWKSHandler))
+
+	enforcer, err := authz.NewEnforcer(authz.NewRepository(db), authz.NewWatcher(ctx, db))
+	if err != nil {
+		return err
+	}
+	roleService := rolesSrv.NewService(rolesSrv.NewRepository(db), enforcer)
+
+	workspaceService := workspacesSrv.NewService(workspacesSrv.NewRepository(db), roleService)
+	workspacesSrv.New(workspaceService)
+	workspacesSrv.RegisterResolver(workspaceService)
+	go workspacesSrv.RunPurger(ctx, workspaceService)
+
+	authz.RegisterAuthorizer(enforcer)
+	authz.New(authz.NewService(enforcer, workspaceService))
 
-	workspacesSrv.New(workspacesSrv.NewService(workspacesSrv.NewRepository(db)))
-	rolesSrv.New(rolesSrv.NewService(rolesSrv.NewRepository(db)))
-	userService := usersSrv.NewService(usersSrv.NewRepository(db))
+	rolesSrv.New(roleService)
+	userRepo := usersSrv.NewRepository(db)
+	userService := usersSrv.NewService(userRepo, usersSrv.NewLDAPAuthenticator(userRepo, authz.NewRoleSync(enforcer)))
 	usersSrv.New(userService)
-	cycleService := cyclesSrv.NewService(cyclesSrv.NewRepository(db), userService)
+	cycleService := cyclesSrv.NewService(cyclesSrv.NewRepository(db), userService, workspaceService)
 	cyclesSrv.New(cycleService)
-	issuesSrv.New(issuesSrv.NewService(issuesSrv.NewRepository(db), userService, cycleService))
+	issuesSrv.New(issuesSrv.NewService(issuesSrv.NewRepository(db), userService, cycleService, workspaceService))
+	aclSrv.New(aclSrv.NewService(aclSrv.NewRepository(db)))
+	archiveSrv.New(archiveSrv.NewService(archiveSrv.NewRepository(db), workspaceService, roleService))
 	return nil
 }
 
@@ -34,10 +70,21 @@ func createSchema(db *pg.DB) error {
 	models := []interface{}{
 		&entity.Workspace{},
 		&entity.User{},
+		&entity.UserIdentity{},
 		&entity.Cycle{},
+		&entity.CycleCapacity{},
 		&entity.Role{},
+		&entity.WorkspaceMember{},
+		&entity.WorkspaceSettings{},
+		&entity.WorkspaceInvitation{},
+		&entity.WorkspaceDeletion{},
+		&entity.WorkspaceQuota{},
 		&entity.IssueStatus{},
 		&entity.Issue{},
+		&entity.IssueCounter{},
+		&entity.IssueTransition{},
+		&entity.CasbinRule{},
+		&entity.ObjectACL{},
 	}
 
 	for _, model := range models {
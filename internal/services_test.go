@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mirzakhany/pm/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// TestMethodPolicies makes sure no method of the services is denied for lacking an access policy.
+func TestMethodPolicies(t *testing.T) {
+	checked := 0
+	protoregistry.GlobalFiles.RangeFiles(func(f protoreflect.FileDescriptor) bool {
+		if !strings.HasPrefix(f.Path(), "protobuf/") {
+			return true
+		}
+		services := f.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := fmt.Sprintf("/%s/%s", services.Get(i).FullName(), methods.Get(j).Name())
+				_, ok := auth.MethodPolicyOf(method)
+				assert.True(t, ok, "%s has no access policy", method)
+				checked++
+			}
+		}
+		return true
+	})
+	assert.NotZero(t, checked)
+}
+
+func TestMigrations(t *testing.T) {
+	names := map[string]bool{}
+	for _, m := range migrations {
+		assert.False(t, names[m.name], "migration %s declared twice", m.name)
+		names[m.name] = true
+		assert.NotEmpty(t, m.statements, m.name)
+	}
+}
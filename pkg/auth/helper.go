@@ -5,24 +5,65 @@ import (
 	"errors"
 
 	users "github.com/mirzakhany/pm/protobuf/users"
+	workspaces "github.com/mirzakhany/pm/protobuf/workspaces"
 )
 
 type contextKey int
 
 const (
-	userKey contextKey = iota + 1
+	principalKey contextKey = iota + 1
+	workspaceKey
 )
 
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// User is the signed in user
+	User *users.User
+	// SessionID is the session the user signed in with, empty if the user was not authenticated by a token
+	SessionID string
+}
+
+// ErrNoWorkspace is returned when the request is not bound to any workspace
+var ErrNoWorkspace = errors.New("no workspace in context")
+
+// ExtractPrincipal try to extract the authenticated caller from the context
+func ExtractPrincipal(ctx context.Context) (*Principal, error) {
+	p, ok := ctx.Value(principalKey).(*Principal)
+	if !ok || p == nil || p.User == nil {
+		return nil, errors.New("no user in context")
+	}
+	return p, nil
+}
+
+// ContextWithPrincipal return context with the authenticated caller
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
 // ExtractUser try to extract the current user from the context
 func ExtractUser(ctx context.Context) (*users.User, error) {
-	u, ok := ctx.Value(userKey).(*users.User)
-	if !ok {
-		return nil, errors.New("no user in context")
+	p, err := ExtractPrincipal(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return u, nil
+	return p.User, nil
 }
 
-// ContextWithUser return context with user
+// ContextWithUser return context with user, authenticated without a session
 func ContextWithUser(ctx context.Context, user *users.User) context.Context {
-	return context.WithValue(ctx, userKey, user)
+	return ContextWithPrincipal(ctx, &Principal{User: user})
+}
+
+// ExtractWorkspace try to extract the current workspace from the context
+func ExtractWorkspace(ctx context.Context) (*workspaces.Workspace, error) {
+	w, ok := ctx.Value(workspaceKey).(*workspaces.Workspace)
+	if !ok || w == nil || w.Id == 0 {
+		return nil, ErrNoWorkspace
+	}
+	return w, nil
+}
+
+// ContextWithWorkspace return context with workspace
+func ContextWithWorkspace(ctx context.Context, workspace *workspaces.Workspace) context.Context {
+	return context.WithValue(ctx, workspaceKey, workspace)
 }
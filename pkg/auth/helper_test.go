@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	users "github.com/mirzakhany/pm/protobuf/users"
+	workspaces "github.com/mirzakhany/pm/protobuf/workspaces"
 )
 
 func TestAuthHelper(t *testing.T) {
@@ -37,4 +38,35 @@ func TestAuthHelper(t *testing.T) {
 	ctx1 := context.Background()
 	_, err1 := ExtractUser(ctx1)
 	assert.NotNil(t, err1)
+
+	// test principal with session
+	ctx2 := ContextWithPrincipal(context.Background(), &Principal{User: user, SessionID: "s1"})
+	principal, err := ExtractPrincipal(ctx2)
+	assert.Nil(t, err)
+	assert.Equal(t, "s1", principal.SessionID)
+	user2, err := ExtractUser(ctx2)
+	assert.Nil(t, err)
+	assert.Equal(t, user, user2)
+}
+
+func TestWorkspaceHelper(t *testing.T) {
+	workspace := &workspaces.Workspace{
+		Id:     1,
+		Uuid:   uuid.New().String(),
+		Title:  "test",
+		Domain: "test",
+	}
+
+	ctx := ContextWithWorkspace(context.Background(), workspace)
+	workspace1, err := ExtractWorkspace(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, workspace, workspace1)
+
+	// test not found workspace
+	_, err = ExtractWorkspace(context.Background())
+	assert.Equal(t, ErrNoWorkspace, err)
+
+	// test workspace without id
+	_, err = ExtractWorkspace(ContextWithWorkspace(context.Background(), &workspaces.Workspace{}))
+	assert.Equal(t, ErrNoWorkspace, err)
 }
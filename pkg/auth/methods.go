@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Access is what a grpc method requires from its caller
+type Access int
+
+const (
+	// AccessPublic methods are open to everyone
+	AccessPublic Access = iota + 1
+	// AccessAuthenticated methods require a signed in user
+	AccessAuthenticated
+	// AccessAuthorized methods require a signed in user allowed to do the action on the resource in the workspace of the request
+	AccessAuthorized
+	// AccessGlobal methods require a signed in user allowed to do the action on the resource in every workspace,
+	// they act on data shared by the workspaces such as the users
+	AccessGlobal
+)
+
+// MethodPolicy states the access a grpc method requires and, for authorized methods, its permission.
+// InWorkspace methods act in the workspace of the request like the authorized ones, their callers must be its members.
+type MethodPolicy struct {
+	Access      Access
+	Resource    string
+	Action      string
+	InWorkspace bool
+}
+
+var (
+	// Public is the policy of the methods open to everyone
+	Public = MethodPolicy{Access: AccessPublic}
+	// Authenticated is the policy of the methods any signed in user can call
+	Authenticated = MethodPolicy{Access: AccessAuthenticated}
+	// AuthenticatedInWorkspace is the policy of the methods any signed in user can call,
+	// in the workspace of the request if it has one
+	AuthenticatedInWorkspace = MethodPolicy{Access: AccessAuthenticated, InWorkspace: true}
+)
+
+// Require returns the policy of a method the user must be allowed to do the action on the resource to call
+func Require(resource, action string) MethodPolicy {
+	return MethodPolicy{Access: AccessAuthorized, Resource: resource, Action: action}
+}
+
+// RequireGlobal returns the policy of a method the user must be allowed to do the action on the resource by a global role to call
+func RequireGlobal(resource, action string) MethodPolicy {
+	return MethodPolicy{Access: AccessGlobal, Resource: resource, Action: action}
+}
+
+var (
+	methods    = make(map[string]MethodPolicy)
+	methodLock sync.RWMutex
+)
+
+// RegisterMethods declares the policies of the grpc methods, keyed by their full method name.
+// Methods without a policy are denied, and declaring a method twice panics.
+func RegisterMethods(policies map[string]MethodPolicy) {
+	methodLock.Lock()
+	defer methodLock.Unlock()
+	for method, policy := range policies {
+		if _, ok := methods[method]; ok {
+			panic(fmt.Sprintf("auth: policy of %s declared twice", method))
+		}
+		methods[method] = policy
+	}
+}
+
+// MethodPolicyOf returns the policy of the grpc method, false if it has none.
+func MethodPolicyOf(method string) (MethodPolicy, bool) {
+	methodLock.RLock()
+	defer methodLock.RUnlock()
+	p, ok := methods[method]
+	return p, ok
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMethods(t *testing.T) {
+	RegisterMethods(map[string]MethodPolicy{
+		"/test.Service/Open":  Public,
+		"/test.Service/Write": Require("tests", "update"),
+	})
+
+	p, ok := MethodPolicyOf("/test.Service/Write")
+	assert.True(t, ok)
+	assert.Equal(t, MethodPolicy{Access: AccessAuthorized, Resource: "tests", Action: "update"}, p)
+	p, ok = MethodPolicyOf("/test.Service/Open")
+	assert.True(t, ok)
+	assert.Equal(t, AccessPublic, p.Access)
+	_, ok = MethodPolicyOf("/test.Service/Unknown")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		RegisterMethods(map[string]MethodPolicy{"/test.Service/Open": Authenticated})
+	})
+}
@@ -8,6 +8,14 @@ type intHolderMock struct {
 	v int
 }
 
+type boolHolderMock struct {
+	v bool
+}
+
+func (b boolHolderMock) Bool() bool {
+	return b.v
+}
+
 func (i intHolderMock) Int() int {
 	return i.v
 }
@@ -27,3 +35,7 @@ func RegisterStringMock(key, defValue string) String {
 func RegisterIntMock(key string, defValue int) Int {
 	return intHolderMock{v: defValue}
 }
+
+func RegisterBoolMock(key string, defValue bool) Bool {
+	return boolHolderMock{v: defValue}
+}
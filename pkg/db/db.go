@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
 
 	"github.com/mirzakhany/pm/pkg/config"
 	"github.com/mirzakhany/pm/pkg/log"
@@ -37,13 +38,25 @@ func (db *DB) DB() *pg.DB {
 // With returns a Builder that can be used to build and execute SQL queries.
 // With will return the transaction if it is found in the given context.
 // Otherwise it will return a DB connection associated with the context.
-func (db *DB) With(ctx context.Context) *pg.DB {
-	if tx, ok := ctx.Value(txKey).(*pg.DB); ok {
+func (db *DB) With(ctx context.Context) orm.DB {
+	if tx, ok := ctx.Value(txKey).(*pg.Tx); ok {
 		return tx
 	}
 	return db.db.WithContext(ctx)
 }
 
+// Transactional runs f in a transaction. Repositories called with the context passed to f
+// run their queries in the transaction, which is committed if f returns no error and rolled back otherwise.
+// Nested calls reuse the transaction of the outer call.
+func (db *DB) Transactional(ctx context.Context, f func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey).(*pg.Tx); ok {
+		return f(ctx)
+	}
+	return db.db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		return f(context.WithValue(ctx, txKey, tx))
+	})
+}
+
 func Init(ctx context.Context) (*DB, error) {
 
 	host = config.RegisterString("db.host", "localhost")
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/textproto"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	grpcRecovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpcCtxTags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mirzakhany/pm/pkg/auth"
 	"github.com/mirzakhany/pm/pkg/config"
 	"github.com/mirzakhany/pm/pkg/log"
 	"github.com/rs/cors"
@@ -32,9 +35,11 @@ type Interceptor struct {
 }
 
 var (
-	controllers  []Controller
-	interceptors []Interceptor
-	lock         sync.RWMutex
+	controllers     []Controller
+	interceptors    []Interceptor
+	handlers        = make(map[string]http.Handler)
+	incomingHeaders = make(map[string]bool)
+	lock            sync.RWMutex
 
 	httpPort config.Int
 	grpcPort config.Int
@@ -55,6 +60,30 @@ func RegisterInterceptors(i Interceptor) {
 	interceptors = append(interceptors, i)
 }
 
+// RegisterHandler serves a plain http handler next to the gateway, outside of the grpc interceptors
+func RegisterHandler(pattern string, h http.Handler) {
+	lock.Lock()
+	defer lock.Unlock()
+	handlers[pattern] = h
+}
+
+// RegisterIncomingHeaders forwards the given http headers to the grpc services as metadata
+func RegisterIncomingHeaders(headers ...string) {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, h := range headers {
+		incomingHeaders[textproto.CanonicalMIMEHeaderKey(h)] = true
+	}
+}
+
+// incomingHeaderMatcher forwards the registered headers next to the default ones
+func incomingHeaderMatcher(key string) (string, bool) {
+	if incomingHeaders[textproto.CanonicalMIMEHeaderKey(key)] {
+		return strings.ToLower(key), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
 func newGrpcServer() *grpc.Server {
 	unaryMiddlewares := []grpc.UnaryServerInterceptor{
 		grpcRecovery.UnaryServerInterceptor(),
@@ -101,8 +130,9 @@ func serveHTTP(ctx context.Context) (func() error, error) {
 	var (
 		normalMux = http.NewServeMux()
 		mux       = runtime.NewServeMux(
-			runtime.WithMarshalerOption(runtime.MIMEWildcard, jsonpb),
+			runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{Marshaler: jsonpb}),
 			runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler),
+			runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
 		)
 	)
 	c, err := gRPCClient()
@@ -115,6 +145,9 @@ func serveHTTP(ctx context.Context) (func() error, error) {
 		controllers[i].InitRest(ctx, c, mux)
 	}
 
+	for pattern, h := range handlers {
+		normalMux.Handle(pattern, h)
+	}
 	normalMux.Handle("/", cors.AllowAll().Handler(mux))
 	srv := http.Server{
 		Addr:    httpAddr,
@@ -199,3 +232,10 @@ func Init() error {
 
 	return nil
 }
+
+func init() {
+	// the server reflection lets grpc clients discover the services
+	auth.RegisterMethods(map[string]auth.MethodPolicy{
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": auth.Public,
+	})
+}
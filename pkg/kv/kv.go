@@ -51,6 +51,58 @@ func (c *Client) Set(key string, val interface{}, alive time.Duration) error {
 	return err
 }
 
+// SetNX will set a key to a value only if it does not exist, returning true if it was set
+func (c *Client) SetNX(key string, val interface{}, alive time.Duration) (bool, error) {
+	ok, err := c.client.SetNX(c.ctx, key, val, alive).Result()
+	if err != nil {
+		log.Error("kv: write error", log.Err(err))
+	}
+	return ok, err
+}
+
+// Incr will increment the counter of a key, the life of the counter starts with its first increment
+func (c *Client) Incr(key string, alive time.Duration) (int64, error) {
+	n, err := c.client.Incr(c.ctx, key).Result()
+	if err == nil && n == 1 {
+		err = c.client.Expire(c.ctx, key, alive).Err()
+	}
+	if err != nil {
+		log.Error("kv: write error", log.Err(err))
+	}
+	return n, err
+}
+
+// AddMember will add a member to the set of a key and reset the life of the set
+func (c *Client) AddMember(key, member string, alive time.Duration) error {
+	pipe := c.client.TxPipeline()
+	pipe.SAdd(c.ctx, key, member)
+	pipe.Expire(c.ctx, key, alive)
+	_, err := pipe.Exec(c.ctx)
+	if err != nil {
+		log.Error("kv: write error", log.Err(err))
+	}
+	return err
+}
+
+// Members will return the members of the set of a key
+func (c *Client) Members(key string) ([]string, error) {
+	return c.client.SMembers(c.ctx, key).Result()
+}
+
+// RemoveMember will remove a member from the set of a key
+func (c *Client) RemoveMember(key, member string) error {
+	err := c.client.SRem(c.ctx, key, member).Err()
+	if err != nil {
+		log.Error("kv: delete member error", log.Err(err))
+	}
+	return err
+}
+
+// TTL will return the remaining life of a key
+func (c *Client) TTL(key string) (time.Duration, error) {
+	return c.client.TTL(c.ctx, key).Result()
+}
+
 // GetString will read and return a key as string
 func (c *Client) GetString(key string) (string, error) {
 	val, err := c.client.Get(c.ctx, key).Result()
@@ -60,6 +112,17 @@ func (c *Client) GetString(key string) (string, error) {
 	return val, err
 }
 
+// Take will read and remove a key at once, so only one reader gets its value
+func (c *Client) Take(key string) (string, error) {
+	pipe := c.client.TxPipeline()
+	get := pipe.Get(c.ctx, key)
+	pipe.Del(c.ctx, key)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return "", err
+	}
+	return get.Val(), nil
+}
+
 // Delete will remove a key
 func (c *Client) Delete(key string) error {
 	err := c.client.Del(c.ctx, key).Err()
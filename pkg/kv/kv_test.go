@@ -86,6 +86,83 @@ func TestSet(t *testing.T) {
 	assert.Equal(t, testVal, "test-value")
 }
 
+func TestSetNX(t *testing.T) {
+	ctx := context.Background()
+	client, err := Init(ctx)
+	assert.Nil(t, err)
+
+	ok, err := client.SetNX("test-nx", "first", time.Minute*1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.SetNX("test-nx", "second", time.Minute*1)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	testVal, err := client.GetString("test-nx")
+	assert.Nil(t, err)
+	assert.Equal(t, testVal, "first")
+}
+
+func TestIncr(t *testing.T) {
+	ctx := context.Background()
+	client, err := Init(ctx)
+	assert.Nil(t, err)
+
+	n, err := client.Incr("test-incr", time.Minute*1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = client.Incr("test-incr", time.Minute*2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+
+	ttl, err := client.TTL("test-incr")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute*1, ttl)
+}
+
+func TestMembers(t *testing.T) {
+	ctx := context.Background()
+	client, err := Init(ctx)
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.AddMember("test-set", "a", time.Minute*1))
+	assert.Nil(t, client.AddMember("test-set", "b", time.Minute*2))
+	members, err := client.Members("test-set")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, members)
+
+	ttl, err := client.TTL("test-set")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute*2, ttl)
+
+	assert.Nil(t, client.RemoveMember("test-set", "a"))
+	members, err = client.Members("test-set")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b"}, members)
+
+	members, err = client.Members("test-none")
+	assert.Nil(t, err)
+	assert.Empty(t, members)
+}
+
+func TestTake(t *testing.T) {
+	ctx := context.Background()
+	client, err := Init(ctx)
+	assert.Nil(t, err)
+
+	err = client.Set("test-take", "test-value", time.Minute*1)
+	assert.Nil(t, err)
+
+	testVal, err := client.Take("test-take")
+	assert.Nil(t, err)
+	assert.Equal(t, testVal, "test-value")
+
+	_, err = client.Take("test-take")
+	assert.Equal(t, redis.Nil, err)
+}
+
 func TestGetString(t *testing.T) {
 	ctx := context.Background()
 	client, err := Init(ctx)
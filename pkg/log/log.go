@@ -46,6 +46,12 @@ func Info(msg string, f ...zap.Field) {
 	logger.Info(msg, f...)
 }
 
+// Warn logs a message at WarnLevel. The message includes any fields passed
+// at the log site, as well as any fields accumulated on the logger.
+func Warn(msg string, f ...zap.Field) {
+	logger.Warn(msg, f...)
+}
+
 // Error logs a message at ErrorLevel. The message includes any fields passed
 // at the log site, as well as any fields accumulated on the logger.
 func Error(msg string, f ...zap.Field) {
@@ -83,3 +89,11 @@ func NewForTest() (*zap.Logger, *observer.ObservedLogs) {
 	core, recorded := observer.New(zapcore.InfoLevel)
 	return zap.New(core), recorded
 }
+
+// ObserveForTest replaces the logger with one recording the entries, so unit tests can verify
+// the entries logged by the code under test.
+func ObserveForTest() *observer.ObservedLogs {
+	var recorded *observer.ObservedLogs
+	logger, recorded = NewForTest()
+	return recorded
+}
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mirzakhany/pm/pkg/config"
+	"github.com/mirzakhany/pm/pkg/log"
+)
+
+var (
+	host     = config.RegisterString("mail.host", "")
+	port     = config.RegisterInt("mail.port", 587)
+	username = config.RegisterString("mail.username", "")
+	password = config.RegisterString("mail.password", "")
+	from     = config.RegisterString("mail.from", "pm@localhost")
+)
+
+// Message is a plain text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Sender delivers the messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var (
+	sender Sender = logSender{}
+	lock   sync.RWMutex
+)
+
+// Send delivers the message with the configured sender
+func Send(ctx context.Context, msg Message) error {
+	lock.RLock()
+	defer lock.RUnlock()
+	return sender.Send(ctx, msg)
+}
+
+// Init configures the sender, the messages are sent through the smtp server of the mail settings
+// or only logged if there is none, which is meant for development.
+func Init() Sender {
+	lock.Lock()
+	defer lock.Unlock()
+	if host.String() == "" {
+		log.Warn("mail.host is not set, emails are logged instead of sent")
+		sender = logSender{}
+	} else {
+		sender = smtpSender{
+			addr:     fmt.Sprintf("%s:%d", host.String(), port.Int()),
+			host:     host.String(),
+			username: username.String(),
+			password: password.String(),
+			from:     from.String(),
+		}
+	}
+	return sender
+}
+
+// logSender logs the messages instead of sending them
+type logSender struct{}
+
+func (logSender) Send(ctx context.Context, msg Message) error {
+	log.Info("email not sent, no mail server configured",
+		log.Any("to", msg.To), log.String("subject", msg.Subject), log.String("body", msg.Body))
+	return nil
+}
+
+// smtpSender sends the messages through an smtp server
+type smtpSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func (s smtpSender) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	return smtp.SendMail(s.addr, auth, s.from, msg.To, format(s.from, msg, time.Now()))
+}
+
+// format returns the message with its headers
+func format(from string, msg Message, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return b.Bytes()
+}
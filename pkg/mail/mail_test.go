@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	b := string(format("pm@localhost", Message{To: []string{"a@b.c", "d@e.f"}, Subject: "Hi", Body: "line 1\nline 2"}, now))
+
+	head := strings.Split(b, "\r\n\r\n")[0]
+	assert.Contains(t, head, "From: pm@localhost\r\n")
+	assert.Contains(t, head, "To: a@b.c, d@e.f\r\n")
+	assert.Contains(t, head, "Subject: Hi\r\n")
+	assert.Contains(t, head, "Date: Thu, 01 Oct 2020 12:00:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(b, "\r\n\r\nline 1\r\nline 2"))
+}
+
+func TestMockSender(t *testing.T) {
+	m := InitMock()
+	assert.Nil(t, Send(context.Background(), Message{To: []string{"a@b.c"}, Subject: "first"}))
+	assert.Nil(t, Send(context.Background(), Message{To: []string{"a@b.c"}, Subject: "second"}))
+
+	assert.Len(t, m.Messages(), 2)
+	msg, ok := m.Last("a@b.c")
+	assert.True(t, ok)
+	assert.Equal(t, "second", msg.Subject)
+	_, ok = m.Last("d@e.f")
+	assert.False(t, ok)
+}
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// MockSender records the messages instead of sending them
+type MockSender struct {
+	messages []Message
+	lock     sync.Mutex
+}
+
+func (m *MockSender) Send(ctx context.Context, msg Message) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far
+func (m *MockSender) Messages() []Message {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// Last returns the last message sent to the address
+func (m *MockSender) Last(to string) (Message, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		for _, addr := range m.messages[i].To {
+			if addr == to {
+				return m.messages[i], true
+			}
+		}
+	}
+	return Message{}, false
+}
+
+// InitMock replaces the sender with one recording the messages
+func InitMock() *MockSender {
+	m := &MockSender{}
+	lock.Lock()
+	defer lock.Unlock()
+	sender = m
+	return m
+}
@@ -0,0 +1,121 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChallenge(t *testing.T) {
+	// RFC 7636 appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestLogin(t *testing.T) {
+	m, err := NewMockServer("pm", "secret")
+	assert.Nil(t, err)
+	defer m.Close()
+	m.SetUser(MockUser{Subject: "42", Email: "user@corp.com", EmailVerified: true, PreferredUsername: "user"})
+	ctx := context.Background()
+
+	p, err := Discover(ctx, m.Config("http://localhost/callback"))
+	assert.Nil(t, err)
+	verifier, err := NewVerifier()
+	assert.Nil(t, err)
+	authURL := p.AuthCodeURL("state", "nonce", verifier)
+	u, err := url.Parse(authURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+	assert.Equal(t, Challenge(verifier), u.Query().Get("code_challenge"))
+
+	code, state, err := m.Authorize(authURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "state", state)
+
+	// the code is bound to the verifier and single use
+	_, err = p.Exchange(ctx, code, "other")
+	assert.NotNil(t, err)
+	code, _, err = m.Authorize(authURL)
+	assert.Nil(t, err)
+	idToken, err := p.Exchange(ctx, code, verifier)
+	assert.Nil(t, err)
+	_, err = p.Exchange(ctx, code, verifier)
+	assert.NotNil(t, err)
+
+	_, err = p.Verify(ctx, idToken, "other")
+	assert.True(t, errors.Is(err, ErrInvalidIDToken))
+	claims, err := p.Verify(ctx, idToken, "nonce")
+	assert.Nil(t, err)
+	assert.Equal(t, "42", claims.Subject)
+	assert.Equal(t, "user@corp.com", claims.Email)
+	assert.True(t, bool(claims.EmailVerified))
+	assert.Equal(t, "user", claims.PreferredUsername)
+}
+
+func TestVerify(t *testing.T) {
+	m, err := NewMockServer("pm", "secret")
+	assert.Nil(t, err)
+	defer m.Close()
+	ctx := context.Background()
+	p, err := Discover(ctx, m.Config("http://localhost/callback"))
+	assert.Nil(t, err)
+
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"iss": m.URL, "sub": "42", "aud": "pm", "exp": now.Add(time.Hour).Unix(), "nonce": "n"}
+	}
+	cases := map[string]func(jwt.MapClaims){
+		"valid":           func(c jwt.MapClaims) {},
+		"audience list":   func(c jwt.MapClaims) { c["aud"] = []string{"pm", "other"}; c["azp"] = "pm" },
+		"other issuer":    func(c jwt.MapClaims) { c["iss"] = "https://evil" },
+		"other audience":  func(c jwt.MapClaims) { c["aud"] = "other" },
+		"no azp":          func(c jwt.MapClaims) { c["aud"] = []string{"pm", "other"} },
+		"expired":         func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+		"no expiry":       func(c jwt.MapClaims) { delete(c, "exp") },
+		"no subject":      func(c jwt.MapClaims) { delete(c, "sub") },
+		"not valid yet":   func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Hour).Unix() },
+		"string verified": func(c jwt.MapClaims) { c["email_verified"] = "true" },
+	}
+	for name, change := range cases {
+		c := valid()
+		change(c)
+		token, err := m.SignIDToken(c)
+		assert.Nil(t, err)
+		_, err = p.Verify(ctx, token, "n")
+		ok := name == "valid" || name == "audience list" || name == "string verified"
+		assert.Equal(t, ok, err == nil, name)
+	}
+
+	// a token signed with a shared secret is rejected
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString([]byte("secret"))
+	assert.Nil(t, err)
+	_, err = p.Verify(ctx, token, "n")
+	assert.NotNil(t, err)
+}
+
+func TestBoolish(t *testing.T) {
+	var v struct {
+		B boolish `json:"b"`
+	}
+	for in, expected := range map[string]bool{`true`: true, `"true"`: true, `false`: false, `"false"`: false} {
+		assert.Nil(t, json.Unmarshal([]byte(`{"b":`+in+`}`), &v))
+		assert.Equal(t, expected, bool(v.B), in)
+	}
+	assert.NotNil(t, json.Unmarshal([]byte(`{"b":1}`), &v))
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	m, err := NewMockServer("pm", "secret")
+	assert.Nil(t, err)
+	defer m.Close()
+	config := m.Config("http://localhost/callback")
+	config.Issuer = m.URL + "/"
+	_, err = Discover(context.Background(), config)
+	assert.NotNil(t, err)
+}
@@ -16,6 +16,15 @@ func Get(key string, data interface{}) error {
 	return json.Unmarshal([]byte(val), data)
 }
 
+// Take get a data from session and remove it, so it can be taken only once
+func Take(key string, data interface{}) error {
+	val, err := kv.Get().Take(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(val), data)
+}
+
 // Set set new key/value into session data
 func Set(key string, data interface{}, duration time.Duration) error {
 	val, err := json.Marshal(data)
@@ -25,7 +34,48 @@ func Set(key string, data interface{}, duration time.Duration) error {
 	return kv.Get().Set(key, string(val), duration)
 }
 
+// SetNX set new key/value into session data only if the key is not set yet, returning true if it was set
+func SetNX(key string, data interface{}, duration time.Duration) (bool, error) {
+	val, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+	return kv.Get().SetNX(key, string(val), duration)
+}
+
+// Incr counts one more for a key, the count resets when the duration since its first count elapses
+func Incr(key string, duration time.Duration) (int64, error) {
+	return kv.Get().Incr(key, duration)
+}
+
 // Delete will remove a key from session
 func Delete(key string) error {
 	return kv.Get().Delete(key)
 }
+
+// TTL returns the remaining life of a key
+func TTL(key string) (time.Duration, error) {
+	return kv.Get().TTL(key)
+}
+
+// AddToIndex adds a member to the index of a key, which lives as long as its longest lived member
+func AddToIndex(key, member string, duration time.Duration) error {
+	ttl, err := kv.Get().TTL(key)
+	if err != nil {
+		return err
+	}
+	if ttl > duration {
+		duration = ttl
+	}
+	return kv.Get().AddMember(key, member, duration)
+}
+
+// Index returns the members of the index of a key
+func Index(key string) ([]string, error) {
+	return kv.Get().Members(key)
+}
+
+// RemoveFromIndex removes a member from the index of a key
+func RemoveFromIndex(key, member string) error {
+	return kv.Get().RemoveMember(key, member)
+}
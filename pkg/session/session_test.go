@@ -46,4 +46,44 @@ func TestSession(t *testing.T) {
 
 	err = Get("test1", &test1Val)
 	assert.NotNil(t, err)
+
+	ok, err := SetNX("test1", test1, time.Minute*1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = SetNX("test1", test{Username: "test2"}, time.Minute*1)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	err = Get("test1", &test1Val)
+	assert.Nil(t, err)
+	assert.Equal(t, test1, test1Val)
+}
+
+func TestTake(t *testing.T) {
+	assert.Nil(t, Set("take", "value", time.Minute*1))
+
+	var val string
+	assert.Nil(t, Take("take", &val))
+	assert.Equal(t, "value", val)
+	assert.NotNil(t, Take("take", &val))
+}
+
+func TestIndex(t *testing.T) {
+	assert.Nil(t, AddToIndex("index", "a", time.Minute*2))
+	assert.Nil(t, AddToIndex("index", "b", time.Minute*1))
+
+	// the index outlives its members
+	ttl, err := TTL("index")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute*2, ttl)
+
+	members, err := Index("index")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, members)
+
+	assert.Nil(t, RemoveFromIndex("index", "a"))
+	members, err = Index("index")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b"}, members)
 }
@@ -0,0 +1,475 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0-devel
+// 	protoc        v3.8.0
+// source: protobuf/acl/acl.proto
+
+package acl
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// ACLEntry gives the view or edit access of an object to a user or to the members with a role
+type ACLEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// subject_type is user or role
+	SubjectType string `protobuf:"bytes,1,opt,name=subject_type,json=subjectType,proto3" json:"subject_type,omitempty"`
+	SubjectUuid string `protobuf:"bytes,2,opt,name=subject_uuid,json=subjectUuid,proto3" json:"subject_uuid,omitempty"`
+	// access is view or edit
+	Access string `protobuf:"bytes,3,opt,name=access,proto3" json:"access,omitempty"`
+}
+
+func (x *ACLEntry) Reset() {
+	*x = ACLEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_acl_acl_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ACLEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACLEntry) ProtoMessage() {}
+
+func (x *ACLEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_acl_acl_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACLEntry.ProtoReflect.Descriptor instead.
+func (*ACLEntry) Descriptor() ([]byte, []int) {
+	return file_protobuf_acl_acl_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ACLEntry) GetSubjectType() string {
+	if x != nil {
+		return x.SubjectType
+	}
+	return ""
+}
+
+func (x *ACLEntry) GetSubjectUuid() string {
+	if x != nil {
+		return x.SubjectUuid
+	}
+	return ""
+}
+
+func (x *ACLEntry) GetAccess() string {
+	if x != nil {
+		return x.Access
+	}
+	return ""
+}
+
+type ObjectACL struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// object_type is issues or cycles
+	ObjectType string      `protobuf:"bytes,1,opt,name=object_type,json=objectType,proto3" json:"object_type,omitempty"`
+	ObjectUuid string      `protobuf:"bytes,2,opt,name=object_uuid,json=objectUuid,proto3" json:"object_uuid,omitempty"`
+	Entries    []*ACLEntry `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *ObjectACL) Reset() {
+	*x = ObjectACL{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_acl_acl_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObjectACL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectACL) ProtoMessage() {}
+
+func (x *ObjectACL) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_acl_acl_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectACL.ProtoReflect.Descriptor instead.
+func (*ObjectACL) Descriptor() ([]byte, []int) {
+	return file_protobuf_acl_acl_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ObjectACL) GetObjectType() string {
+	if x != nil {
+		return x.ObjectType
+	}
+	return ""
+}
+
+func (x *ObjectACL) GetObjectUuid() string {
+	if x != nil {
+		return x.ObjectUuid
+	}
+	return ""
+}
+
+func (x *ObjectACL) GetEntries() []*ACLEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetObjectACLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ObjectType string `protobuf:"bytes,1,opt,name=object_type,json=objectType,proto3" json:"object_type,omitempty"`
+	ObjectUuid string `protobuf:"bytes,2,opt,name=object_uuid,json=objectUuid,proto3" json:"object_uuid,omitempty"`
+}
+
+func (x *GetObjectACLRequest) Reset() {
+	*x = GetObjectACLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_acl_acl_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetObjectACLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetObjectACLRequest) ProtoMessage() {}
+
+func (x *GetObjectACLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_acl_acl_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetObjectACLRequest.ProtoReflect.Descriptor instead.
+func (*GetObjectACLRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_acl_acl_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetObjectACLRequest) GetObjectType() string {
+	if x != nil {
+		return x.ObjectType
+	}
+	return ""
+}
+
+func (x *GetObjectACLRequest) GetObjectUuid() string {
+	if x != nil {
+		return x.ObjectUuid
+	}
+	return ""
+}
+
+var File_protobuf_acl_acl_proto protoreflect.FileDescriptor
+
+var file_protobuf_acl_acl_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x63, 0x6c, 0x2f, 0x61,
+	0x63, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61, 0x63, 0x6c, 0x56, 0x31, 0x1a,
+	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x68, 0x0a,
+	0x08, 0x41, 0x43, 0x4c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x55, 0x75, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x78, 0x0a, 0x09, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x41, 0x43, 0x4c, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f,
+	0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x55, 0x75, 0x69, 0x64, 0x12, 0x29, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x63, 0x6c, 0x56, 0x31, 0x2e,
+	0x41, 0x43, 0x4c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x22, 0x57, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x41, 0x43,
+	0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x55, 0x75, 0x69, 0x64, 0x32, 0xdd, 0x01, 0x0a, 0x0a, 0x41,
+	0x43, 0x4c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x6a, 0x0a, 0x0c, 0x47, 0x65, 0x74,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x41, 0x43, 0x4c, 0x12, 0x1a, 0x2e, 0x61, 0x63, 0x6c, 0x56,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x41, 0x43, 0x4c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x63, 0x6c, 0x56, 0x31, 0x2e, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x41, 0x43, 0x4c, 0x22, 0x2c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x26, 0x12,
+	0x24, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x63, 0x6c, 0x73, 0x2f, 0x7b, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x7d, 0x2f, 0x7b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f,
+	0x75, 0x75, 0x69, 0x64, 0x7d, 0x12, 0x63, 0x0a, 0x0c, 0x53, 0x65, 0x74, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x41, 0x43, 0x4c, 0x12, 0x10, 0x2e, 0x61, 0x63, 0x6c, 0x56, 0x31, 0x2e, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x41, 0x43, 0x4c, 0x1a, 0x10, 0x2e, 0x61, 0x63, 0x6c, 0x56, 0x31, 0x2e,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x41, 0x43, 0x4c, 0x22, 0x2f, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x29, 0x1a, 0x24, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x63, 0x6c, 0x73, 0x2f, 0x7b, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x7d, 0x2f, 0x7b, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x3a, 0x01, 0x2a, 0x42, 0x12, 0x5a, 0x10, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x63, 0x6c, 0x3b, 0x61, 0x63, 0x6c, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_protobuf_acl_acl_proto_rawDescOnce sync.Once
+	file_protobuf_acl_acl_proto_rawDescData = file_protobuf_acl_acl_proto_rawDesc
+)
+
+func file_protobuf_acl_acl_proto_rawDescGZIP() []byte {
+	file_protobuf_acl_acl_proto_rawDescOnce.Do(func() {
+		file_protobuf_acl_acl_proto_rawDescData = protoimpl.X.CompressGZIP(file_protobuf_acl_acl_proto_rawDescData)
+	})
+	return file_protobuf_acl_acl_proto_rawDescData
+}
+
+var file_protobuf_acl_acl_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_protobuf_acl_acl_proto_goTypes = []interface{}{
+	(*ACLEntry)(nil),            // 0: aclV1.ACLEntry
+	(*ObjectACL)(nil),           // 1: aclV1.ObjectACL
+	(*GetObjectACLRequest)(nil), // 2: aclV1.GetObjectACLRequest
+}
+var file_protobuf_acl_acl_proto_depIdxs = []int32{
+	0, // 0: aclV1.ObjectACL.entries:type_name -> aclV1.ACLEntry
+	2, // 1: aclV1.ACLService.GetObjectACL:input_type -> aclV1.GetObjectACLRequest
+	1, // 2: aclV1.ACLService.SetObjectACL:input_type -> aclV1.ObjectACL
+	1, // 3: aclV1.ACLService.GetObjectACL:output_type -> aclV1.ObjectACL
+	1, // 4: aclV1.ACLService.SetObjectACL:output_type -> aclV1.ObjectACL
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_protobuf_acl_acl_proto_init() }
+func file_protobuf_acl_acl_proto_init() {
+	if File_protobuf_acl_acl_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_protobuf_acl_acl_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ACLEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_acl_acl_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObjectACL); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_acl_acl_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetObjectACLRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_protobuf_acl_acl_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_protobuf_acl_acl_proto_goTypes,
+		DependencyIndexes: file_protobuf_acl_acl_proto_depIdxs,
+		MessageInfos:      file_protobuf_acl_acl_proto_msgTypes,
+	}.Build()
+	File_protobuf_acl_acl_proto = out.File
+	file_protobuf_acl_acl_proto_rawDesc = nil
+	file_protobuf_acl_acl_proto_goTypes = nil
+	file_protobuf_acl_acl_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ACLServiceClient is the client API for ACLService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ACLServiceClient interface {
+	// Get the access control list of an object
+	GetObjectACL(ctx context.Context, in *GetObjectACLRequest, opts ...grpc.CallOption) (*ObjectACL, error)
+	// Replace the access control list of an object, an empty list removes its restrictions
+	SetObjectACL(ctx context.Context, in *ObjectACL, opts ...grpc.CallOption) (*ObjectACL, error)
+}
+
+type aCLServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewACLServiceClient(cc grpc.ClientConnInterface) ACLServiceClient {
+	return &aCLServiceClient{cc}
+}
+
+func (c *aCLServiceClient) GetObjectACL(ctx context.Context, in *GetObjectACLRequest, opts ...grpc.CallOption) (*ObjectACL, error) {
+	out := new(ObjectACL)
+	err := c.cc.Invoke(ctx, "/aclV1.ACLService/GetObjectACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aCLServiceClient) SetObjectACL(ctx context.Context, in *ObjectACL, opts ...grpc.CallOption) (*ObjectACL, error) {
+	out := new(ObjectACL)
+	err := c.cc.Invoke(ctx, "/aclV1.ACLService/SetObjectACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ACLServiceServer is the server API for ACLService service.
+type ACLServiceServer interface {
+	// Get the access control list of an object
+	GetObjectACL(context.Context, *GetObjectACLRequest) (*ObjectACL, error)
+	// Replace the access control list of an object, an empty list removes its restrictions
+	SetObjectACL(context.Context, *ObjectACL) (*ObjectACL, error)
+}
+
+// UnimplementedACLServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedACLServiceServer struct {
+}
+
+func (*UnimplementedACLServiceServer) GetObjectACL(context.Context, *GetObjectACLRequest) (*ObjectACL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetObjectACL not implemented")
+}
+func (*UnimplementedACLServiceServer) SetObjectACL(context.Context, *ObjectACL) (*ObjectACL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetObjectACL not implemented")
+}
+
+func RegisterACLServiceServer(s *grpc.Server, srv ACLServiceServer) {
+	s.RegisterService(&_ACLService_serviceDesc, srv)
+}
+
+func _ACLService_GetObjectACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetObjectACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ACLServiceServer).GetObjectACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/aclV1.ACLService/GetObjectACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ACLServiceServer).GetObjectACL(ctx, req.(*GetObjectACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ACLService_SetObjectACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObjectACL)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ACLServiceServer).SetObjectACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/aclV1.ACLService/SetObjectACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ACLServiceServer).SetObjectACL(ctx, req.(*ObjectACL))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ACLService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "aclV1.ACLService",
+	HandlerType: (*ACLServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetObjectACL",
+			Handler:    _ACLService_GetObjectACL_Handler,
+		},
+		{
+			MethodName: "SetObjectACL",
+			Handler:    _ACLService_SetObjectACL_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "protobuf/acl/acl.proto",
+}
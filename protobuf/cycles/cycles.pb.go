@@ -215,6 +215,7 @@ type CreateCycleRequest struct {
 	Description string               `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	StartAt     *timestamp.Timestamp `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
 	EndAt       *timestamp.Timestamp `protobuf:"bytes,5,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
+	Goals       []string             `protobuf:"bytes,6,rep,name=goals,proto3" json:"goals,omitempty"`
 }
 
 func (x *CreateCycleRequest) Reset() {
@@ -284,6 +285,13 @@ func (x *CreateCycleRequest) GetEndAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *CreateCycleRequest) GetGoals() []string {
+	if x != nil {
+		return x.Goals
+	}
+	return nil
+}
+
 type UpdateCycleRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -295,6 +303,7 @@ type UpdateCycleRequest struct {
 	Description string               `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	StartAt     *timestamp.Timestamp `protobuf:"bytes,5,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
 	EndAt       *timestamp.Timestamp `protobuf:"bytes,6,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
+	Goals       []string             `protobuf:"bytes,7,rep,name=goals,proto3" json:"goals,omitempty"`
 }
 
 func (x *UpdateCycleRequest) Reset() {
@@ -371,6 +380,13 @@ func (x *UpdateCycleRequest) GetEndAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *UpdateCycleRequest) GetGoals() []string {
+	if x != nil {
+		return x.Goals
+	}
+	return nil
+}
+
 type DeleteCycleRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -418,6 +434,273 @@ func (x *DeleteCycleRequest) GetUuid() string {
 	return ""
 }
 
+type SetCycleCapacityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CycleUuid string `protobuf:"bytes,1,opt,name=cycle_uuid,json=cycleUuid,proto3" json:"cycle_uuid,omitempty"`
+	UserUuid  string `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`
+	Capacity  uint64 `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Unit      string `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (x *SetCycleCapacityRequest) Reset() {
+	*x = SetCycleCapacityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_cycles_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetCycleCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCycleCapacityRequest) ProtoMessage() {}
+
+func (x *SetCycleCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_cycles_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCycleCapacityRequest.ProtoReflect.Descriptor instead.
+func (*SetCycleCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_cycles_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetCycleCapacityRequest) GetCycleUuid() string {
+	if x != nil {
+		return x.CycleUuid
+	}
+	return ""
+}
+
+func (x *SetCycleCapacityRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *SetCycleCapacityRequest) GetCapacity() uint64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *SetCycleCapacityRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type ListCycleCapacitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CycleUuid string `protobuf:"bytes,1,opt,name=cycle_uuid,json=cycleUuid,proto3" json:"cycle_uuid,omitempty"`
+}
+
+func (x *ListCycleCapacitiesRequest) Reset() {
+	*x = ListCycleCapacitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_cycles_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCycleCapacitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCycleCapacitiesRequest) ProtoMessage() {}
+
+func (x *ListCycleCapacitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_cycles_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCycleCapacitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListCycleCapacitiesRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_cycles_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListCycleCapacitiesRequest) GetCycleUuid() string {
+	if x != nil {
+		return x.CycleUuid
+	}
+	return ""
+}
+
+type ListCycleCapacitiesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Capacities []*CycleCapacity `protobuf:"bytes,1,rep,name=capacities,proto3" json:"capacities,omitempty"`
+}
+
+func (x *ListCycleCapacitiesResponse) Reset() {
+	*x = ListCycleCapacitiesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_cycles_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCycleCapacitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCycleCapacitiesResponse) ProtoMessage() {}
+
+func (x *ListCycleCapacitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_cycles_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCycleCapacitiesResponse.ProtoReflect.Descriptor instead.
+func (*ListCycleCapacitiesResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_cycles_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListCycleCapacitiesResponse) GetCapacities() []*CycleCapacity {
+	if x != nil {
+		return x.Capacities
+	}
+	return nil
+}
+
+type DeleteCycleCapacityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CycleUuid string `protobuf:"bytes,1,opt,name=cycle_uuid,json=cycleUuid,proto3" json:"cycle_uuid,omitempty"`
+	UserUuid  string `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`
+}
+
+func (x *DeleteCycleCapacityRequest) Reset() {
+	*x = DeleteCycleCapacityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_cycles_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteCycleCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCycleCapacityRequest) ProtoMessage() {}
+
+func (x *DeleteCycleCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_cycles_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCycleCapacityRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCycleCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_cycles_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteCycleCapacityRequest) GetCycleUuid() string {
+	if x != nil {
+		return x.CycleUuid
+	}
+	return ""
+}
+
+func (x *DeleteCycleCapacityRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+type GetCyclePlanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+}
+
+func (x *GetCyclePlanRequest) Reset() {
+	*x = GetCyclePlanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_cycles_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCyclePlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCyclePlanRequest) ProtoMessage() {}
+
+func (x *GetCyclePlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_cycles_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCyclePlanRequest.ProtoReflect.Descriptor instead.
+func (*GetCyclePlanRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_cycles_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCyclePlanRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
 var File_protobuf_cycles_cycles_proto protoreflect.FileDescriptor
 
 var file_protobuf_cycles_cycles_proto_rawDesc = []byte{
@@ -446,7 +729,7 @@ var file_protobuf_cycles_cycles_proto_rawDesc = []byte{
 	0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66,
 	0x73, 0x65, 0x74, 0x22, 0x25, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0xce, 0x01, 0x0a, 0x12, 0x43,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0xe4, 0x01, 0x0a, 0x12, 0x43,
 	0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76,
@@ -459,55 +742,117 @@ var file_protobuf_cycles_cycles_proto_rawDesc = []byte{
 	0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x74, 0x12, 0x31, 0x0a, 0x06, 0x65, 0x6e, 0x64, 0x5f,
 	0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
 	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x65, 0x6e, 0x64, 0x41, 0x74, 0x22, 0xe2, 0x01, 0x0a, 0x12,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x65, 0x6e, 0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x67,
+	0x6f, 0x61, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x67, 0x6f, 0x61, 0x6c,
+	0x73, 0x22, 0xf8, 0x01, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74,
+	0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x08,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x41, 0x74, 0x12, 0x31, 0x0a, 0x06, 0x65, 0x6e, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x05, 0x65, 0x6e, 0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x6f, 0x61, 0x6c, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x67, 0x6f, 0x61, 0x6c, 0x73, 0x22, 0x28, 0x0a, 0x12,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63,
-	0x74, 0x69, 0x76, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
-	0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x74, 0x12, 0x31, 0x0a,
-	0x06, 0x65, 0x6e, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x65, 0x6e, 0x64, 0x41, 0x74,
-	0x22, 0x28, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x32, 0xcf, 0x03, 0x0a, 0x0c, 0x43,
-	0x79, 0x63, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5b, 0x0a, 0x0a, 0x4c,
-	0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x63, 0x79, 0x63, 0x6c,
-	0x65, 0x73, 0x56, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56,
-	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a, 0x2f, 0x76,
-	0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x51, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x43,
-	0x79, 0x63, 0x6c, 0x65, 0x12, 0x19, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e,
-	0x47, 0x65, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x0f, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65,
-	0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79,
-	0x63, 0x6c, 0x65, 0x73, 0x2f, 0x7b, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x12, 0x53, 0x0a, 0x0b, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x63, 0x79, 0x63,
-	0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65,
-	0x73, 0x56, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02,
-	0x0f, 0x22, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x3a, 0x01, 0x2a,
-	0x12, 0x5a, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12,
-	0x1c, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e,
-	0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x22, 0x1c,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x1a, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c,
-	0x65, 0x73, 0x2f, 0x7b, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x5e, 0x0a, 0x0b,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x63, 0x79,
-	0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x79, 0x63,
-	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x2a, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x63,
-	0x79, 0x63, 0x6c, 0x65, 0x73, 0x2f, 0x7b, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x42, 0x18, 0x5a, 0x16,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x3b,
-	0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x85, 0x01, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x43, 0x79,
+	0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x55, 0x75, 0x69,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x55, 0x75, 0x69, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e,
+	0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0x3b,
+	0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x55, 0x75, 0x69, 0x64, 0x22, 0x56, 0x0a, 0x1b, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x63, 0x61,
+	0x70, 0x61, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x0a, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x22, 0x58, 0x0a, 0x1a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x79, 0x63,
+	0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x55, 0x75, 0x69, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x55, 0x75, 0x69, 0x64, 0x22, 0x29, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x32, 0xde, 0x07, 0x0a, 0x0c, 0x43, 0x79, 0x63,
+	0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5b, 0x0a, 0x0a, 0x4c, 0x69, 0x73,
+	0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73,
+	0x56, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a, 0x2f, 0x76, 0x31, 0x2f,
+	0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x51, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x43, 0x79, 0x63,
+	0x6c, 0x65, 0x12, 0x19, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e,
+	0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x22, 0x19,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c,
+	0x65, 0x73, 0x2f, 0x7b, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x12, 0x53, 0x0a, 0x0b, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65,
+	0x73, 0x56, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56,
+	0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x22,
+	0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x5a,
+	0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x1c, 0x2e,
+	0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
+	0x79, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x63, 0x79,
+	0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x22, 0x1c, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x16, 0x1a, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73,
+	0x2f, 0x7b, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x5e, 0x0a, 0x0b, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x63, 0x79, 0x63, 0x6c,
+	0x65, 0x73, 0x56, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x2a, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63,
+	0x6c, 0x65, 0x73, 0x2f, 0x7b, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x12, 0x89, 0x01, 0x0a, 0x10, 0x53,
+	0x65, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12,
+	0x21, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x43, 0x79,
+	0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x43, 0x79,
+	0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0x39, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x33, 0x1a, 0x2e, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x2f,
+	0x7b, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x2f, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x75, 0x75,
+	0x69, 0x64, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x8e, 0x01, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x24,
+	0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x79,
+	0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2a, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x24, 0x12, 0x22, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x2f,
+	0x7b, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x2f, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x8b, 0x01, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12,
+	0x24, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x36, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x30, 0x2a, 0x2e, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65,
+	0x73, 0x2f, 0x7b, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x7d, 0x2f, 0x63,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x75, 0x75, 0x69, 0x64, 0x7d, 0x12, 0x62, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x43, 0x79, 0x63, 0x6c,
+	0x65, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x1d, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e,
+	0x43, 0x79, 0x63, 0x6c, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x18, 0x12, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x2f, 0x7b, 0x75,
+	0x75, 0x69, 0x64, 0x7d, 0x2f, 0x70, 0x6c, 0x61, 0x6e, 0x42, 0x18, 0x5a, 0x16, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x3b, 0x63, 0x79, 0x63,
+	0x6c, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -522,39 +867,55 @@ func file_protobuf_cycles_cycles_proto_rawDescGZIP() []byte {
 	return file_protobuf_cycles_cycles_proto_rawDescData
 }
 
-var file_protobuf_cycles_cycles_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_protobuf_cycles_cycles_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_protobuf_cycles_cycles_proto_goTypes = []interface{}{
-	(*ListCyclesRequest)(nil),   // 0: cyclesV1.ListCyclesRequest
-	(*ListCyclesResponse)(nil),  // 1: cyclesV1.ListCyclesResponse
-	(*GetCycleRequest)(nil),     // 2: cyclesV1.GetCycleRequest
-	(*CreateCycleRequest)(nil),  // 3: cyclesV1.CreateCycleRequest
-	(*UpdateCycleRequest)(nil),  // 4: cyclesV1.UpdateCycleRequest
-	(*DeleteCycleRequest)(nil),  // 5: cyclesV1.DeleteCycleRequest
-	(*Cycle)(nil),               // 6: cyclesV1.Cycle
-	(*timestamp.Timestamp)(nil), // 7: google.protobuf.Timestamp
-	(*empty.Empty)(nil),         // 8: google.protobuf.Empty
+	(*ListCyclesRequest)(nil),           // 0: cyclesV1.ListCyclesRequest
+	(*ListCyclesResponse)(nil),          // 1: cyclesV1.ListCyclesResponse
+	(*GetCycleRequest)(nil),             // 2: cyclesV1.GetCycleRequest
+	(*CreateCycleRequest)(nil),          // 3: cyclesV1.CreateCycleRequest
+	(*UpdateCycleRequest)(nil),          // 4: cyclesV1.UpdateCycleRequest
+	(*DeleteCycleRequest)(nil),          // 5: cyclesV1.DeleteCycleRequest
+	(*SetCycleCapacityRequest)(nil),     // 6: cyclesV1.SetCycleCapacityRequest
+	(*ListCycleCapacitiesRequest)(nil),  // 7: cyclesV1.ListCycleCapacitiesRequest
+	(*ListCycleCapacitiesResponse)(nil), // 8: cyclesV1.ListCycleCapacitiesResponse
+	(*DeleteCycleCapacityRequest)(nil),  // 9: cyclesV1.DeleteCycleCapacityRequest
+	(*GetCyclePlanRequest)(nil),         // 10: cyclesV1.GetCyclePlanRequest
+	(*Cycle)(nil),                       // 11: cyclesV1.Cycle
+	(*timestamp.Timestamp)(nil),         // 12: google.protobuf.Timestamp
+	(*CycleCapacity)(nil),               // 13: cyclesV1.CycleCapacity
+	(*empty.Empty)(nil),                 // 14: google.protobuf.Empty
+	(*CyclePlan)(nil),                   // 15: cyclesV1.CyclePlan
 }
 var file_protobuf_cycles_cycles_proto_depIdxs = []int32{
-	6,  // 0: cyclesV1.ListCyclesResponse.cycles:type_name -> cyclesV1.Cycle
-	7,  // 1: cyclesV1.CreateCycleRequest.start_at:type_name -> google.protobuf.Timestamp
-	7,  // 2: cyclesV1.CreateCycleRequest.end_at:type_name -> google.protobuf.Timestamp
-	7,  // 3: cyclesV1.UpdateCycleRequest.start_at:type_name -> google.protobuf.Timestamp
-	7,  // 4: cyclesV1.UpdateCycleRequest.end_at:type_name -> google.protobuf.Timestamp
-	0,  // 5: cyclesV1.CycleService.ListCycles:input_type -> cyclesV1.ListCyclesRequest
-	2,  // 6: cyclesV1.CycleService.GetCycle:input_type -> cyclesV1.GetCycleRequest
-	3,  // 7: cyclesV1.CycleService.CreateCycle:input_type -> cyclesV1.CreateCycleRequest
-	4,  // 8: cyclesV1.CycleService.UpdateCycle:input_type -> cyclesV1.UpdateCycleRequest
-	5,  // 9: cyclesV1.CycleService.DeleteCycle:input_type -> cyclesV1.DeleteCycleRequest
-	1,  // 10: cyclesV1.CycleService.ListCycles:output_type -> cyclesV1.ListCyclesResponse
-	6,  // 11: cyclesV1.CycleService.GetCycle:output_type -> cyclesV1.Cycle
-	6,  // 12: cyclesV1.CycleService.CreateCycle:output_type -> cyclesV1.Cycle
-	6,  // 13: cyclesV1.CycleService.UpdateCycle:output_type -> cyclesV1.Cycle
-	8,  // 14: cyclesV1.CycleService.DeleteCycle:output_type -> google.protobuf.Empty
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	11, // 0: cyclesV1.ListCyclesResponse.cycles:type_name -> cyclesV1.Cycle
+	12, // 1: cyclesV1.CreateCycleRequest.start_at:type_name -> google.protobuf.Timestamp
+	12, // 2: cyclesV1.CreateCycleRequest.end_at:type_name -> google.protobuf.Timestamp
+	12, // 3: cyclesV1.UpdateCycleRequest.start_at:type_name -> google.protobuf.Timestamp
+	12, // 4: cyclesV1.UpdateCycleRequest.end_at:type_name -> google.protobuf.Timestamp
+	13, // 5: cyclesV1.ListCycleCapacitiesResponse.capacities:type_name -> cyclesV1.CycleCapacity
+	0,  // 6: cyclesV1.CycleService.ListCycles:input_type -> cyclesV1.ListCyclesRequest
+	2,  // 7: cyclesV1.CycleService.GetCycle:input_type -> cyclesV1.GetCycleRequest
+	3,  // 8: cyclesV1.CycleService.CreateCycle:input_type -> cyclesV1.CreateCycleRequest
+	4,  // 9: cyclesV1.CycleService.UpdateCycle:input_type -> cyclesV1.UpdateCycleRequest
+	5,  // 10: cyclesV1.CycleService.DeleteCycle:input_type -> cyclesV1.DeleteCycleRequest
+	6,  // 11: cyclesV1.CycleService.SetCycleCapacity:input_type -> cyclesV1.SetCycleCapacityRequest
+	7,  // 12: cyclesV1.CycleService.ListCycleCapacities:input_type -> cyclesV1.ListCycleCapacitiesRequest
+	9,  // 13: cyclesV1.CycleService.DeleteCycleCapacity:input_type -> cyclesV1.DeleteCycleCapacityRequest
+	10, // 14: cyclesV1.CycleService.GetCyclePlan:input_type -> cyclesV1.GetCyclePlanRequest
+	1,  // 15: cyclesV1.CycleService.ListCycles:output_type -> cyclesV1.ListCyclesResponse
+	11, // 16: cyclesV1.CycleService.GetCycle:output_type -> cyclesV1.Cycle
+	11, // 17: cyclesV1.CycleService.CreateCycle:output_type -> cyclesV1.Cycle
+	11, // 18: cyclesV1.CycleService.UpdateCycle:output_type -> cyclesV1.Cycle
+	14, // 19: cyclesV1.CycleService.DeleteCycle:output_type -> google.protobuf.Empty
+	13, // 20: cyclesV1.CycleService.SetCycleCapacity:output_type -> cyclesV1.CycleCapacity
+	8,  // 21: cyclesV1.CycleService.ListCycleCapacities:output_type -> cyclesV1.ListCycleCapacitiesResponse
+	14, // 22: cyclesV1.CycleService.DeleteCycleCapacity:output_type -> google.protobuf.Empty
+	15, // 23: cyclesV1.CycleService.GetCyclePlan:output_type -> cyclesV1.CyclePlan
+	15, // [15:24] is the sub-list for method output_type
+	6,  // [6:15] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_protobuf_cycles_cycles_proto_init() }
@@ -636,6 +997,66 @@ func file_protobuf_cycles_cycles_proto_init() {
 				return nil
 			}
 		}
+		file_protobuf_cycles_cycles_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetCycleCapacityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_cycles_cycles_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCycleCapacitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_cycles_cycles_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCycleCapacitiesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_cycles_cycles_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteCycleCapacityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_cycles_cycles_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCyclePlanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -643,7 +1064,7 @@ func file_protobuf_cycles_cycles_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protobuf_cycles_cycles_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -679,6 +1100,14 @@ type CycleServiceClient interface {
 	UpdateCycle(ctx context.Context, in *UpdateCycleRequest, opts ...grpc.CallOption) (*Cycle, error)
 	// Delete Cycle object request
 	DeleteCycle(ctx context.Context, in *DeleteCycleRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Set Cycle Capacity sets the availability of a user in the cycle
+	SetCycleCapacity(ctx context.Context, in *SetCycleCapacityRequest, opts ...grpc.CallOption) (*CycleCapacity, error)
+	// List Cycle Capacities
+	ListCycleCapacities(ctx context.Context, in *ListCycleCapacitiesRequest, opts ...grpc.CallOption) (*ListCycleCapacitiesResponse, error)
+	// Delete Cycle Capacity removes the availability of a user in the cycle
+	DeleteCycleCapacity(ctx context.Context, in *DeleteCycleCapacityRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Get Cycle Plan compares assigned estimates against capacity per assignee
+	GetCyclePlan(ctx context.Context, in *GetCyclePlanRequest, opts ...grpc.CallOption) (*CyclePlan, error)
 }
 
 type cycleServiceClient struct {
@@ -734,6 +1163,42 @@ func (c *cycleServiceClient) DeleteCycle(ctx context.Context, in *DeleteCycleReq
 	return out, nil
 }
 
+func (c *cycleServiceClient) SetCycleCapacity(ctx context.Context, in *SetCycleCapacityRequest, opts ...grpc.CallOption) (*CycleCapacity, error) {
+	out := new(CycleCapacity)
+	err := c.cc.Invoke(ctx, "/cyclesV1.CycleService/SetCycleCapacity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cycleServiceClient) ListCycleCapacities(ctx context.Context, in *ListCycleCapacitiesRequest, opts ...grpc.CallOption) (*ListCycleCapacitiesResponse, error) {
+	out := new(ListCycleCapacitiesResponse)
+	err := c.cc.Invoke(ctx, "/cyclesV1.CycleService/ListCycleCapacities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cycleServiceClient) DeleteCycleCapacity(ctx context.Context, in *DeleteCycleCapacityRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cyclesV1.CycleService/DeleteCycleCapacity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cycleServiceClient) GetCyclePlan(ctx context.Context, in *GetCyclePlanRequest, opts ...grpc.CallOption) (*CyclePlan, error) {
+	out := new(CyclePlan)
+	err := c.cc.Invoke(ctx, "/cyclesV1.CycleService/GetCyclePlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CycleServiceServer is the server API for CycleService service.
 type CycleServiceServer interface {
 	// List Cycles
@@ -746,6 +1211,14 @@ type CycleServiceServer interface {
 	UpdateCycle(context.Context, *UpdateCycleRequest) (*Cycle, error)
 	// Delete Cycle object request
 	DeleteCycle(context.Context, *DeleteCycleRequest) (*empty.Empty, error)
+	// Set Cycle Capacity sets the availability of a user in the cycle
+	SetCycleCapacity(context.Context, *SetCycleCapacityRequest) (*CycleCapacity, error)
+	// List Cycle Capacities
+	ListCycleCapacities(context.Context, *ListCycleCapacitiesRequest) (*ListCycleCapacitiesResponse, error)
+	// Delete Cycle Capacity removes the availability of a user in the cycle
+	DeleteCycleCapacity(context.Context, *DeleteCycleCapacityRequest) (*empty.Empty, error)
+	// Get Cycle Plan compares assigned estimates against capacity per assignee
+	GetCyclePlan(context.Context, *GetCyclePlanRequest) (*CyclePlan, error)
 }
 
 // UnimplementedCycleServiceServer can be embedded to have forward compatible implementations.
@@ -767,6 +1240,18 @@ func (*UnimplementedCycleServiceServer) UpdateCycle(context.Context, *UpdateCycl
 func (*UnimplementedCycleServiceServer) DeleteCycle(context.Context, *DeleteCycleRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCycle not implemented")
 }
+func (*UnimplementedCycleServiceServer) SetCycleCapacity(context.Context, *SetCycleCapacityRequest) (*CycleCapacity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCycleCapacity not implemented")
+}
+func (*UnimplementedCycleServiceServer) ListCycleCapacities(context.Context, *ListCycleCapacitiesRequest) (*ListCycleCapacitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCycleCapacities not implemented")
+}
+func (*UnimplementedCycleServiceServer) DeleteCycleCapacity(context.Context, *DeleteCycleCapacityRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCycleCapacity not implemented")
+}
+func (*UnimplementedCycleServiceServer) GetCyclePlan(context.Context, *GetCyclePlanRequest) (*CyclePlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCyclePlan not implemented")
+}
 
 func RegisterCycleServiceServer(s *grpc.Server, srv CycleServiceServer) {
 	s.RegisterService(&_CycleService_serviceDesc, srv)
@@ -862,6 +1347,78 @@ func _CycleService_DeleteCycle_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CycleService_SetCycleCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCycleCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CycleServiceServer).SetCycleCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cyclesV1.CycleService/SetCycleCapacity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CycleServiceServer).SetCycleCapacity(ctx, req.(*SetCycleCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CycleService_ListCycleCapacities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCycleCapacitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CycleServiceServer).ListCycleCapacities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cyclesV1.CycleService/ListCycleCapacities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CycleServiceServer).ListCycleCapacities(ctx, req.(*ListCycleCapacitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CycleService_DeleteCycleCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCycleCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CycleServiceServer).DeleteCycleCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cyclesV1.CycleService/DeleteCycleCapacity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CycleServiceServer).DeleteCycleCapacity(ctx, req.(*DeleteCycleCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CycleService_GetCyclePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCyclePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CycleServiceServer).GetCyclePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cyclesV1.CycleService/GetCyclePlan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CycleServiceServer).GetCyclePlan(ctx, req.(*GetCyclePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CycleService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "cyclesV1.CycleService",
 	HandlerType: (*CycleServiceServer)(nil),
@@ -886,6 +1443,22 @@ var _CycleService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCycle",
 			Handler:    _CycleService_DeleteCycle_Handler,
 		},
+		{
+			MethodName: "SetCycleCapacity",
+			Handler:    _CycleService_SetCycleCapacity_Handler,
+		},
+		{
+			MethodName: "ListCycleCapacities",
+			Handler:    _CycleService_ListCycleCapacities_Handler,
+		},
+		{
+			MethodName: "DeleteCycleCapacity",
+			Handler:    _CycleService_DeleteCycleCapacity_Handler,
+		},
+		{
+			MethodName: "GetCyclePlan",
+			Handler:    _CycleService_GetCyclePlan_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "protobuf/cycles/cycles.proto",
@@ -42,6 +42,7 @@ type Cycle struct {
 	EndAt       *timestamp.Timestamp `protobuf:"bytes,8,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
 	CreatedAt   *timestamp.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt   *timestamp.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Goals       []string             `protobuf:"bytes,11,rep,name=goals,proto3" json:"goals,omitempty"`
 }
 
 func (x *Cycle) Reset() {
@@ -146,6 +147,291 @@ func (x *Cycle) GetUpdatedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *Cycle) GetGoals() []string {
+	if x != nil {
+		return x.Goals
+	}
+	return nil
+}
+
+type CycleCapacity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CycleUuid string               `protobuf:"bytes,1,opt,name=cycle_uuid,json=cycleUuid,proto3" json:"cycle_uuid,omitempty"`
+	User      *users.User          `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Capacity  uint64               `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Unit      string               `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *CycleCapacity) Reset() {
+	*x = CycleCapacity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_model_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CycleCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CycleCapacity) ProtoMessage() {}
+
+func (x *CycleCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_model_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CycleCapacity.ProtoReflect.Descriptor instead.
+func (*CycleCapacity) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_model_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CycleCapacity) GetCycleUuid() string {
+	if x != nil {
+		return x.CycleUuid
+	}
+	return ""
+}
+
+func (x *CycleCapacity) GetUser() *users.User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *CycleCapacity) GetCapacity() uint64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *CycleCapacity) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *CycleCapacity) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CycleCapacity) GetUpdatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type AssigneePlan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User          *users.User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Capacity      uint64      `protobuf:"varint,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Unit          string      `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	Assigned      uint64      `protobuf:"varint,4,opt,name=assigned,proto3" json:"assigned,omitempty"`
+	IssuesCount   int64       `protobuf:"varint,5,opt,name=issues_count,json=issuesCount,proto3" json:"issues_count,omitempty"`
+	Remaining     int64       `protobuf:"varint,6,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Overcommitted bool        `protobuf:"varint,7,opt,name=overcommitted,proto3" json:"overcommitted,omitempty"`
+}
+
+func (x *AssigneePlan) Reset() {
+	*x = AssigneePlan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_model_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssigneePlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssigneePlan) ProtoMessage() {}
+
+func (x *AssigneePlan) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_model_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssigneePlan.ProtoReflect.Descriptor instead.
+func (*AssigneePlan) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_model_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AssigneePlan) GetUser() *users.User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *AssigneePlan) GetCapacity() uint64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *AssigneePlan) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *AssigneePlan) GetAssigned() uint64 {
+	if x != nil {
+		return x.Assigned
+	}
+	return 0
+}
+
+func (x *AssigneePlan) GetIssuesCount() int64 {
+	if x != nil {
+		return x.IssuesCount
+	}
+	return 0
+}
+
+func (x *AssigneePlan) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *AssigneePlan) GetOvercommitted() bool {
+	if x != nil {
+		return x.Overcommitted
+	}
+	return false
+}
+
+type CyclePlan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CycleUuid     string          `protobuf:"bytes,1,opt,name=cycle_uuid,json=cycleUuid,proto3" json:"cycle_uuid,omitempty"`
+	Assignees     []*AssigneePlan `protobuf:"bytes,2,rep,name=assignees,proto3" json:"assignees,omitempty"`
+	TotalCapacity uint64          `protobuf:"varint,3,opt,name=total_capacity,json=totalCapacity,proto3" json:"total_capacity,omitempty"`
+	TotalAssigned uint64          `protobuf:"varint,4,opt,name=total_assigned,json=totalAssigned,proto3" json:"total_assigned,omitempty"`
+	Unassigned    uint64          `protobuf:"varint,5,opt,name=unassigned,proto3" json:"unassigned,omitempty"`
+	Overcommitted bool            `protobuf:"varint,6,opt,name=overcommitted,proto3" json:"overcommitted,omitempty"`
+	// unit of the capacities, all the capacities of a cycle are in the same unit
+	Unit string `protobuf:"bytes,7,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (x *CyclePlan) Reset() {
+	*x = CyclePlan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_cycles_model_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CyclePlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CyclePlan) ProtoMessage() {}
+
+func (x *CyclePlan) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_cycles_model_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CyclePlan.ProtoReflect.Descriptor instead.
+func (*CyclePlan) Descriptor() ([]byte, []int) {
+	return file_protobuf_cycles_model_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CyclePlan) GetCycleUuid() string {
+	if x != nil {
+		return x.CycleUuid
+	}
+	return ""
+}
+
+func (x *CyclePlan) GetAssignees() []*AssigneePlan {
+	if x != nil {
+		return x.Assignees
+	}
+	return nil
+}
+
+func (x *CyclePlan) GetTotalCapacity() uint64 {
+	if x != nil {
+		return x.TotalCapacity
+	}
+	return 0
+}
+
+func (x *CyclePlan) GetTotalAssigned() uint64 {
+	if x != nil {
+		return x.TotalAssigned
+	}
+	return 0
+}
+
+func (x *CyclePlan) GetUnassigned() uint64 {
+	if x != nil {
+		return x.Unassigned
+	}
+	return 0
+}
+
+func (x *CyclePlan) GetOvercommitted() bool {
+	if x != nil {
+		return x.Overcommitted
+	}
+	return false
+}
+
+func (x *CyclePlan) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
 var File_protobuf_cycles_model_proto protoreflect.FileDescriptor
 
 var file_protobuf_cycles_model_proto_rawDesc = []byte{
@@ -155,7 +441,7 @@ var file_protobuf_cycles_model_proto_rawDesc = []byte{
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
 	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1a, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
 	0x75, 0x66, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84, 0x03, 0x0a, 0x05, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x0e,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9a, 0x03, 0x0a, 0x05, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x0e,
 	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
 	0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75,
 	0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
@@ -179,9 +465,57 @@ var file_protobuf_cycles_model_proto_rawDesc = []byte{
 	0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0a,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x42, 0x18, 0x5a, 0x16, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x3b, 0x63,
-	0x79, 0x63, 0x6c, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x67,
+	0x6f, 0x61, 0x6c, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x67, 0x6f, 0x61, 0x6c,
+	0x73, 0x22, 0xf7, 0x01, 0x0a, 0x0d, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63,
+	0x69, 0x74, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x55, 0x75,
+	0x69, 0x64, 0x12, 0x21, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x56, 0x31, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x75, 0x6e, 0x69, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xe4, 0x01, 0x0a, 0x0c,
+	0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x21, 0x0a, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x73, 0x56, 0x31, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12,
+	0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x6e, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x69,
+	0x73, 0x73, 0x75, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x69, 0x73, 0x73, 0x75, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x24, 0x0a, 0x0d,
+	0x6f, 0x76, 0x65, 0x72, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0d, 0x6f, 0x76, 0x65, 0x72, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74,
+	0x65, 0x64, 0x22, 0x88, 0x02, 0x0a, 0x09, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x50, 0x6c, 0x61, 0x6e,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x55, 0x75, 0x69, 0x64, 0x12,
+	0x34, 0x0a, 0x09, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x56, 0x31, 0x2e, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x09, 0x61, 0x73, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x25, 0x0a, 0x0e,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x73, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x75, 0x6e, 0x61, 0x73, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x6f, 0x76, 0x65, 0x72, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x6f, 0x76, 0x65, 0x72,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x42, 0x18, 0x5a,
+	0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73,
+	0x3b, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -196,23 +530,31 @@ func file_protobuf_cycles_model_proto_rawDescGZIP() []byte {
 	return file_protobuf_cycles_model_proto_rawDescData
 }
 
-var file_protobuf_cycles_model_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protobuf_cycles_model_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_protobuf_cycles_model_proto_goTypes = []interface{}{
 	(*Cycle)(nil),               // 0: cyclesV1.Cycle
-	(*users.User)(nil),          // 1: usersV1.User
-	(*timestamp.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*CycleCapacity)(nil),       // 1: cyclesV1.CycleCapacity
+	(*AssigneePlan)(nil),        // 2: cyclesV1.AssigneePlan
+	(*CyclePlan)(nil),           // 3: cyclesV1.CyclePlan
+	(*users.User)(nil),          // 4: usersV1.User
+	(*timestamp.Timestamp)(nil), // 5: google.protobuf.Timestamp
 }
 var file_protobuf_cycles_model_proto_depIdxs = []int32{
-	1, // 0: cyclesV1.Cycle.creator:type_name -> usersV1.User
-	2, // 1: cyclesV1.Cycle.start_at:type_name -> google.protobuf.Timestamp
-	2, // 2: cyclesV1.Cycle.end_at:type_name -> google.protobuf.Timestamp
-	2, // 3: cyclesV1.Cycle.created_at:type_name -> google.protobuf.Timestamp
-	2, // 4: cyclesV1.Cycle.updated_at:type_name -> google.protobuf.Timestamp
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	4,  // 0: cyclesV1.Cycle.creator:type_name -> usersV1.User
+	5,  // 1: cyclesV1.Cycle.start_at:type_name -> google.protobuf.Timestamp
+	5,  // 2: cyclesV1.Cycle.end_at:type_name -> google.protobuf.Timestamp
+	5,  // 3: cyclesV1.Cycle.created_at:type_name -> google.protobuf.Timestamp
+	5,  // 4: cyclesV1.Cycle.updated_at:type_name -> google.protobuf.Timestamp
+	4,  // 5: cyclesV1.CycleCapacity.user:type_name -> usersV1.User
+	5,  // 6: cyclesV1.CycleCapacity.created_at:type_name -> google.protobuf.Timestamp
+	5,  // 7: cyclesV1.CycleCapacity.updated_at:type_name -> google.protobuf.Timestamp
+	4,  // 8: cyclesV1.AssigneePlan.user:type_name -> usersV1.User
+	2,  // 9: cyclesV1.CyclePlan.assignees:type_name -> cyclesV1.AssigneePlan
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_protobuf_cycles_model_proto_init() }
@@ -233,6 +575,42 @@ func file_protobuf_cycles_model_proto_init() {
 				return nil
 			}
 		}
+		file_protobuf_cycles_model_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CycleCapacity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_cycles_model_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssigneePlan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_cycles_model_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CyclePlan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -240,7 +618,7 @@ func file_protobuf_cycles_model_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protobuf_cycles_model_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},